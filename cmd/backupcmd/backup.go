@@ -0,0 +1,63 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backupcmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app            *application.Avalanche
+	cliVersion     string
+	passphraseFile string
+)
+
+func NewCmd(injectedApp *application.Avalanche, version string) *cobra.Command {
+	app = injectedApp
+	cliVersion = version
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Backup and restore the Avalanche-CLI state",
+		Long: `The backup command suite saves and restores the Avalanche-CLI state kept
+at ~/.avalanche-cli: subnet sidecars and configs, stored keys, clusters config,
+node staking certs, ansible inventories and relayer configs.
+
+Losing this state means losing control of cloud validators, so keep backups
+somewhere safe. Binaries, logs and local network snapshots are not included.`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+
+	// avalanche backup create
+	cmd.AddCommand(newCreateCmd())
+
+	// avalanche backup restore
+	cmd.AddCommand(newRestoreCmd())
+
+	return cmd
+}
+
+func readPassphrase() (string, error) {
+	if passphraseFile == "" {
+		return "", nil
+	}
+	bs, err := os.ReadFile(passphraseFile)
+	if err != nil {
+		return "", fmt.Errorf("failed reading passphrase file: %w", err)
+	}
+	passphrase := strings.TrimSpace(string(bs))
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase file %s is empty", passphraseFile)
+	}
+	return passphrase, nil
+}
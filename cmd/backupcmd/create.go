@@ -0,0 +1,68 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backupcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/backup"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outputPath     string
+	excludeSecrets bool
+)
+
+// avalanche backup create
+func newCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a backup archive of the Avalanche-CLI state",
+		Long: `The backup create command writes the Avalanche-CLI state into a tar.gz archive
+that contains a manifest with the state versions and file checksums.
+
+The archive holds subnet configs, keys, node and cluster configs, and custom
+VM binaries, so it grows by the size of each custom VM binary. Avalanchego and
+plugin binaries, logs and snapshots are left out, as they are downloaded or
+recreated when needed. When a project dir is in use, its subnets and custom
+VMs are archived too.
+
+If --passphrase-file is given, the archive is encrypted with a key derived
+from the passphrase. Use --exclude-secrets to leave stored keys and node
+staking keys out of the archive.`,
+		Args:         cobra.ExactArgs(0),
+		RunE:         createBackup,
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "write the backup to the given file path")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "encrypt the backup with the passphrase contained in the given file")
+	cmd.Flags().BoolVar(&excludeSecrets, "exclude-secrets", false, "do not include stored keys and node staking keys")
+	return cmd
+}
+
+func createBackup(_ *cobra.Command, _ []string) error {
+	passphrase, err := readPassphrase()
+	if err != nil {
+		return err
+	}
+	if outputPath == "" {
+		outputPath = backup.DefaultFileName()
+	}
+	manifest, err := backup.Create(app.GetBaseDir(), outputPath, backup.CreateOptions{
+		CLIVersion:     cliVersion,
+		ExcludeSecrets: excludeSecrets,
+		Passphrase:     passphrase,
+		ProjectDir:     app.GetProjectDir(),
+	})
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Backup of %d files written to %s", len(manifest.Files), outputPath)
+	if manifest.ProjectDir != "" {
+		ux.Logger.PrintToUser("The backup includes the project dir %s", manifest.ProjectDir)
+	}
+	if !manifest.Encrypted && !manifest.ExcludesSecrets {
+		ux.Logger.PrintToUser("The backup is not encrypted and contains private keys. Store it safely")
+	}
+	return nil
+}
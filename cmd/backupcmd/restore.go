@@ -0,0 +1,66 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backupcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/internal/migrations"
+	"github.com/ava-labs/avalanche-cli/pkg/backup"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var force bool
+
+// avalanche backup restore
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [backupFile]",
+		Short: "Restore the Avalanche-CLI state from a backup archive",
+		Long: `The backup restore command restores the Avalanche-CLI state from an archive
+created with backup create, and then applies any pending migrations.
+
+The restore is refused if the archive was created by a newer version of
+Avalanche-CLI, or if it would overwrite files that were modified after the
+backup was taken. Use --force to overwrite them anyway.
+
+The project files of the backup are restored into the project dir in use, or
+into the project dir they were taken from if there is none.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         restoreBackup,
+		SilenceUsage: true,
+		// the current state is replaced, so it is pointless to migrate it,
+		// and a failing migration must not prevent restoring a backup
+		Annotations: map[string]string{migrations.SkipAnnotation: "true"},
+	}
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "decrypt the backup with the passphrase contained in the given file")
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite files modified after the backup was taken")
+	return cmd
+}
+
+func restoreBackup(_ *cobra.Command, args []string) error {
+	passphrase, err := readPassphrase()
+	if err != nil {
+		return err
+	}
+	manifest, err := backup.Restore(args[0], app.GetBaseDir(), backup.RestoreOptions{
+		Passphrase: passphrase,
+		Force:      force,
+		ProjectDir: app.GetProjectDir(),
+	})
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Restored %d files from backup created at %s", len(manifest.Files), manifest.CreatedAt.Local().Format(constants.TimeParseLayout))
+	if manifest.HasProjectFiles() {
+		projectDir := app.GetProjectDir()
+		if projectDir == "" {
+			projectDir = manifest.ProjectDir
+		}
+		ux.Logger.PrintToUser("Project subnets and custom VMs restored into %s", projectDir)
+	}
+	if manifest.ExcludesSecrets {
+		ux.Logger.PrintToUser("The backup did not include secrets. Stored keys and node staking keys must be restored separately")
+	}
+	return migrations.RunMigrations(app)
+}
@@ -30,5 +30,6 @@ func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
 	cmd.AddCommand(newMigrateCmd())
 	cmd.AddCommand(newSingleNodeCmd())
 	cmd.AddCommand(newAuthorizeCloudAccessCmd())
+	cmd.AddCommand(newProjectCmd())
 	return cmd
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ava-labs/avalanche-cli/internal/migrations"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -13,21 +14,67 @@ import (
 	"github.com/spf13/viper"
 )
 
-var MigrateOutput string
+var (
+	MigrateOutput string
+	migrateDryRun bool
+)
 
 // avalanche config metrics migrate
 func newMigrateCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "migrate",
-		Short:        "migrate ~/.avalanche-cli.json and ~/.avalanche-cli/config to new configuration location ~/.avalanche-cli/config.json",
-		Long:         `migrate command migrates old ~/.avalanche-cli.json and ~/.avalanche-cli/config to /.avalanche-cli/config.json..`,
-		RunE:         migrateConfig,
+		Use:   "migrate",
+		Short: "migrate ~/.avalanche-cli.json and ~/.avalanche-cli/config to new configuration location ~/.avalanche-cli/config.json",
+		Long: `migrate command migrates old ~/.avalanche-cli.json and ~/.avalanche-cli/config to /.avalanche-cli/config.json..
+
+It also applies any pending migration of the Avalanche-CLI state. A snapshot
+of the state is taken before migrating, and restored if a migration fails.
+Use --dry-run to show the pending changes without applying them.`,
+		RunE:         migrate,
 		SilenceUsage: true,
+		// migrations are applied by the command itself, and must not be
+		// applied in advance when asked for a dry-run
+		Annotations: map[string]string{migrations.SkipAnnotation: "true"},
 	}
+	cmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "show pending migrations without applying them")
 	return cmd
 }
 
-func migrateConfig(_ *cobra.Command, _ []string) error {
+func migrate(_ *cobra.Command, _ []string) error {
+	if migrateDryRun {
+		return showPendingMigrations()
+	}
+	if err := migrateConfig(); err != nil {
+		return err
+	}
+	return migrations.RunMigrations(app)
+}
+
+func showPendingMigrations() error {
+	oldConfigFilename := utils.UserHomePath(constants.OldConfigFileName)
+	oldMetricsConfigFilename := utils.UserHomePath(constants.OldMetricsConfigFileName)
+	if !utils.FileExists(app.Conf.GetConfigPath()) {
+		for _, oldFilename := range []string{oldConfigFilename, oldMetricsConfigFilename} {
+			if utils.FileExists(oldFilename) {
+				ux.Logger.PrintToUser("- move configuration file %s into %s", oldFilename, app.Conf.GetConfigPath())
+			}
+		}
+	}
+	pending, err := migrations.PendingMigrations(app)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		ux.Logger.PrintToUser("No pending migrations for the Avalanche-CLI state")
+		return nil
+	}
+	ux.Logger.PrintToUser("Pending migrations:")
+	for _, change := range pending {
+		ux.Logger.PrintToUser("- #%d: %s", change.Migration, change.Description)
+	}
+	return nil
+}
+
+func migrateConfig() error {
 	oldConfigFilename := utils.UserHomePath(constants.OldConfigFileName)
 	oldMetricsConfigFilename := utils.UserHomePath(constants.OldMetricsConfigFileName)
 	configFileName := app.Conf.GetConfigPath()
@@ -0,0 +1,81 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+const (
+	projectInit = "init"
+	projectShow = "show"
+)
+
+// avalanche config project command
+func newProjectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project [init | show]",
+		Short: "manage the project dir used to keep subnet configs in a repository",
+		Long: `The project command manages project mode.
+
+When a ` + constants.ProjectDirName + ` directory is found in the working directory or any
+of its parents, Avalanche-CLI keeps subnet sidecars, genesis files, upgrade
+bytes and custom VM binaries in it instead of the global ~/` + constants.BaseDirName + ` dir.
+Keys, nodes and the other binaries stay global. init keeps the custom VM
+binaries out of git, as they are rebuilt or re-imported on each machine.
+
+init creates a project dir in the working directory.
+show prints the project dir currently in use.`,
+		RunE:         handleProjectSettings,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+
+	return cmd
+}
+
+func handleProjectSettings(_ *cobra.Command, args []string) error {
+	switch args[0] {
+	case projectInit:
+		return initProject()
+	case projectShow:
+		if app.GetProjectDir() == "" {
+			ux.Logger.PrintToUser("No project dir in use. Subnet configs are kept at %s", app.GetSubnetDir())
+			return nil
+		}
+		ux.Logger.PrintToUser("Using project dir %s", app.GetProjectDir())
+		return nil
+	default:
+		return errors.New("Invalid argument '" + args[0] + "'")
+	}
+}
+
+func initProject() error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	projectDir := filepath.Join(wd, constants.ProjectDirName)
+	if utils.DirectoryExists(projectDir) {
+		ux.Logger.PrintToUser("Project dir %s already exists", projectDir)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Join(projectDir, constants.SubnetDir), constants.DefaultPerms755); err != nil {
+		return fmt.Errorf("failed creating project dir %s: %w", projectDir, err)
+	}
+	gitignorePath := filepath.Join(projectDir, ".gitignore")
+	gitignore := []byte("/" + constants.CustomVMDir + "/\n")
+	if err := os.WriteFile(gitignorePath, gitignore, constants.WriteReadReadPerms); err != nil {
+		return fmt.Errorf("failed writing %s: %w", gitignorePath, err)
+	}
+	ux.Logger.PrintToUser("Project dir %s created. Subnets created from here on will be kept in it", projectDir)
+	return nil
+}
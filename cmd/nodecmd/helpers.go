@@ -199,6 +199,8 @@ func checkHostsAreRPCCompatible(hosts []*models.Host, subnetName string) error {
 			ux.Logger.PrintToUser("To modify your Subnet-EVM version: https://docs.avax.network/build/subnet/upgrade/upgrade-subnet-vm")
 		case models.CustomVM:
 			ux.Logger.PrintToUser("To modify your Custom VM binary: avalanche subnet upgrade vm %s --config", subnetName)
+		default:
+			ux.Logger.PrintToUser("To modify your %s version: avalanche subnet upgrade vm %s --config", sc.VM, subnetName)
 		}
 		ux.Logger.PrintToUser("Yoy can use \"avalanche node upgrade\" to upgrade Avalanche Go and/or Subnet-EVM to their latest versions")
 		return fmt.Errorf("the Avalanche Go version of node(s) %s is incompatible with VM RPC version of %s", incompatibleNodes, subnetName)
@@ -99,33 +99,9 @@ func statusNode(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	ux.Logger.PrintToUser("Getting avalanchego version of node(s)...")
-
-	wg := sync.WaitGroup{}
-	wgResults := models.NodeResults{}
-	for _, host := range hosts {
-		wg.Add(1)
-		go func(nodeResults *models.NodeResults, host *models.Host) {
-			defer wg.Done()
-			if resp, err := ssh.RunSSHCheckAvalancheGoVersion(host); err != nil {
-				nodeResults.AddResult(host.GetCloudID(), nil, err)
-				return
-			} else {
-				if avalancheGoVersion, _, err := parseAvalancheGoOutput(resp); err != nil {
-					nodeResults.AddResult(host.GetCloudID(), nil, err)
-				} else {
-					nodeResults.AddResult(host.GetCloudID(), avalancheGoVersion, err)
-				}
-			}
-		}(&wgResults, host)
-	}
-	wg.Wait()
-	if wgResults.HasErrors() {
-		return fmt.Errorf("failed to get avalanchego version for node(s) %s", wgResults.GetErrorHostMap())
-	}
-	avagoVersions := map[string]string{}
-	for nodeID, avalanchegoVersion := range wgResults.GetResultMap() {
-		avagoVersions[nodeID] = fmt.Sprintf("%v", avalanchegoVersion)
+	avagoVersions, err := getAvalancheGoVersions(hosts)
+	if err != nil {
+		return err
 	}
 
 	notSyncedNodes := []string{}
@@ -207,6 +183,111 @@ func statusNode(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+// NodeStatus is the status of an avalanchego node of a cluster
+type NodeStatus struct {
+	CloudID            string   `json:"cloudID"`
+	NodeID             string   `json:"nodeID"`
+	IP                 string   `json:"ip"`
+	Roles              []string `json:"roles"`
+	Healthy            bool     `json:"healthy"`
+	Bootstrapped       bool     `json:"bootstrapped"`
+	AvalancheGoVersion string   `json:"avalancheGoVersion"`
+}
+
+// ClusterStatus is the status of the avalanchego nodes of a cluster
+type ClusterStatus struct {
+	ClusterName string       `json:"clusterName"`
+	Network     string       `json:"network"`
+	Nodes       []NodeStatus `json:"nodes"`
+}
+
+// GetClusterStatus checks health, bootstrap status and avalanchego version
+// of all avalanchego nodes of the cluster
+func GetClusterStatus(clusterName string) (*ClusterStatus, error) {
+	if err := checkCluster(clusterName); err != nil {
+		return nil, err
+	}
+	clusterConf, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	defer disconnectHosts(hosts)
+	hosts = utils.Filter(hosts, func(h *models.Host) bool { return clusterConf.IsAvalancheGoHost(h.GetCloudID()) })
+	notBootstrappedNodes, err := getNotBootstrappedNodes(hosts)
+	if err != nil {
+		return nil, err
+	}
+	unhealthyNodes, err := getUnhealthyNodes(hosts)
+	if err != nil {
+		return nil, err
+	}
+	avagoVersions, err := getAvalancheGoVersions(hosts)
+	if err != nil {
+		return nil, err
+	}
+	clusterStatus := &ClusterStatus{
+		ClusterName: clusterName,
+		Network:     clusterConf.Network.Name(),
+		Nodes:       []NodeStatus{},
+	}
+	for _, host := range hosts {
+		cloudID := host.GetCloudID()
+		nodeID, err := getNodeID(app.GetNodeInstanceDirPath(cloudID))
+		if err != nil {
+			return nil, err
+		}
+		nodeConfig, err := app.LoadClusterNodeConfig(cloudID)
+		if err != nil {
+			return nil, err
+		}
+		clusterStatus.Nodes = append(clusterStatus.Nodes, NodeStatus{
+			CloudID:            cloudID,
+			NodeID:             nodeID.String(),
+			IP:                 nodeConfig.ElasticIP,
+			Roles:              clusterConf.GetHostRoles(nodeConfig),
+			Healthy:            !slices.Contains(unhealthyNodes, cloudID),
+			Bootstrapped:       !slices.Contains(notBootstrappedNodes, cloudID),
+			AvalancheGoVersion: avagoVersions[cloudID],
+		})
+	}
+	return clusterStatus, nil
+}
+
+func getAvalancheGoVersions(hosts []*models.Host) (map[string]string, error) {
+	ux.Logger.PrintToUser("Getting avalanchego version of node(s)...")
+	wg := sync.WaitGroup{}
+	wgResults := models.NodeResults{}
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(nodeResults *models.NodeResults, host *models.Host) {
+			defer wg.Done()
+			if resp, err := ssh.RunSSHCheckAvalancheGoVersion(host); err != nil {
+				nodeResults.AddResult(host.GetCloudID(), nil, err)
+				return
+			} else {
+				if avalancheGoVersion, _, err := parseAvalancheGoOutput(resp); err != nil {
+					nodeResults.AddResult(host.GetCloudID(), nil, err)
+				} else {
+					nodeResults.AddResult(host.GetCloudID(), avalancheGoVersion, err)
+				}
+			}
+		}(&wgResults, host)
+	}
+	wg.Wait()
+	if wgResults.HasErrors() {
+		return nil, fmt.Errorf("failed to get avalanchego version for node(s) %s", wgResults.GetErrorHostMap())
+	}
+	avagoVersions := map[string]string{}
+	for nodeID, avalanchegoVersion := range wgResults.GetResultMap() {
+		avagoVersions[nodeID] = fmt.Sprintf("%v", avalanchegoVersion)
+	}
+	return avagoVersions, nil
+}
+
 func printOutput(
 	clusterConf models.ClusterConfig,
 	cloudIDs []string,
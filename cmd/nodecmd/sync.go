@@ -16,9 +16,12 @@ import (
 	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/spf13/cobra"
 )
 
+var syncChainName string
+
 func newSyncCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "sync [clusterName] [subnetName]",
@@ -26,7 +29,10 @@ func newSyncCmd() *cobra.Command {
 		Long: `(ALPHA Warning) This command is currently in experimental mode.
 
 The node sync command enables all nodes in a cluster to be bootstrapped to a Subnet. 
-You can check the subnet bootstrap status by calling avalanche node status <clusterName> --subnet <subnetName>`,
+You can check the subnet bootstrap status by calling avalanche node status <clusterName> --subnet <subnetName>
+
+The VMs of all the chains of the Subnet deployed on the cluster are installed, or only the
+one of the chain given with --chain.`,
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(2),
 		RunE:         syncSubnet,
@@ -34,6 +40,7 @@ You can check the subnet bootstrap status by calling avalanche node status <clus
 
 	cmd.Flags().StringSliceVar(&validators, "validators", []string{}, "sync subnet into given comma separated list of validators. defaults to all cluster nodes")
 	cmd.Flags().BoolVar(&avoidChecks, "no-checks", false, "do not check for bootstrapped/healthy status or rpc compatibility of nodes against subnet")
+	cmd.Flags().StringVar(&syncChainName, "chain", "", "only sync this chain of the subnet (defaults to all its deployed chains)")
 
 	return cmd
 }
@@ -44,7 +51,12 @@ func syncSubnet(_ *cobra.Command, args []string) error {
 	if err := checkCluster(clusterName); err != nil {
 		return err
 	}
-	if _, err := subnetcmd.ValidateSubnetNameAndGetChains([]string{subnetName}); err != nil {
+	chains, err := subnetcmd.ValidateSubnetNameAndGetChains([]string{subnetName})
+	if err != nil {
+		return err
+	}
+	syncChains, err := getChainsToSync(clusterName, subnetName, chains)
+	if err != nil {
 		return err
 	}
 	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
@@ -65,32 +77,66 @@ func syncSubnet(_ *cobra.Command, args []string) error {
 		if err := checkHostsAreHealthy(hosts); err != nil {
 			return err
 		}
-		if err := checkHostsAreRPCCompatible(hosts, subnetName); err != nil {
-			return err
+		for _, chain := range syncChains {
+			if err := checkHostsAreRPCCompatible(hosts, chain); err != nil {
+				return err
+			}
 		}
 	}
-	untrackedNodes, err := trackSubnet(hosts, clusterName, subnetName)
-	if err != nil {
-		return err
-	}
-	if len(untrackedNodes) > 0 {
-		return fmt.Errorf("node(s) %s failed to sync with subnet %s", untrackedNodes, subnetName)
+	for _, chain := range syncChains {
+		untrackedNodes, err := trackSubnet(hosts, clusterName, subnetName, chain)
+		if err != nil {
+			return err
+		}
+		if len(untrackedNodes) > 0 {
+			return fmt.Errorf("node(s) %s failed to sync with chain %s of subnet %s", untrackedNodes, chain, subnetName)
+		}
 	}
 	ux.Logger.PrintToUser("Node(s) successfully started syncing with Subnet!")
 	ux.Logger.PrintToUser(fmt.Sprintf("Check node subnet syncing status with avalanche node status %s --subnet %s", clusterName, subnetName))
 	return nil
 }
 
-// trackSubnet exports deployed subnet in user's local machine to cloud server and calls node to
-// start tracking the specified subnet (similar to avalanche subnet join <subnetName> command)
+// getChainsToSync returns the chain given with --chain, or else all the chains
+// of the subnet deployed on the cluster
+func getChainsToSync(clusterName string, subnetName string, chains []string) ([]string, error) {
+	if syncChainName != "" {
+		chain, err := subnetcmd.SelectSubnetChain(subnetName, chains, syncChainName)
+		if err != nil {
+			return nil, err
+		}
+		return []string{chain}, nil
+	}
+	network, err := app.GetClusterNetwork(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	deployed, err := subnetcmd.GetDeployedChains(chains, network)
+	if err != nil {
+		return nil, err
+	}
+	if len(deployed) == 0 {
+		return chains[:1], nil
+	}
+	return deployed, nil
+}
+
+// trackSubnet exports chain [chainName] of the deployed subnet in user's local machine to cloud
+// server and calls node to start tracking the specified subnet (similar to avalanche subnet join
+// <subnetName> --chain <chainName> command)
 func trackSubnet(
 	hosts []*models.Host,
 	clusterName string,
 	subnetName string,
+	chainName string,
 ) ([]string, error) {
-	subnetPath := "/tmp/" + subnetName + constants.ExportSubnetSuffix
-	networkFlag := "--cluster " + clusterName
-	if err := subnetcmd.CallExportSubnet(subnetName, subnetPath); err != nil {
+	subnetPath := "/tmp/" + chainName + constants.ExportSubnetSuffix
+	networkFlag := "--cluster " + clusterName + " --chain " + chainName
+	if err := subnetcmd.CallExportSubnet(chainName, subnetPath); err != nil {
+		return nil, err
+	}
+	sc, err := app.LoadSidecar(chainName)
+	if err != nil {
 		return nil, err
 	}
 	wg := sync.WaitGroup{}
@@ -111,6 +157,20 @@ func trackSubnet(
 				nodeResults.AddResult(host.NodeID, nil, err)
 				return
 			}
+			if build := sc.CustomVMBuild; build != nil && build.BinarySHA256 != "" {
+				binarySHA256, err := ssh.RunSSHGetPluginSHA256(host, build.VMID)
+				if err != nil {
+					nodeResults.AddResult(host.NodeID, nil, err)
+					return
+				}
+				if binarySHA256 != build.BinarySHA256 {
+					nodeResults.AddResult(host.NodeID, nil, fmt.Errorf(
+						"%w: node has sha256 %s, commit %s built into %s",
+						vm.ErrCustomVMChecksumMismatch, binarySHA256, build.Commit, build.BinarySHA256,
+					))
+					return
+				}
+			}
 		}(&wgResults, host)
 	}
 	wg.Wait()
@@ -82,7 +82,6 @@ func parseSubnetSyncOutput(byteValue []byte) (string, error) {
 }
 
 func addNodeAsSubnetValidator(
-	deployer *subnet.PublicDeployer,
 	network models.Network,
 	kc *keychain.Keychain,
 	useLedger bool,
@@ -93,7 +92,6 @@ func addNodeAsSubnetValidator(
 ) error {
 	ux.Logger.PrintToUser("Adding the node as a Subnet Validator...")
 	if err := subnetcmd.CallAddValidator(
-		deployer,
 		network,
 		kc,
 		useLedger,
@@ -285,7 +283,7 @@ func validateSubnet(_ *cobra.Command, args []string) error {
 				continue
 			}
 		}
-		err = addNodeAsSubnetValidator(deployer, network, kc, useLedger, nodeIDStr, subnetName, i, len(hosts))
+		err = addNodeAsSubnetValidator(network, kc, useLedger, nodeIDStr, subnetName, i, len(hosts))
 		if err != nil {
 			ux.Logger.PrintToUser("Failed to add node %s as subnet validator due to %s", host.NodeID, err.Error())
 			nodeErrors[host.NodeID] = err
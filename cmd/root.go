@@ -12,12 +12,14 @@ import (
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/cmd/primarycmd"
+	"github.com/ava-labs/avalanche-cli/cmd/servecmd"
 
 	"github.com/ava-labs/avalanche-cli/cmd/nodecmd"
 
 	"github.com/ava-labs/avalanche-cli/cmd/configcmd"
 
 	"github.com/ava-labs/avalanche-cli/cmd/backendcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/backupcmd"
 	"github.com/ava-labs/avalanche-cli/cmd/keycmd"
 	"github.com/ava-labs/avalanche-cli/cmd/networkcmd"
 	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
@@ -44,6 +46,7 @@ var (
 	Version   = ""
 	cfgFile   string
 	skipCheck bool
+	noProject bool
 )
 
 func NewRootCmd() *cobra.Command {
@@ -67,6 +70,7 @@ in with avalanche subnet create myNewSubnet.`,
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.avalanche-cli/config.json)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "ERROR", "log level for the application")
 	rootCmd.PersistentFlags().BoolVar(&skipCheck, constants.SkipUpdateFlag, false, "skip check for new versions")
+	rootCmd.PersistentFlags().BoolVar(&noProject, "no-project", false, "ignore any "+constants.ProjectDirName+" project dir and use the global subnet dir")
 
 	// add sub commands
 	rootCmd.AddCommand(subnetcmd.NewCmd(app))
@@ -92,6 +96,12 @@ in with avalanche subnet create myNewSubnet.`,
 	// add teleporter command
 	rootCmd.AddCommand(teleportercmd.NewCmd(app))
 
+	// add backup command
+	rootCmd.AddCommand(backupcmd.NewCmd(app, Version))
+
+	// add serve command
+	rootCmd.AddCommand(servecmd.NewCmd(app))
+
 	return rootCmd
 }
 
@@ -106,11 +116,16 @@ func createApp(cmd *cobra.Command, _ []string) error {
 	}
 	cf := config.New()
 	app.Setup(baseDir, log, cf, prompts.NewPrompter(), application.NewDownloader())
+	if err := setupProject(); err != nil {
+		return err
+	}
 
 	initConfig()
 
-	if err := migrations.RunMigrations(app); err != nil {
-		return err
+	if _, ok := cmd.Annotations[migrations.SkipAnnotation]; !ok {
+		if err := migrations.RunNewMigrations(app); err != nil {
+			return err
+		}
 	}
 	if utils.IsE2E() && !app.Conf.ConfigFileExists() && !utils.FileExists(utils.UserHomePath(constants.OldMetricsConfigFileName)) && metrics.CheckCommandIsNotCompletion(cmd) {
 		err = metrics.HandleUserMetricsPreference(app)
@@ -258,6 +273,31 @@ func setupEnv() (string, error) {
 	return baseDir, nil
 }
 
+// setupProject looks for a project dir from the working directory upwards,
+// and if found, makes the app keep subnet state inside it
+func setupProject() error {
+	if noProject {
+		return nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	projectDir, found := utils.FindProjectDir(wd)
+	if !found {
+		return nil
+	}
+	app.SetProjectDir(projectDir)
+	app.Log.Debug("using project dir", zap.String("dir", projectDir))
+	if err := os.MkdirAll(app.GetSubnetDir(), constants.DefaultPerms755); err != nil {
+		return fmt.Errorf("failed creating the project subnet dir %s: %w", app.GetSubnetDir(), err)
+	}
+	if err := os.MkdirAll(app.GetCustomVMDir(), constants.DefaultPerms755); err != nil {
+		return fmt.Errorf("failed creating the project vm dir %s: %w", app.GetCustomVMDir(), err)
+	}
+	return nil
+}
+
 func setupLogging(baseDir string) (logging.Logger, error) {
 	var err error
 
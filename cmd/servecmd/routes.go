@@ -0,0 +1,311 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/cmd/networkcmd"
+	"github.com/ava-labs/avalanche-cli/cmd/nodecmd"
+	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
+	"github.com/ava-labs/avalanche-cli/pkg/apiserver"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/localnetworkinterface"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/sdk"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-network-runner/server"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func registerRoutes(s *apiserver.Server) {
+	s.Handle(http.MethodGet, "/subnets", listSubnets)
+	s.Handle(http.MethodGet, "/subnets/{name}", describeSubnet)
+	s.HandleJob(http.MethodPost, "/subnets/{name}/deploy", "subnet-deploy", deploySubnet)
+	s.HandleJob(http.MethodPost, "/network/start", "network-start", func(*http.Request) (apiserver.JobFunc, error) {
+		return func() (interface{}, error) {
+			return nil, networkcmd.StartNetwork(nil, nil)
+		}, nil
+	})
+	s.HandleJob(http.MethodPost, "/network/stop", "network-stop", func(*http.Request) (apiserver.JobFunc, error) {
+		return func() (interface{}, error) {
+			return nil, networkcmd.StopNetwork(nil, nil)
+		}, nil
+	})
+	s.Handle(http.MethodGet, "/network/status", networkStatus)
+	s.Handle(http.MethodGet, "/keys", listKeys)
+	s.HandleJob(http.MethodPost, "/clusters/{name}/status", "cluster-status", clusterStatus)
+}
+
+func listSubnets(*http.Request) (interface{}, error) {
+	sidecars, err := subnetcmd.GetSidecars(app)
+	if err != nil {
+		return nil, err
+	}
+	if sidecars == nil {
+		sidecars = []*models.Sidecar{}
+	}
+	return sidecars, nil
+}
+
+type subnetDescription struct {
+	Sidecar models.Sidecar  `json:"sidecar"`
+	Genesis json.RawMessage `json:"genesis"`
+}
+
+func describeSubnet(r *http.Request) (interface{}, error) {
+	subnetName := apiserver.PathParam(r, "name")
+	if !app.SidecarExists(subnetName) {
+		return nil, apiserver.NotFound(fmt.Errorf("subnet %s not found", subnetName))
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := app.LoadRawGenesis(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(genesis) {
+		// custom VMs may have non JSON genesis
+		genesis, err = json.Marshal(string(genesis))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return subnetDescription{Sidecar: sc, Genesis: genesis}, nil
+}
+
+type deployRequest struct {
+	// Network is one of local, fuji, devnet or cluster
+	Network     string `json:"network"`
+	Endpoint    string `json:"endpoint,omitempty"`
+	ClusterName string `json:"clusterName,omitempty"`
+	// Key is the name of the stored key paying the fees
+	Key            string   `json:"key,omitempty"`
+	ControlKeys    []string `json:"controlKeys,omitempty"`
+	Threshold      uint32   `json:"threshold,omitempty"`
+	SubnetAuthKeys []string `json:"subnetAuthKeys,omitempty"`
+	SubnetID       string   `json:"subnetID,omitempty"`
+	SubnetOnly     bool     `json:"subnetOnly,omitempty"`
+	// SkipGenesisValidation deploys even if the genesis validation finds errors
+	SkipGenesisValidation bool `json:"skipGenesisValidation,omitempty"`
+}
+
+func deploySubnet(r *http.Request) (apiserver.JobFunc, error) {
+	subnetName := apiserver.PathParam(r, "name")
+	if !app.SidecarExists(subnetName) {
+		return nil, apiserver.NotFound(fmt.Errorf("subnet %s not found", subnetName))
+	}
+	var req deployRequest
+	if err := apiserver.DecodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	subnetID := ids.Empty
+	if req.SubnetID != "" {
+		var err error
+		subnetID, err = ids.FromString(req.SubnetID)
+		if err != nil {
+			return nil, apiserver.BadRequest(fmt.Errorf("invalid subnet ID: %w", err))
+		}
+	}
+	if req.Network == "local" {
+		return func() (interface{}, error) {
+			sc, err := app.LoadSidecar(subnetName)
+			if err != nil {
+				return nil, err
+			}
+			avagoVersion, err := subnetcmd.CheckForInvalidDeployAndGetAvagoVersion(localnetworkinterface.NewStatusChecker(), sc.RPCVersion)
+			if err != nil {
+				return nil, err
+			}
+			return sdk.New(app).DeployLocal(sdk.LocalDeployOptions{
+				SubnetName:            subnetName,
+				AvalancheGoVersion:    avagoVersion,
+				SubnetID:              subnetID,
+				SkipGenesisValidation: req.SkipGenesisValidation,
+			})
+		}, nil
+	}
+	networkFlags := networkoptions.NetworkFlags{
+		Endpoint:    req.Endpoint,
+		ClusterName: req.ClusterName,
+	}
+	switch req.Network {
+	case "fuji":
+		networkFlags.UseFuji = true
+	case "devnet":
+		if req.Endpoint == "" {
+			return nil, apiserver.BadRequest(errors.New("devnet deploys require an endpoint"))
+		}
+		networkFlags.UseDevnet = true
+	case "cluster":
+		if req.ClusterName == "" {
+			return nil, apiserver.BadRequest(errors.New("cluster deploys require a cluster name"))
+		}
+	default:
+		return nil, apiserver.BadRequest(fmt.Errorf("unsupported network %q: expected local, fuji, devnet or cluster", req.Network))
+	}
+	if req.Key == "" || !utils.FileExists(app.GetKeyPath(req.Key)) {
+		return nil, apiserver.BadRequest(fmt.Errorf("a stored key is required to pay the fees: %q not found", req.Key))
+	}
+	return func() (interface{}, error) {
+		network, err := networkoptions.GetNetworkFromCmdLineFlags(
+			app,
+			networkFlags,
+			false,
+			[]networkoptions.NetworkOption{networkoptions.Fuji, networkoptions.Devnet, networkoptions.Cluster},
+			"",
+		)
+		if err != nil {
+			return nil, err
+		}
+		if network.Kind == models.Mainnet {
+			return nil, errors.New("mainnet deploys require a ledger and are not supported by the API")
+		}
+		kc, err := keychain.GetKeychain(app, false, false, nil, req.Key, network, 0)
+		if err != nil {
+			return nil, err
+		}
+		controlKeys := req.ControlKeys
+		threshold := req.Threshold
+		if len(controlKeys) == 0 && subnetID == ids.Empty {
+			// same default as the deploy command: the fee paying key owns the subnet
+			controlKeys, err = kc.PChainFormattedStrAddresses()
+			if err != nil {
+				return nil, err
+			}
+			threshold = 1
+		}
+		return sdk.New(app).Deploy(sdk.DeployOptions{
+			SubnetName:            subnetName,
+			Network:               network,
+			Keychain:              kc,
+			SubnetID:              subnetID,
+			ControlKeys:           controlKeys,
+			Threshold:             threshold,
+			SubnetAuthKeys:        req.SubnetAuthKeys,
+			SubnetOnly:            req.SubnetOnly,
+			SkipGenesisValidation: req.SkipGenesisValidation,
+		})
+	}, nil
+}
+
+type networkNode struct {
+	Name   string `json:"name"`
+	NodeID string `json:"nodeID"`
+	URI    string `json:"uri"`
+}
+
+type localNetworkStatus struct {
+	Running             bool          `json:"running"`
+	Healthy             bool          `json:"healthy"`
+	CustomChainsHealthy bool          `json:"customChainsHealthy"`
+	Nodes               []networkNode `json:"nodes"`
+	Blockchains         []string      `json:"blockchains"`
+}
+
+func networkStatus(*http.Request) (interface{}, error) {
+	status := localNetworkStatus{Nodes: []networkNode{}, Blockchains: []string{}}
+	cli, err := binutils.NewGRPCClient(
+		binutils.WithDialTimeout(constants.FastGRPCDialTimeout),
+	)
+	if errors.Is(err, binutils.ErrGRPCTimeout) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	resp, err := cli.Status(ctx)
+	if err != nil {
+		if server.IsServerError(err, server.ErrNotBootstrapped) {
+			return status, nil
+		}
+		return nil, err
+	}
+	if resp == nil || resp.ClusterInfo == nil {
+		return status, nil
+	}
+	status.Running = true
+	status.Healthy = resp.ClusterInfo.Healthy
+	status.CustomChainsHealthy = resp.ClusterInfo.CustomChainsHealthy
+	for _, nodeName := range resp.ClusterInfo.NodeNames {
+		nodeInfo := resp.ClusterInfo.NodeInfos[nodeName]
+		status.Nodes = append(status.Nodes, networkNode{Name: nodeName, NodeID: nodeInfo.Id, URI: nodeInfo.Uri})
+	}
+	for blockchainID := range resp.ClusterInfo.CustomChains {
+		status.Blockchains = append(status.Blockchains, blockchainID)
+	}
+	return status, nil
+}
+
+type storedKey struct {
+	Name          string   `json:"name"`
+	PChainAddress []string `json:"pChainAddress"`
+	XChainAddress []string `json:"xChainAddress"`
+	EVMAddress    string   `json:"evmAddress"`
+}
+
+// listKeys lists the stored keys with their addresses on the network given
+// by the network query parameter (local, fuji or mainnet, defaults to fuji)
+func listKeys(r *http.Request) (interface{}, error) {
+	var network models.Network
+	switch r.URL.Query().Get("network") {
+	case "local":
+		network = models.NewLocalNetwork()
+	case "", "fuji":
+		network = models.NewFujiNetwork()
+	case "mainnet":
+		network = models.NewMainnetNetwork()
+	default:
+		return nil, apiserver.BadRequest(fmt.Errorf("unsupported network %q: expected local, fuji or mainnet", r.URL.Query().Get("network")))
+	}
+	files, err := os.ReadDir(app.GetKeyDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	keys := []storedKey{}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), constants.KeySuffix) {
+			continue
+		}
+		sk, err := key.LoadSoft(network.ID, filepath.Join(app.GetKeyDir(), f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, storedKey{
+			Name:          strings.TrimSuffix(f.Name(), constants.KeySuffix),
+			PChainAddress: sk.P(),
+			XChainAddress: sk.X(),
+			EVMAddress:    sk.C(),
+		})
+	}
+	return keys, nil
+}
+
+func clusterStatus(r *http.Request) (apiserver.JobFunc, error) {
+	clusterName := apiserver.PathParam(r, "name")
+	exists, err := app.ClusterExists(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apiserver.NotFound(fmt.Errorf("cluster %s not found", clusterName))
+	}
+	return func() (interface{}, error) {
+		return nodecmd.GetClusterStatus(clusterName)
+	}, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servecmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ava-labs/avalanche-cli/pkg/apiserver"
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	app *application.Avalanche
+
+	addr      string
+	tokenFile string
+)
+
+// avalanche serve
+func NewCmd(injectedApp *application.Avalanche) *cobra.Command {
+	app = injectedApp
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve CLI operations over an HTTP API",
+		Long: `The serve command starts a long-running HTTP server exposing CLI operations
+under /v1, so that dashboards and bots can drive the CLI without shelling out.
+
+Subnet list/describe, local network status and key list are answered directly.
+Subnet deploy, local network start/stop and node cluster status run as jobs:
+the request is answered with the queued job, whose progress can be polled at
+/v1/jobs/<id> or streamed as server-sent events from /v1/jobs/<id>/events.
+Jobs run one at a time.
+
+All requests must carry the header "Authorization: Bearer <token>". The token
+is generated on first use and stored locally; see avalanche serve token.`,
+		RunE:         serve,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&addr, "addr", constants.DefaultAPIServerAddress, "address to listen on")
+	cmd.PersistentFlags().StringVar(&tokenFile, "token-file", "", "file holding the API token (defaults to the CLI base dir)")
+	cmd.AddCommand(newTokenCmd())
+	return cmd
+}
+
+func getTokenPath() string {
+	if tokenFile != "" {
+		return tokenFile
+	}
+	return app.GetAPITokenPath()
+}
+
+func serve(*cobra.Command, []string) error {
+	token, err := apiserver.LoadOrCreateToken(getTokenPath())
+	if err != nil {
+		return err
+	}
+	server := apiserver.NewServer(app.Log, token)
+	registerRoutes(server)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	ux.Logger.PrintToUser("Serving API at http://%s%s", addr, apiserver.APIPrefix)
+	ux.Logger.PrintToUser("API token stored at %s", getTokenPath())
+	return server.ListenAndServe(ctx, addr)
+}
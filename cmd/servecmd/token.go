@@ -0,0 +1,46 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package servecmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/apiserver"
+	"github.com/spf13/cobra"
+)
+
+var rotateToken bool
+
+// avalanche serve token
+func newTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Print the API token",
+		Long: `The serve token command prints the token API clients must send as bearer
+token, generating it if needed. Use --rotate to replace it; running servers
+keep accepting the old token until restarted.`,
+		RunE:         printToken,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&rotateToken, "rotate", false, "generate a new token, replacing the current one")
+	return cmd
+}
+
+func printToken(*cobra.Command, []string) error {
+	var (
+		token string
+		err   error
+	)
+	if rotateToken {
+		token, err = apiserver.CreateToken(getTokenPath())
+	} else {
+		token, err = apiserver.LoadOrCreateToken(getTokenPath())
+	}
+	if err != nil {
+		return err
+	}
+	// print only the token, so it can be captured by scripts
+	fmt.Println(token)
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/sdk"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/txutils"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
@@ -55,7 +56,8 @@ for the validation start time, duration, and stake weight. You can bypass
 these prompts by providing the values with flags.
 
 This command currently only works on Subnets deployed to either the Fuji
-Testnet or Mainnet.`,
+Testnet or Mainnet. Use --dry-run to build the transaction and check its fee,
+the keychain balance and the control key signatures without issuing it.`,
 		SilenceUsage: true,
 		RunE:         addValidator,
 		Args:         cobra.ExactArgs(1),
@@ -80,6 +82,7 @@ Testnet or Mainnet.`,
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji/devnet)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
 	cmd.Flags().BoolVar(&justIssueTx, "just-issue-tx", false, "just issue the add validator tx, without waiting for its acceptance")
+	cmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "build the add validator tx and check fees, balance and signers without issuing it")
 	return cmd
 }
 
@@ -113,12 +116,10 @@ func addValidator(_ *cobra.Command, args []string) error {
 	if err := UpdateKeychainWithSubnetControlKeys(kc, network, subnetName); err != nil {
 		return err
 	}
-	deployer := subnet.NewPublicDeployer(app, kc, network)
-	return CallAddValidator(deployer, network, kc, useLedger, subnetName, nodeIDStr, defaultValidatorParams, justIssueTx)
+	return CallAddValidator(network, kc, useLedger, subnetName, nodeIDStr, defaultValidatorParams, justIssueTx)
 }
 
 func CallAddValidator(
-	deployer *subnet.PublicDeployer,
 	network models.Network,
 	kc *keychain.Keychain,
 	useLedgerSetting bool,
@@ -228,37 +229,56 @@ func CallAddValidator(
 	ux.Logger.PrintToUser("Start time: %s", start.Format(constants.TimeParseLayout))
 	ux.Logger.PrintToUser("End time: %s", start.Add(selectedDuration).Format(constants.TimeParseLayout))
 	ux.Logger.PrintToUser("Weight: %d", selectedWeight)
+
+	if dryRun {
+		plan := subnet.NewTxPlan(app, kc, network, subnetID, transferSubnetOwnershipTxID)
+		if err := plan.AddSubnetValidator(
+			subnetID,
+			controlKeys,
+			threshold,
+			subnetAuthKeys,
+			nodeID,
+			selectedWeight,
+			start,
+			selectedDuration,
+		); err != nil {
+			return err
+		}
+		return printTxPlan(plan, network)
+	}
+
 	ux.Logger.PrintToUser("Inputs complete, issuing transaction to add the provided validator information...")
 
-	isFullySigned, tx, remainingSubnetAuthKeys, err := deployer.AddValidator(
-		justIssueTx,
-		controlKeys,
-		subnetAuthKeys,
-		subnetID,
-		transferSubnetOwnershipTxID,
-		nodeID,
-		selectedWeight,
-		start,
-		selectedDuration,
-	)
+	result, err := sdk.New(app).AddValidator(sdk.AddValidatorOptions{
+		ValidatorOptions: sdk.ValidatorOptions{
+			SubnetName:     subnetName,
+			Network:        network,
+			Keychain:       kc,
+			NodeID:         nodeID,
+			ControlKeys:    controlKeys,
+			Threshold:      threshold,
+			SubnetAuthKeys: subnetAuthKeys,
+		},
+		Weight:      selectedWeight,
+		StartTime:   start,
+		Duration:    selectedDuration,
+		JustIssueTx: justIssueTx,
+	})
 	if err != nil {
 		return err
 	}
-	if !isFullySigned {
-		if err := SaveNotFullySignedTx(
+	if !result.FullySigned {
+		return SaveNotFullySignedTx(
 			"Add Validator",
-			tx,
+			result.Tx,
 			subnetName,
-			subnetAuthKeys,
-			remainingSubnetAuthKeys,
+			result.SubnetAuthKeys,
+			result.RemainingSubnetAuthKeys,
 			outputTxPath,
 			false,
-		); err != nil {
-			return err
-		}
+		)
 	}
-
-	return err
+	return nil
 }
 
 func PromptDuration(start time.Time, network models.Network) (time.Duration, error) {
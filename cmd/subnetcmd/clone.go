@@ -0,0 +1,63 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/sdk"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cloneChainID   uint64
+	cloneTokenName string
+	forceClone     bool
+)
+
+// avalanche subnet clone
+func newCloneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone [sourceSubnetName] [destinationSubnetName]",
+		Short: "Copy a subnet configuration into a new one",
+		Long: `The subnet clone command copies the configuration of an existing subnet
+into a new one, for example to spin up a staging copy of a production subnet.
+
+The genesis, chain config, per node chain config, subnet config, node config,
+upgrade bytes and custom VM binary are copied. Deployment data is not: the
+clone has no deployed networks and no applied upgrades, so it can be deployed
+from scratch. The clone gets its own VMID, derived from its name.
+
+Use --evm-chain-id and --evm-token to give the clone its own Subnet-EVM chain
+ID and token name.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		RunE:         cloneSubnet,
+	}
+	cmd.Flags().Uint64Var(&cloneChainID, "evm-chain-id", 0, "chain ID to use for the clone (Subnet-EVM only)")
+	cmd.Flags().StringVar(&cloneTokenName, "evm-token", "", "token name to use for the clone")
+	cmd.Flags().BoolVarP(&forceClone, forceFlag, "f", false, "overwrite the destination configuration if it exists")
+	return cmd
+}
+
+func cloneSubnet(_ *cobra.Command, args []string) error {
+	result, err := sdk.New(app).CloneSubnet(sdk.CloneOptions{
+		Source:      args[0],
+		Destination: args[1],
+		ChainID:     cloneChainID,
+		TokenName:   cloneTokenName,
+		Force:       forceClone,
+	})
+	if err != nil {
+		return err
+	}
+	for _, path := range result.CopiedFiles {
+		ux.Logger.PrintToUser("Wrote %s", path)
+	}
+	ux.Logger.PrintToUser("VMID of %s: %s", result.Sidecar.Name, result.VMID)
+	if result.Sidecar.VM == models.SubnetEvm && cloneChainID == 0 {
+		ux.Logger.PrintToUser("The clone keeps the chain ID of %s. Use --evm-chain-id to set a different one if both chains can be reached by the same wallets", args[0])
+	}
+	ux.Logger.GreenCheckmarkToUser("Successfully cloned %s into %s", args[0], args[1])
+	return nil
+}
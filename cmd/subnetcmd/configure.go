@@ -3,12 +3,14 @@
 package subnetcmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/spf13/cobra"
@@ -19,6 +21,7 @@ var (
 	subnetConf       string
 	chainConf        string
 	perNodeChainConf string
+	deployHooksConf  string
 )
 
 // avalanche subnet configure
@@ -29,7 +32,22 @@ func newConfigureCmd() *cobra.Command {
 		Long: `AvalancheGo nodes support several different configuration files. Subnets have their own
 Subnet config which applies to all chains/VMs in the Subnet. Each chain within the Subnet
 can have its own chain config. A chain can also have special requirements for the AvalancheGo node 
-configuration itself. This command allows you to set all those files.`,
+configuration itself. This command allows you to set all those files.
+
+The --deploy-hooks file declares the hooks avalanche subnet deploy runs before and after
+deploying the chain, either executables or built-in actions:
+
+{
+  "preDeploy": [{"name": "check", "command": "./check.sh"}],
+  "postDeploy": [
+    {"name": "registry", "deployContract": {"bytecodePath": "registry.hex", "key": "deployer"}},
+    {"name": "relayers", "fund": {"addresses": ["0x..."], "amount": "1000000000000000000", "key": "deployer"}},
+    {"name": "indexer", "command": "./register.sh", "networks": ["Fuji"], "continueOnError": true}
+  ]
+}
+
+Outside of the local network, post-deploy hooks are run with avalanche subnet hooks run,
+once the validators serve the chain.`,
 		SilenceUsage: true,
 		RunE:         configure,
 		Args:         cobra.ExactArgs(1),
@@ -39,6 +57,7 @@ configuration itself. This command allows you to set all those files.`,
 	cmd.Flags().StringVar(&subnetConf, "subnet-config", "", "path to the subnet configuration")
 	cmd.Flags().StringVar(&chainConf, "chain-config", "", "path to the chain configuration")
 	cmd.Flags().StringVar(&perNodeChainConf, "per-node-chain-config", "", "path to per node chain configuration for local network")
+	cmd.Flags().StringVar(&deployHooksConf, "deploy-hooks", "", "path to the deploy hooks configuration")
 	return cmd
 }
 
@@ -82,6 +101,12 @@ func configure(_ *cobra.Command, args []string) error {
 	if perNodeChainConf != "" {
 		configsToLoad[perNodeChainLabel] = perNodeChainConf
 	}
+	if deployHooksConf != "" {
+		if err := validateDeployHooks(deployHooksConf); err != nil {
+			return err
+		}
+		configsToLoad[constants.DeployHooksFileName] = deployHooksConf
+	}
 
 	// no flags provided
 	if len(configsToLoad) == 0 {
@@ -122,6 +147,18 @@ func configure(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+func validateDeployHooks(path string) error {
+	hooksBytes, err := utils.ValidateJSON(path)
+	if err != nil {
+		return err
+	}
+	var hooks models.DeployHooks
+	if err := json.Unmarshal(hooksBytes, &hooks); err != nil {
+		return fmt.Errorf("invalid deploy hooks file %s: %w", path, err)
+	}
+	return hooks.Validate()
+}
+
 func updateConf(subnet, path, filename string) error {
 	var (
 		fileBytes []byte
@@ -3,23 +3,18 @@
 package subnetcmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
-	"unicode"
 
 	"github.com/ava-labs/avalanche-cli/cmd/flags"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/metrics"
 
-	"github.com/ava-labs/avalanche-cli/pkg/binutils"
-	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
-	"github.com/ava-labs/avalanche-cli/pkg/teleporter"
-	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/sdk"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/spf13/cobra"
@@ -46,11 +41,23 @@ var (
 	useLatestPreReleasedEvmVersion bool
 	useRepo                        bool
 	teleporterReady                bool
+	airdropFile                    string
+	genesisContractsFile           string
+	genesisContractFlags           []string
+	genesisTemplate                string
+	genesisTemplateParams          map[string]string
+	precompilesFile                string
+	vmTemplateName                 string
+	vmTemplateParams               map[string]string
+	vmTemplateDefaults             bool
+	customVMBuildImage             string
+	parentSubnetName               string
 
-	errIllegalNameCharacter = errors.New(
-		"illegal name character: only letters, no special characters allowed")
 	errMutuallyExlusiveVersionOptions = errors.New("version flags --latest,--pre-release,vm-version are mutually exclusive")
-	errMutuallyVMConfigOptions        = errors.New("specifying --genesis flag disables SubnetEVM config flags --evm-chain-id,--evm-token,--evm-defaults")
+	errMutuallyVMConfigOptions        = errors.New("specifying --genesis flag disables SubnetEVM config flags --evm-chain-id,--evm-token,--evm-defaults,--airdrop-file,--genesis-contracts,--genesis-contract,--template,--precompiles-file")
+	errAirdropFileCustomVM            = errors.New("--airdrop-file, --genesis-contracts, --genesis-contract, --template and --precompiles-file are only supported with Subnet-EVM")
+	errTemplateWithDefaults           = errors.New("--template and --evm-defaults are mutually exclusive")
+	errVMTemplateParams               = errors.New("--vm-template-param and --vm-template-defaults require --vm-template")
 )
 
 // avalanche subnet create
@@ -68,7 +75,46 @@ the path to your genesis and VM binaries with the --genesis and --vm flags.
 
 By default, running the command with a subnetName that already exists
 causes the command to fail. If you’d like to overwrite an existing
-configuration, pass the -f flag.`,
+configuration, pass the -f flag.
+
+To seed many accounts at once, pass a CSV or JSON file with the --airdrop-file
+flag. CSV files need a header row with address and amount columns, and may
+add unit (token, gwei or wei; token by default), code and storage columns to
+pre-deploy contracts. Amounts for repeated addresses are summed.
+
+Contracts such as multicall or token contracts can also be placed in the
+genesis from their runtime bytecode or compiled Foundry/Hardhat artifacts,
+either with a JSON or YAML list given to --genesis-contracts, or with
+--genesis-contract <address>=<artifact file or 0x bytecode>.
+
+Named genesis templates, such as gaming, enterprise or high-throughput, are
+selected with --template <name>[@version] and customized with
+--template-param key=value. A template provides the fee config, precompiles
+and allocations, so no genesis question is asked. Use subnet template list
+to see the available templates.
+
+Precompiles can be configured without prompts with --precompiles-file, a JSON
+or YAML file using the subnet-evm genesis config keys (txAllowListConfig,
+contractDeployerAllowListConfig, contractNativeMinterConfig, feeManagerConfig,
+rewardManagerConfig, warpConfig), with their admin, manager and enabled
+addresses and initial configs. Its settings override the template ones.
+
+VMs other than Subnet-EVM can be registered as VM templates, either compiled
+in or described by a manifest (see subnet vm-template). Select one with
+--vm-template <name>: its binary is downloaded or built, and its genesis is
+created from the template params, given with --vm-template-param key=value
+or asked. --vm-template-defaults uses the param defaults without asking, and
+--genesis replaces the template genesis altogether.
+
+Custom VMs built from a git repository are pinned: the commit the branch
+resolves to, the SHA-256 of the built binary and its VMID are stored, later
+builds check out that commit, and deploys and node syncs fail if the binary
+differs. --custom-vm-build-image runs the build script in a container of the
+given image, for bit-reproducible builds across hosts.
+
+A Subnet can run several chains. --subnet <subnetName> creates the configuration
+as an additional chain of an existing Subnet: it is deployed into that Subnet with
+avalanche subnet deploy <subnetName> --chain <chainName>, sharing its validators.`,
 		SilenceUsage:      true,
 		Args:              cobra.ExactArgs(1),
 		RunE:              createSubnetConfig,
@@ -89,8 +135,19 @@ configuration, pass the -f flag.`,
 	cmd.Flags().StringVar(&customVMRepoURL, "custom-vm-repo-url", "", "custom vm repository url")
 	cmd.Flags().StringVar(&customVMBranch, "custom-vm-branch", "", "custom vm branch or commit")
 	cmd.Flags().StringVar(&customVMBuildScript, "custom-vm-build-script", "", "custom vm build-script")
+	cmd.Flags().StringVar(&customVMBuildImage, "custom-vm-build-image", "", "run the custom vm build-script in this container image, for reproducible builds (pin it by digest)")
 	cmd.Flags().BoolVar(&useRepo, "from-github-repo", false, "generate custom VM binary from github repository")
 	cmd.Flags().BoolVar(&teleporterReady, "teleporter", true, "generate a teleporter-ready vm")
+	cmd.Flags().StringVar(&airdropFile, "airdrop-file", "", "CSV or JSON file with the Subnet-EVM genesis allocations")
+	cmd.Flags().StringVar(&genesisContractsFile, "genesis-contracts", "", "JSON or YAML file listing contracts to pre-deploy in the Subnet-EVM genesis")
+	cmd.Flags().StringVar(&genesisTemplate, "template", "", "create the Subnet-EVM genesis from the given template, as name or name@version")
+	cmd.Flags().StringToStringVar(&genesisTemplateParams, "template-param", nil, "value for a template param, as key=value")
+	cmd.Flags().StringVar(&precompilesFile, "precompiles-file", "", "JSON or YAML file with the Subnet-EVM genesis precompile configs")
+	cmd.Flags().StringSliceVar(&genesisContractFlags, "genesis-contract", nil, "contract to pre-deploy in the Subnet-EVM genesis, as <address>=<artifact file or 0x bytecode>")
+	cmd.Flags().StringVar(&vmTemplateName, "vm-template", "", "use the VM of the given VM template")
+	cmd.Flags().StringToStringVar(&vmTemplateParams, "vm-template-param", nil, "value for a VM template genesis param, as key=value")
+	cmd.Flags().BoolVar(&vmTemplateDefaults, "vm-template-defaults", false, "use the VM template param defaults instead of asking")
+	cmd.Flags().StringVar(&parentSubnetName, "subnet", "", "create the configuration as an additional chain of the given subnet")
 	return cmd
 }
 
@@ -129,13 +186,17 @@ func CallCreate(
 
 func detectVMTypeFromFlags() {
 	// assumes custom
-	if customVMRepoURL != "" || customVMBranch != "" || customVMBuildScript != "" {
+	if customVMRepoURL != "" || customVMBranch != "" || customVMBuildScript != "" || customVMBuildImage != "" {
 		useCustom = true
 	}
+	// templates are Subnet-EVM only
+	if genesisTemplate != "" && !useCustom {
+		useSubnetEvm = true
+	}
 }
 
 func moreThanOneVMSelected() bool {
-	vmVars := []bool{useSubnetEvm, useCustom}
+	vmVars := []bool{useSubnetEvm, useCustom, vmTemplateName != ""}
 	firstSelect := false
 	for _, val := range vmVars {
 		if firstSelect && val {
@@ -154,6 +215,9 @@ func getVMFromFlag() models.VMType {
 	if useCustom {
 		return models.CustomVM
 	}
+	if vmTemplateName != "" {
+		return models.VMType(vmTemplateName)
+	}
 	return ""
 }
 
@@ -180,26 +244,57 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 		return errMutuallyExlusiveVersionOptions
 	}
 
-	if genesisFile != "" && (evmChainID != 0 || evmToken != "" || evmDefaults) {
+	customGenesisContents := airdropFile != "" || genesisContractsFile != "" || len(genesisContractFlags) > 0 || genesisTemplate != "" || precompilesFile != ""
+	if genesisFile != "" && (evmChainID != 0 || evmToken != "" || evmDefaults || customGenesisContents) {
 		return errMutuallyVMConfigOptions
 	}
 
+	if genesisTemplate != "" && evmDefaults {
+		return errTemplateWithDefaults
+	}
+
+	if vmTemplateName == "" && (len(vmTemplateParams) > 0 || vmTemplateDefaults) {
+		return errVMTemplateParams
+	}
+
+	if parentSubnetName != "" {
+		if err := checkParentSubnet(subnetName, parentSubnetName); err != nil {
+			return err
+		}
+	}
+
 	subnetType := getVMFromFlag()
 
 	if subnetType == "" {
+		vmTemplates, err := vm.ListVMTemplates(app)
+		if err != nil {
+			return err
+		}
+		options := []string{models.SubnetEvm, models.CustomVM}
+		for _, t := range vmTemplates {
+			options = append(options, t.Name())
+		}
 		subnetTypeStr, err := app.Prompt.CaptureList(
 			"Choose your VM",
-			[]string{models.SubnetEvm, models.CustomVM},
+			options,
 		)
 		if err != nil {
 			return err
 		}
 		subnetType = models.VMTypeFromString(subnetTypeStr)
+		if subnetType == models.CustomVM && subnetTypeStr != models.CustomVM {
+			subnetType = models.VMType(subnetTypeStr)
+		}
+	}
+
+	if customGenesisContents && subnetType != models.SubnetEvm {
+		return errAirdropFileCustomVM
 	}
 
 	var (
 		genesisBytes []byte
 		sc           *models.Sidecar
+		chainConfig  []byte
 		err          error
 	)
 
@@ -211,12 +306,24 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 		evmVersion = preRelease
 	}
 
-	if evmVersion != latest && evmVersion != preRelease && evmVersion != "" && !semver.IsValid(evmVersion) {
+	// VM templates built from source are versioned by git refs
+	usesVMTemplate := subnetType != models.SubnetEvm && subnetType != models.CustomVM
+	if !usesVMTemplate && evmVersion != latest && evmVersion != preRelease && evmVersion != "" && !semver.IsValid(evmVersion) {
 		return fmt.Errorf("invalid version string, should be semantic version (ex: v1.1.1): %s", evmVersion)
 	}
 
 	switch subnetType {
 	case models.SubnetEvm:
+		var genesisContracts []vm.GenesisContract
+		genesisContracts, err = getGenesisContracts()
+		if err != nil {
+			return err
+		}
+		var evmTemplate *vm.EvmGenesisTemplate
+		evmTemplate, err = getEvmGenesisTemplate()
+		if err != nil {
+			return err
+		}
 		genesisBytes, sc, err = vm.CreateEvmSubnetConfig(
 			app,
 			subnetName,
@@ -227,6 +334,10 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 			evmToken,
 			evmDefaults,
 			teleporterReady,
+			airdropFile,
+			genesisContracts,
+			evmTemplate,
+			precompilesFile,
 		)
 		if err != nil {
 			return err
@@ -240,60 +351,53 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 			customVMRepoURL,
 			customVMBranch,
 			customVMBuildScript,
+			customVMBuildImage,
 			vmFile,
 		)
 		if err != nil {
 			return err
 		}
 	default:
-		return errors.New("not implemented")
-	}
-
-	if teleporterReady {
-		if isSubnetEVMGenesis := jsonIsSubnetEVMGenesis(genesisBytes); isSubnetEVMGenesis {
-			keyPath := app.GetKeyPath(constants.TeleporterKeyName)
-			var k *key.SoftKey
-			if utils.FileExists(keyPath) {
-				ux.Logger.PrintToUser("loading stored key %q for teleporter deploys", constants.TeleporterKeyName)
-				k, err = key.LoadSoft(models.NewLocalNetwork().ID, keyPath)
-				if err != nil {
-					return err
-				}
-			} else {
-				ux.Logger.PrintToUser("generating stored key %q for teleporter deploys", constants.TeleporterKeyName)
-				k, err = key.NewSoft(0)
-				if err != nil {
-					return err
-				}
-				if err := k.Save(keyPath); err != nil {
-					return err
-				}
-			}
-			ux.Logger.PrintToUser("  (evm address, genesis balance) = (%s, %v)", k.C(), teleporter.TeleporterPrefundedAddressBalance)
-			genesisBytes, err = addSubnetEVMGenesisPrefundedAddress(genesisBytes, k.C(), teleporter.TeleporterPrefundedAddressBalance.String())
-			if err != nil {
-				return err
-			}
-			// let's use latest versions for teleporter contract
-			teleporterVersion, err := app.Downloader.GetLatestReleaseVersion(binutils.GetGithubLatestReleaseURL(constants.AvaLabsOrg, constants.TeleporterRepoName))
-			if err != nil {
-				return err
-			}
-			ux.Logger.PrintToUser("using latest teleporter version (%s)", teleporterVersion)
-			sc.TeleporterReady = true
-			sc.TeleporterKey = constants.TeleporterKeyName
-			sc.TeleporterVersion = teleporterVersion
+		var vmTemplate vm.VMTemplate
+		vmTemplate, err = vm.GetVMTemplate(app, string(subnetType))
+		if err != nil {
+			return err
+		}
+		if useLatestPreReleasedEvmVersion {
+			return fmt.Errorf("--%s is not supported by VM templates", preRelease)
+		}
+		if evmVersion == latest {
+			evmVersion = ""
+		}
+		genesisBytes, sc, err = vm.CreateTemplateSubnetConfig(
+			app,
+			subnetName,
+			vmTemplate,
+			evmVersion,
+			genesisFile,
+			vmTemplateParams,
+			vmTemplateDefaults,
+		)
+		if err != nil {
+			return err
+		}
+		chainConfig, err = vmTemplate.DefaultChainConfig()
+		if err != nil {
+			return err
 		}
 	}
 
-	if err = app.WriteGenesisFile(subnetName, genesisBytes); err != nil {
-		return err
+	if parentSubnetName != "" {
+		sc.Subnet = parentSubnetName
 	}
-
-	sc.ImportedFromAPM = false
-	if err = app.CreateSidecar(sc); err != nil {
+	if err := sdk.New(app).SaveSubnet(sc, genesisBytes, teleporterReady); err != nil {
 		return err
 	}
+	if chainConfig != nil {
+		if err := app.WriteChainConfigFile(subnetName, chainConfig); err != nil {
+			return err
+		}
+	}
 	if subnetType == models.SubnetEvm {
 		err = sendMetrics(cmd, subnetType.RepoName(), subnetName)
 		if err != nil {
@@ -304,25 +408,43 @@ func createSubnetConfig(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func addSubnetEVMGenesisPrefundedAddress(genesisBytes []byte, address string, balance string) ([]byte, error) {
-	var genesisMap map[string]interface{}
-	if err := json.Unmarshal(genesisBytes, &genesisMap); err != nil {
-		return nil, err
+// checkParentSubnet checks that chain [chainName] can be added to subnet [subnetName]
+func checkParentSubnet(chainName, subnetName string) error {
+	if subnetName == chainName {
+		return fmt.Errorf("--subnet must name another subnet than %s", chainName)
 	}
-	allocI, ok := genesisMap["alloc"]
-	if !ok {
-		return nil, fmt.Errorf("alloc field not found on genesis")
+	if !app.SidecarExists(subnetName) {
+		return fmt.Errorf("subnet %s does not exist", subnetName)
 	}
-	alloc, ok := allocI.(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("expected genesis alloc field to be map[string]interface, found %T", allocI)
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	if sc.IsAddedChain() {
+		return fmt.Errorf("%s is a chain of subnet %s, not a subnet", subnetName, sc.GetSubnetName())
+	}
+	return nil
+}
+
+// getGenesisContracts collects the contracts given with --genesis-contracts
+// and --genesis-contract
+func getGenesisContracts() ([]vm.GenesisContract, error) {
+	contracts := []vm.GenesisContract{}
+	if genesisContractsFile != "" {
+		fileContracts, err := vm.LoadGenesisContractsFile(genesisContractsFile)
+		if err != nil {
+			return nil, err
+		}
+		contracts = append(contracts, fileContracts...)
 	}
-	trimmedAddress := strings.TrimPrefix(address, "0x")
-	alloc[trimmedAddress] = map[string]interface{}{
-		"balance": balance,
+	for _, value := range genesisContractFlags {
+		contract, err := vm.ParseGenesisContractFlag(value)
+		if err != nil {
+			return nil, err
+		}
+		contracts = append(contracts, contract)
 	}
-	genesisMap["alloc"] = alloc
-	return json.MarshalIndent(genesisMap, "", "  ")
+	return contracts, nil
 }
 
 func sendMetrics(cmd *cobra.Command, repoName, subnetName string) error {
@@ -357,12 +479,5 @@ func sendMetrics(cmd *cobra.Command, repoName, subnetName string) error {
 }
 
 func checkInvalidSubnetNames(name string) error {
-	// this is currently exactly the same code as in avalanchego/vms/platformvm/create_chain_tx.go
-	for _, r := range name {
-		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsNumber(r) || r == ' ') {
-			return errIllegalNameCharacter
-		}
-	}
-
-	return nil
+	return sdk.ValidateSubnetName(name)
 }
@@ -4,9 +4,11 @@ package subnetcmd
 
 import (
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/spf13/cobra"
@@ -34,8 +36,19 @@ func deleteSubnet(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if !sidecar.IsAddedChain() {
+		chains, err := getChainsInSubnet(subnetName)
+		if err != nil {
+			return err
+		}
+		if len(chains) > 1 {
+			return fmt.Errorf("subnet %s has other chains (%s): delete them first", subnetName, strings.Join(chains[1:], ", "))
+		}
+	}
 
-	if sidecar.VM == models.CustomVM {
+	// VM templates built from source keep their binary with the custom ones
+	builtVMTemplate := sidecar.VMTemplate != nil && sidecar.VMTemplate.Binary.Build != nil
+	if sidecar.VM == models.CustomVM || builtVMTemplate {
 		if _, err := os.Stat(customVMPath); err != nil {
 			if !errors.Is(err, fs.ErrNotExist) {
 				return err
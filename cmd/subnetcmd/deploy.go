@@ -11,7 +11,6 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/keychain"
@@ -20,6 +19,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/sdk"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/txutils"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
@@ -33,7 +33,7 @@ import (
 	"github.com/ava-labs/subnet-evm/params"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
-	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
 	"golang.org/x/mod/semver"
 )
 
@@ -52,10 +52,14 @@ var (
 	ledgerAddresses          []string
 	subnetIDStr              string
 	mainnetChainID           uint32
+	dryRun                   bool
+	skipHooks                bool
 	skipCreatePrompt         bool
 	avagoBinaryPath          string
 	skipLocalTeleporter      bool
 	subnetOnly               bool
+	chainName                string
+	skipGenesisValidation    bool
 
 	errMutuallyExlusiveControlKeys = errors.New("--control-keys and --same-control-key are mutually exclusive")
 	ErrMutuallyExlusiveKeyLedger   = errors.New("key source flags --key, --ledger/--ledger-addrs are mutually exclusive")
@@ -77,7 +81,26 @@ attempts to deploy the same Subnet to the same network (local, Fuji, Mainnet) ar
 allowed. If you'd like to redeploy a Subnet locally for testing, you must first call
 avalanche network clean to reset all deployed chain state. Subsequent local deploys
 redeploy the chain with fresh state. You can deploy the same Subnet to multiple networks,
-so you can take your locally tested Subnet and deploy it on Fuji or Mainnet.`,
+so you can take your locally tested Subnet and deploy it on Fuji or Mainnet.
+
+A Subnet can run several chains sharing its validator set. Chain configurations created
+with avalanche subnet create <chainName> --subnet <subnetName> are deployed into the
+already deployed Subnet with avalanche subnet deploy <subnetName> --chain <chainName>.
+
+Public deploys checkpoint each transaction they issue. If a deploy fails or is aborted
+after creating the Subnet, running it again resumes it from the failed step instead of
+creating (and paying for) a new Subnet. Use --dry-run to build the deploy transactions and
+check their fees, the keychain balance and the control key signatures without issuing them.
+
+Deploy hooks set with avalanche subnet configure <subnetName> --deploy-hooks <file> run
+before and after each deploy. Executable hooks receive the deploy info (SubnetID,
+BlockchainID, RPC URL, Teleporter addresses) as AVALANCHE_* env vars and as JSON in
+their stdin. Post-deploy hooks can also deploy a contract or fund addresses with a
+stored key. Their results are recorded in the sidecar. Use --skip-hooks to skip them.
+Post-deploy hooks run right after local deploys. On other networks the chain is not
+served until its validators join it, so they are run with avalanche subnet hooks run
+once it is. The same goes for the check that the genesis contracts of Subnet-EVM
+chains are deployed.`,
 		SilenceUsage:      true,
 		RunE:              deploySubnet,
 		PersistentPostRun: handlePostRun,
@@ -99,6 +122,10 @@ so you can take your locally tested Subnet and deploy it on Fuji or Mainnet.`,
 	cmd.Flags().StringVar(&avagoBinaryPath, "avalanchego-path", "", "use this avalanchego binary path")
 	cmd.Flags().BoolVar(&skipLocalTeleporter, "skip-local-teleporter", false, "skip local teleporter deploy to a local network")
 	cmd.Flags().BoolVar(&subnetOnly, "subnet-only", false, "only create a subnet")
+	cmd.Flags().StringVar(&chainName, "chain", "", "deploy this chain of the subnet (see subnet create --subnet)")
+	cmd.Flags().BoolVar(&skipGenesisValidation, skipGenesisValidationFlag, false, "deploy even if genesis validation finds errors")
+	cmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "build the deploy txs and check fees, balance and signers without issuing them")
+	cmd.Flags().BoolVar(&skipHooks, "skip-hooks", false, "do not run the deploy hooks of the subnet")
 	return cmd
 }
 
@@ -151,9 +178,43 @@ func getChainsInSubnet(subnetName string) ([]string, error) {
 			}
 		}
 	}
+	// the chain the subnet was created with goes first
+	if i := slices.Index(chains, subnetName); i > 0 {
+		chains = append([]string{subnetName}, slices.Delete(chains, i, i+1)...)
+	}
 	return chains, nil
 }
 
+// SelectSubnetChain returns the chain of subnet [subnetName] named [chainName],
+// or else asks for one when the subnet has more than one chain
+func SelectSubnetChain(subnetName string, chains []string, chainName string) (string, error) {
+	if chainName != "" {
+		if !slices.Contains(chains, chainName) {
+			return "", fmt.Errorf("subnet %s has no chain %s. Its chains are %s", subnetName, chainName, strings.Join(chains, ", "))
+		}
+		return chainName, nil
+	}
+	if len(chains) == 1 {
+		return chains[0], nil
+	}
+	return app.Prompt.CaptureList(fmt.Sprintf("Choose a chain of subnet %s", subnetName), chains)
+}
+
+// GetDeployedChains returns the chains among [chains] that are deployed on [network]
+func GetDeployedChains(chains []string, network models.Network) ([]string, error) {
+	deployed := []string{}
+	for _, chain := range chains {
+		sc, err := app.LoadSidecar(chain)
+		if err != nil {
+			return nil, err
+		}
+		if sc.Networks[network.Name()].BlockchainID != ids.Empty {
+			deployed = append(deployed, chain)
+		}
+	}
+	return deployed, nil
+}
+
 func checkSubnetEVMDefaultAddressNotInAlloc(network models.Network, chain string) error {
 	if network.Kind != models.Local && network.Kind != models.Devnet && os.Getenv(constants.SimulatePublicNetwork) == "" {
 		genesis, err := app.LoadEvmGenesis(chain)
@@ -267,7 +328,10 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	chain := chains[0]
+	chain, err := SelectSubnetChain(subnetName, chains, chainName)
+	if err != nil {
+		return err
+	}
 
 	sidecar, err := app.LoadSidecar(chain)
 	if err != nil {
@@ -299,13 +363,27 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	chainGenesis, err := app.LoadRawGenesis(chain)
+	if err != nil {
+		return err
+	}
+
 	if sidecar.VM == models.SubnetEvm && !isEVMGenesis {
+		// give details on what is wrong
+		if err := lintGenesisBeforeDeploy(chainGenesis, sidecar.TeleporterReady); err != nil {
+			return err
+		}
 		return fmt.Errorf("failed to validate SubnetEVM genesis format")
 	}
 
-	chainGenesis, err := app.LoadRawGenesis(chain)
-	if err != nil {
-		return err
+	if sidecar.VM == models.CustomVM {
+		// the validators will build or install the binary recorded in the sidecar
+		vmPath := app.GetCustomVMPath(chain)
+		if utils.FileExists(vmPath) {
+			if err := vm.VerifyCustomVMBinary(&sidecar, vmPath); err != nil {
+				return err
+			}
+		}
 	}
 
 	if isEVMGenesis {
@@ -324,28 +402,43 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+		if !skipGenesisValidation {
+			if err := lintGenesisBeforeDeploy(chainGenesis, sidecar.TeleporterReady); err != nil {
+				return err
+			}
+		}
 	}
 
-	ux.Logger.PrintToUser("Deploying %s to %s", chains, network.Name())
-
-	if network.Kind == models.Local {
-		app.Log.Debug("Deploy local")
-
-		genesisPath := app.GetGenesisPath(chain)
-
-		// copy vm binary to the expected location, first downloading it if necessary
-		var vmBin string
-		switch sidecar.VM {
-		case models.SubnetEvm:
-			_, vmBin, err = binutils.SetupSubnetEVM(app, sidecar.VMVersion)
+	if sidecar.IsAddedChain() {
+		if subnetOnly {
+			return fmt.Errorf("--subnet-only can't be used to deploy %s, a chain added to subnet %s", chain, subnetName)
+		}
+		if subnetIDStr == "" {
+			// deploy into the subnet the chain was added to
+			subnetData, deployed, err := app.GetSubnetNetworkData(&sidecar, network)
 			if err != nil {
-				return fmt.Errorf("failed to install subnet-evm: %w", err)
+				return err
+			}
+			if !deployed {
+				return fmt.Errorf("subnet %s is not deployed on %s. Deploy it first with avalanche subnet deploy %s", subnetName, network.Name(), subnetName)
 			}
-		case models.CustomVM:
-			vmBin = binutils.SetupCustomBin(app, chain)
-		default:
-			return fmt.Errorf("unknown vm: %s", sidecar.VM)
+			subnetIDStr = subnetData.SubnetID.String()
 		}
+	}
+
+	ux.Logger.PrintToUser("Deploying %s to %s", chain, network.Name())
+
+	if dryRun && network.Kind == models.Local {
+		return fmt.Errorf("--%s is only supported on public networks", dryRunFlag)
+	}
+
+	hooks, err := newDeployHookRunner(chain, network, skipHooks || dryRun)
+	if err != nil {
+		return err
+	}
+
+	if network.Kind == models.Local {
+		app.Log.Debug("Deploy local")
 
 		// check if selected version matches what is currently running
 		nc := localnetworkinterface.NewStatusChecker()
@@ -356,29 +449,41 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 		if avagoBinaryPath == "" {
 			userProvidedAvagoVersion = avagoVersion
 		}
+		subnetID := ids.Empty
+		if subnetIDStr != "" {
+			subnetID, err = ids.FromString(subnetIDStr)
+			if err != nil {
+				return err
+			}
+		}
 
-		deployer := subnet.NewLocalDeployer(app, userProvidedAvagoVersion, avagoBinaryPath, vmBin)
-		deployInfo, err := deployer.DeployToLocalNetwork(chain, chainGenesis, genesisPath, skipLocalTeleporter, subnetIDStr)
+		if err := hooks.preDeploy(); err != nil {
+			return err
+		}
+		result, err := sdk.New(app).DeployLocal(sdk.LocalDeployOptions{
+			SubnetName:            chain,
+			AvalancheGoVersion:    userProvidedAvagoVersion,
+			AvalancheGoBinaryPath: avagoBinaryPath,
+			SubnetID:              subnetID,
+			SkipTeleporter:        skipLocalTeleporter,
+			SkipGenesisValidation: skipGenesisValidation,
+		})
 		if err != nil {
-			if deployer.BackendStartedHere() {
-				if innerErr := binutils.KillgRPCServerProcess(app); innerErr != nil {
-					app.Log.Warn("tried to kill the gRPC server process but it failed", zap.Error(innerErr))
-				}
-			}
+			return err
+		}
+		if err := hooks.postDeploy(
+			result.SubnetID,
+			result.BlockchainID,
+			network.BlockchainEndpoint(result.BlockchainID.String()),
+			result.TeleporterMessengerAddress,
+			result.TeleporterRegistryAddress,
+		); err != nil {
 			return err
 		}
 		flags := make(map[string]string)
 		flags[constants.Network] = network.Name()
 		metrics.HandleTracking(cmd, app, flags)
-		return app.UpdateSidecarNetworks(
-			&sidecar,
-			network,
-			deployInfo.SubnetID,
-			ids.Empty,
-			deployInfo.BlockchainID,
-			deployInfo.TeleporterMessengerAddress,
-			deployInfo.TeleporterRegistryAddress,
-		)
+		return nil
 	}
 
 	// from here on we are assuming a public deploy
@@ -386,6 +491,48 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 		return errMutuallyExlusiveSubnetFlags
 	}
 
+	// resume an interrupted deploy from the step that failed. Dry runs only
+	// show what would be resumed, leaving the sidecar untouched
+	var (
+		pending        *sdk.PendingDeploy
+		pendingSidecar *models.Sidecar
+	)
+	if dryRun {
+		pending, pendingSidecar, err = sdk.New(app).PreviewPendingDeploy(chain, network)
+	} else {
+		pending, err = sdk.New(app).CheckPendingDeploy(chain, network)
+	}
+	if err != nil {
+		return err
+	}
+	if pending != nil {
+		if pending.BlockchainID != ids.Empty {
+			ux.Logger.PrintToUser("The interrupted deploy of %s to %s already created its blockchain", chain, network.Name())
+			if err := PrintDeployResults(chain, pending.SubnetID, pending.BlockchainID); err != nil {
+				return err
+			}
+			if !dryRun {
+				hooks.deferPostDeploy()
+			}
+			return nil
+		}
+		if dryRun {
+			sidecar = *pendingSidecar
+		} else {
+			sidecar, err = app.LoadSidecar(chain)
+			if err != nil {
+				return err
+			}
+		}
+		if pending.SubnetID != ids.Empty {
+			if dryRun {
+				ux.Logger.PrintToUser("The deploy would resume the interrupted deploy of %s to %s", chain, network.Name())
+			} else {
+				ux.Logger.PrintToUser("Resuming the interrupted deploy of %s to %s", chain, network.Name())
+			}
+		}
+	}
+
 	createSubnet := true
 	var subnetID, transferSubnetOwnershipTxID ids.ID
 	if subnetIDStr != "" {
@@ -393,10 +540,13 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+		if subnetData, _, err := app.GetSubnetNetworkData(&sidecar, network); err == nil && subnetData.SubnetID == subnetID {
+			transferSubnetOwnershipTxID = subnetData.TransferSubnetOwnershipTxID
+		}
 		createSubnet = false
-	} else if !subnetOnly && sidecar.Networks != nil {
+	} else if sidecar.Networks != nil {
 		model, ok := sidecar.Networks[network.Name()]
-		if ok {
+		if ok && (!subnetOnly || model.Pending) {
 			if model.SubnetID != ids.Empty && model.BlockchainID == ids.Empty {
 				subnetID = model.SubnetID
 				transferSubnetOwnershipTxID = model.TransferSubnetOwnershipTxID
@@ -473,58 +623,60 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 	}
 	ux.Logger.PrintToUser("Your subnet auth keys for chain creation: %s", subnetAuthKeys)
 
-	// deploy to public network
-	deployer := subnet.NewPublicDeployer(app, kc, network)
-
-	if createSubnet {
-		subnetID, err = deployer.DeploySubnet(controlKeys, threshold)
-		if err != nil {
-			return err
+	if dryRun {
+		plan := subnet.NewTxPlan(app, kc, network, subnetID, transferSubnetOwnershipTxID)
+		if createSubnet {
+			if err := plan.CreateSubnet(controlKeys, threshold); err != nil {
+				return err
+			}
 		}
-		// get the control keys in the same order as the tx
-		controlKeys, threshold, err = txutils.GetOwners(network, subnetID, ids.Empty)
-		if err != nil {
-			return err
+		if !subnetOnly {
+			if err := plan.CreateChain(subnetID, controlKeys, threshold, subnetAuthKeys, chain, chainGenesis); err != nil {
+				return err
+			}
 		}
+		return printTxPlan(plan, network)
 	}
 
-	var (
-		savePartialTx           bool
-		blockchainID            ids.ID
-		tx                      *txs.Tx
-		remainingSubnetAuthKeys []string
-		isFullySigned           bool
-	)
-
-	if !subnetOnly {
-		isFullySigned, blockchainID, tx, remainingSubnetAuthKeys, err = deployer.DeployBlockchain(
-			controlKeys,
-			subnetAuthKeys,
-			subnetID,
-			transferSubnetOwnershipTxID,
-			chain,
-			chainGenesis,
-		)
-		if err != nil {
-			ux.Logger.PrintToUser(logging.Red.Wrap(
-				fmt.Sprintf("error deploying blockchain: %s. fix the issue and try again with a new deploy cmd", err),
-			))
-		}
+	if err := hooks.preDeploy(); err != nil {
+		return err
+	}
 
-		savePartialTx = !isFullySigned && err == nil
+	// deploy to public network
+	opts := sdk.DeployOptions{
+		SubnetName:            chain,
+		Network:               network,
+		Keychain:              kc,
+		Genesis:               chainGenesis,
+		ControlKeys:           controlKeys,
+		Threshold:             threshold,
+		SubnetAuthKeys:        subnetAuthKeys,
+		SubnetOnly:            subnetOnly,
+		SkipGenesisValidation: skipGenesisValidation,
+	}
+	if !createSubnet {
+		opts.SubnetID = subnetID
+	}
+	result, err := sdk.New(app).Deploy(opts)
+	if errors.Is(err, sdk.ErrDeployBlockchain) {
+		ux.Logger.PrintToUser(logging.Red.Wrap(
+			fmt.Sprintf("%s. fix the issue and try again with a new deploy cmd", err),
+		))
+	} else if err != nil {
+		return err
 	}
 
-	if err := PrintDeployResults(chain, subnetID, blockchainID); err != nil {
+	if err := PrintDeployResults(chain, result.SubnetID, result.BlockchainID); err != nil {
 		return err
 	}
 
-	if savePartialTx {
+	if result.BlockchainTx != nil && !result.BlockchainTx.FullySigned {
 		if err := SaveNotFullySignedTx(
 			"Blockchain Creation",
-			tx,
+			result.BlockchainTx.Tx,
 			chain,
 			subnetAuthKeys,
-			remainingSubnetAuthKeys,
+			result.BlockchainTx.RemainingSubnetAuthKeys,
 			outputTxPath,
 			false,
 		); err != nil {
@@ -532,28 +684,14 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if isFullySigned {
-		if network.ClusterName != "" {
-			clusterConfig, err := app.GetClusterConfig(network.ClusterName)
-			if err != nil {
-				return err
-			}
-			if _, err := utils.GetIndexInSlice(clusterConfig.Subnets, subnetName); err != nil {
-				clusterConfig.Subnets = append(clusterConfig.Subnets, subnetName)
-			}
-			if err := app.SetClusterConfig(network.ClusterName, clusterConfig); err != nil {
-				return err
-			}
-		}
+	if result.BlockchainID != ids.Empty {
+		hooks.deferPostDeploy()
 	}
 
 	flags := make(map[string]string)
 	flags[constants.Network] = network.Name()
 	metrics.HandleTracking(cmd, app, flags)
-
-	// update sidecar
-	// TODO: need to do something for backwards compatibility?
-	return app.UpdateSidecarNetworks(&sidecar, network, subnetID, transferSubnetOwnershipTxID, blockchainID, "", "")
+	return nil
 }
 
 func getControlKeys(kc *keychain.Keychain) ([]string, bool, error) {
@@ -897,20 +1035,6 @@ func HasSubnetEVMGenesis(subnetName string) (bool, error) {
 	return true, nil
 }
 
-func jsonIsSubnetEVMGenesis(jsonBytes []byte) bool {
-	genesis, err := app.LoadEvmGenesisFromJSON(jsonBytes)
-	if err != nil {
-		return false
-	}
-	genesis.Config.AvalancheContext = params.AvalancheContext{
-		SnowCtx: &snow.Context{},
-	}
-	if err := genesis.Verify(); err != nil {
-		return false
-	}
-	return true
-}
-
 func promptOwners(
 	kc *keychain.Keychain,
 	controlKeys []string,
@@ -0,0 +1,99 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/deployhooks"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// deployHookRunner runs the deploy hooks configured for a chain, keeping
+// the results of all stages to record them together in the sidecar
+type deployHookRunner struct {
+	chain   string
+	network models.Network
+	hooks   models.DeployHooks
+	results []models.DeployHookResult
+}
+
+func newDeployHookRunner(chain string, network models.Network, skip bool) (*deployHookRunner, error) {
+	runner := &deployHookRunner{chain: chain, network: network}
+	if skip {
+		return runner, nil
+	}
+	hooks, err := app.LoadDeployHooks(chain)
+	if err != nil {
+		return nil, err
+	}
+	runner.hooks = hooks
+	return runner, nil
+}
+
+func (r *deployHookRunner) preDeploy() error {
+	return r.run(r.hooks.PreDeploy, deployhooks.DeployInfo{
+		SubnetName: r.chain,
+		Network:    r.network.Name(),
+		Stage:      models.PreDeployStage,
+	})
+}
+
+// postDeploy runs the post-deploy hooks against the chain served at [rpcURL]
+func (r *deployHookRunner) postDeploy(
+	subnetID ids.ID,
+	blockchainID ids.ID,
+	rpcURL string,
+	teleporterMessengerAddress string,
+	teleporterRegistryAddress string,
+) error {
+	return r.run(r.hooks.PostDeploy, deployhooks.DeployInfo{
+		SubnetName:                 r.chain,
+		Network:                    r.network.Name(),
+		Stage:                      models.PostDeployStage,
+		SubnetID:                   subnetID.String(),
+		BlockchainID:               blockchainID.String(),
+		RPCURL:                     rpcURL,
+		TeleporterMessengerAddress: teleporterMessengerAddress,
+		TeleporterRegistryAddress:  teleporterRegistryAddress,
+	})
+}
+
+// deferPostDeploy tells how to run the post-deploy hooks of a chain deployed
+// into a public network, and to check its genesis contracts, once its
+// validators serve it
+func (r *deployHookRunner) deferPostDeploy() {
+	steps := []string{}
+	if len(r.hooks.PostDeploy) > 0 {
+		steps = append(steps, "its post-deploy hooks")
+	}
+	if contracts := genesisContracts(r.chain); len(contracts) > 0 {
+		steps = append(steps, fmt.Sprintf("the check of its %d genesis contracts", len(contracts)))
+	}
+	if len(steps) == 0 {
+		return
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("%s needs to be served by its validators to run %s.", r.chain, strings.Join(steps, " and "))
+	ux.Logger.PrintToUser("Once they have joined it and bootstrapped, run avalanche subnet hooks run %s", r.chain)
+}
+
+func (r *deployHookRunner) run(hooks []models.DeployHook, info deployhooks.DeployInfo) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	results, runErr := deployhooks.Run(app, r.network, hooks, info)
+	r.results = append(r.results, results...)
+	// the deploy may have updated the sidecar since it was loaded
+	sc, err := app.LoadSidecar(r.chain)
+	if err != nil {
+		return err
+	}
+	if err := app.UpdateSidecarDeployHookResults(&sc, r.network, r.results); err != nil {
+		return err
+	}
+	return runErr
+}
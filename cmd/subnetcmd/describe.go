@@ -83,10 +83,10 @@ func printDetails(genesis core.Genesis, sc models.Sidecar) {
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	table.SetAutoMergeCellsByColumnIndex([]int{0})
 
-	table.Append([]string{"Subnet Name", sc.Subnet})
+	table.Append([]string{"Subnet Name", sc.GetSubnetName()})
 	table.Append([]string{"ChainID", genesis.Config.ChainID.String()})
 	table.Append([]string{"Mainnet ChainID", fmt.Sprint(sc.SubnetEVMMainnetChainID)})
-	table.Append([]string{"Token Name", app.GetTokenName(sc.Subnet)})
+	table.Append([]string{"Token Name", app.GetTokenName(sc.Name)})
 	table.Append([]string{"VM Version", sc.VMVersion})
 	if sc.ImportedVMID != "" {
 		table.Append([]string{"VM ID", sc.ImportedVMID})
@@ -274,7 +274,7 @@ func appendToAddressTable(
 
 func describeSubnetEvmGenesis(sc models.Sidecar) error {
 	// Load genesis
-	genesis, err := app.LoadEvmGenesis(sc.Subnet)
+	genesis, err := app.LoadEvmGenesis(sc.Name)
 	if err != nil {
 		return err
 	}
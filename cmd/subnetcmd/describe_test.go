@@ -0,0 +1,83 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/tests/e2e/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func captureStdout(t *testing.T, f func() error) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() {
+		os.Stdout = stdout
+	}()
+	outCh := make(chan []byte)
+	go func() {
+		out, _ := io.ReadAll(r)
+		outCh <- out
+	}()
+	err = f()
+	require.NoError(t, w.Close())
+	os.Stdout = stdout
+	require.NoError(t, err)
+	return string(<-outCh)
+}
+
+func TestDescribeAddedChain(t *testing.T) {
+	require := require.New(t)
+	app = application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, prompts.NewPrompter(), nil)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	defer func() {
+		app = nil
+	}()
+
+	parentGenesis, err := os.ReadFile("../../" + utils.SubnetEvmGenesisPath)
+	require.NoError(err)
+	var genesis map[string]interface{}
+	require.NoError(json.Unmarshal(parentGenesis, &genesis))
+	genesis["config"].(map[string]interface{})["chainId"] = 88888
+	genesis["config"].(map[string]interface{})["feeConfig"].(map[string]interface{})["gasLimit"] = 12345678
+	genesis["gasLimit"] = "0xbc614e"
+	genesis["alloc"] = map[string]interface{}{
+		"0x1111111111111111111111111111111111111111": map[string]interface{}{"balance": "0x1"},
+	}
+	chainGenesis, err := json.Marshal(genesis)
+	require.NoError(err)
+
+	for _, sc := range []models.Sidecar{
+		{Name: "parent", VM: models.SubnetEvm, Subnet: "parent", TokenName: "PARENT"},
+		{Name: "chain", VM: models.SubnetEvm, Subnet: "parent", TokenName: "CHAIN"},
+	} {
+		genesisBytes := parentGenesis
+		if sc.Name == "chain" {
+			genesisBytes = chainGenesis
+		}
+		require.NoError(app.WriteGenesisFile(sc.Name, genesisBytes))
+		require.NoError(app.CreateSidecar(&sc))
+	}
+
+	out := captureStdout(t, func() error { return readGenesis(nil, []string{"chain"}) })
+	require.Contains(out, "parent")
+	require.Contains(out, "CHAIN")
+	require.NotContains(out, "PARENT")
+	require.Contains(out, "88888")
+	require.NotContains(out, "99999")
+	require.Contains(out, "12345678")
+	require.Contains(out, "0x1111111111111111111111111111111111111111")
+	require.NotContains(out, "8db97c7cece249c2b98bdc0226cc4c2a57bf52fc")
+}
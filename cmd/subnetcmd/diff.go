@@ -0,0 +1,288 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/diff"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/sdk"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	diffGenesisSection      = "genesis"
+	diffChainConfigSection  = "chain config"
+	diffSubnetConfigSection = "subnet config"
+	diffUpgradeSection      = "upgrade bytes"
+	diffSidecarSection      = "sidecar"
+	diffDeploymentSection   = "deployment"
+
+	// values longer than this are truncated in the output table
+	diffMaxValueLen = 80
+)
+
+// diffSections lists every section in output order. Local subnets provide
+// all but deployment, live chains provide genesis and deployment
+var diffSections = []string{
+	diffGenesisSection,
+	diffChainConfigSection,
+	diffSubnetConfigSection,
+	diffUpgradeSection,
+	diffSidecarSection,
+	diffDeploymentSection,
+}
+
+// diffSource is one side of a subnet diff. Sections maps the section names
+// the source supports to their JSON contents, nil when the source supports
+// the section but has no such file
+type diffSource struct {
+	label    string
+	sections map[string][]byte
+}
+
+// avalanche subnet diff
+func newDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff [a] [b]",
+		Short: "Compare the configuration of two subnets",
+		Long: `The subnet diff command compares two subnets semantically: key order, number
+formatting, hex casing and the order of address lists are ignored, and every
+difference is reported with the path of the setting that changed.
+
+Each side is either:
+  - a subnet name, whose genesis (fee config, precompiles, allocations...),
+    chain config, subnet config, upgrade bytes and sidecar are compared
+  - subnetName@network, the genesis and deployment info of the chain deployed
+    for that subnet on network, fetched from the P-Chain
+  - blockchainID@network, the genesis and deployment info of any chain
+
+network is one of local, fuji, mainnet or a cluster name. Live chains are
+fetched the same way subnet import public does. Only the sections both sides
+provide are compared, so diffing a subnet against a live chain checks that
+the deployed genesis matches the local one.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		RunE:         diffSubnets,
+	}
+}
+
+func diffSubnets(_ *cobra.Command, args []string) error {
+	a, err := loadDiffSource(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := loadDiffSource(args[1])
+	if err != nil {
+		return err
+	}
+	table := tablewriter.NewWriter(ux.Logger.Writer)
+	table.SetHeader([]string{"Section", "Path", "Change", a.label, b.label})
+	table.SetRowLine(true)
+	differences := 0
+	compared := []string{}
+	for _, section := range diffSections {
+		aBytes, aOk := a.sections[section]
+		bBytes, bOk := b.sections[section]
+		if !aOk || !bOk {
+			continue
+		}
+		compared = append(compared, section)
+		switch {
+		case aBytes == nil && bBytes == nil:
+			continue
+		case aBytes == nil:
+			table.Append([]string{section, "", string(diff.Added), "(no file)", "(file)"})
+			differences++
+			continue
+		case bBytes == nil:
+			table.Append([]string{section, "", string(diff.Removed), "(file)", "(no file)"})
+			differences++
+			continue
+		}
+		changes, err := diff.JSON(aBytes, bBytes)
+		if err != nil {
+			// custom VM genesis are not necessarily JSON
+			if !bytes.Equal(aBytes, bBytes) {
+				table.Append([]string{section, "", string(diff.Changed), "(content)", "(different content)"})
+				differences++
+			}
+			continue
+		}
+		for _, change := range changes {
+			table.Append([]string{
+				section,
+				change.Path,
+				string(change.Kind),
+				formatDiffValue(change.Old, change.Kind == diff.Added),
+				formatDiffValue(change.New, change.Kind == diff.Removed),
+			})
+		}
+		differences += len(changes)
+	}
+	if len(compared) == 0 {
+		return fmt.Errorf("%s and %s have no section in common to compare", a.label, b.label)
+	}
+	ux.Logger.PrintToUser("Compared sections: %s", strings.Join(compared, ", "))
+	if differences == 0 {
+		ux.Logger.GreenCheckmarkToUser("No differences between %s and %s", a.label, b.label)
+		return nil
+	}
+	table.Render()
+	ux.Logger.PrintToUser("%d difference(s) between %s and %s", differences, a.label, b.label)
+	return nil
+}
+
+func formatDiffValue(v interface{}, absent bool) string {
+	if absent {
+		return "(absent)"
+	}
+	s := diff.FormatValue(v)
+	if len(s) > diffMaxValueLen {
+		s = s[:diffMaxValueLen] + "..."
+	}
+	return s
+}
+
+// loadDiffSource resolves [arg], either subnetName, subnetName@network or
+// blockchainID@network
+func loadDiffSource(arg string) (*diffSource, error) {
+	name, networkName, live := strings.Cut(arg, "@")
+	if !live {
+		return loadLocalDiffSource(name)
+	}
+	network, err := getDiffNetwork(networkName)
+	if err != nil {
+		return nil, err
+	}
+	var blockchainID ids.ID
+	if app.SidecarExists(name) {
+		sc, err := app.LoadSidecar(name)
+		if err != nil {
+			return nil, err
+		}
+		blockchainID = sc.Networks[network.Name()].BlockchainID
+		if blockchainID == ids.Empty {
+			return nil, fmt.Errorf("subnet %s is not deployed on %s", name, network.Name())
+		}
+	} else {
+		blockchainID, err = ids.FromString(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s is neither a subnet nor a blockchain ID", name)
+		}
+	}
+	return loadLiveDiffSource(arg, network, blockchainID)
+}
+
+func getDiffNetwork(networkName string) (models.Network, error) {
+	switch strings.ToLower(networkName) {
+	case "local":
+		return models.NewLocalNetwork(), nil
+	case "fuji", "testnet":
+		return models.NewFujiNetwork(), nil
+	case "mainnet":
+		return models.NewMainnetNetwork(), nil
+	}
+	exists, err := app.ClusterExists(networkName)
+	if err != nil {
+		return models.UndefinedNetwork, err
+	}
+	if !exists {
+		return models.UndefinedNetwork, fmt.Errorf("unknown network %q: expected local, fuji, mainnet or a cluster name", networkName)
+	}
+	return app.GetClusterNetwork(networkName)
+}
+
+func loadLocalDiffSource(subnetName string) (*diffSource, error) {
+	if !app.SidecarExists(subnetName) {
+		return nil, fmt.Errorf("subnet %s does not exist", subnetName)
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	source := &diffSource{label: subnetName, sections: map[string][]byte{}}
+	for section, path := range map[string]string{
+		diffGenesisSection:      app.GetGenesisPath(subnetName),
+		diffChainConfigSection:  app.GetChainConfigPath(subnetName),
+		diffSubnetConfigSection: app.GetAvagoSubnetConfigPath(subnetName),
+		diffUpgradeSection:      app.GetUpgradeBytesFilePath(subnetName),
+	} {
+		source.sections[section] = nil
+		if !utils.FileExists(path) {
+			continue
+		}
+		fileBytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		source.sections[section] = fileBytes
+	}
+	source.sections[diffGenesisSection] = canonicalGenesis(source.sections[diffGenesisSection])
+	source.sections[diffSidecarSection], err = sidecarDiffBytes(sc)
+	if err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func loadLiveDiffSource(label string, network models.Network, blockchainID ids.ID) (*diffSource, error) {
+	ux.Logger.PrintToUser("Getting %s from the %s network...", blockchainID, network.Name())
+	createChainTx, err := getCreateChainTx(network, blockchainID)
+	if err != nil {
+		return nil, err
+	}
+	deployment, err := json.Marshal(map[string]string{
+		"chainName":    createChainTx.ChainName,
+		"vmID":         createChainTx.VMID.String(),
+		"subnetID":     createChainTx.SubnetID.String(),
+		"blockchainID": blockchainID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &diffSource{
+		label: label,
+		sections: map[string][]byte{
+			diffGenesisSection:    canonicalGenesis(createChainTx.GenesisData),
+			diffDeploymentSection: deployment,
+		},
+	}, nil
+}
+
+// canonicalGenesis round trips Subnet-EVM genesis through its go type, so that
+// equivalent encodings (eg. hex or decimal numbers, 0x prefixed or bare alloc
+// addresses) compare equal. Other genesis are returned as is
+func canonicalGenesis(genesisBytes []byte) []byte {
+	if genesisBytes == nil || !sdk.IsSubnetEVMGenesis(genesisBytes) {
+		return genesisBytes
+	}
+	var genesis core.Genesis
+	if err := json.Unmarshal(genesisBytes, &genesis); err != nil {
+		return genesisBytes
+	}
+	canonical, err := json.Marshal(&genesis)
+	if err != nil {
+		return genesisBytes
+	}
+	return canonical
+}
+
+// sidecarDiffBytes returns the sidecar without the fields that always differ
+// between two subnets
+func sidecarDiffBytes(sc models.Sidecar) ([]byte, error) {
+	sc.Name = ""
+	sc.Subnet = ""
+	sc.Version = ""
+	return json.Marshal(sc)
+}
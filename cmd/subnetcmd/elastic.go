@@ -58,7 +58,45 @@ func newElasticCmd() *cobra.Command {
 P-Chain. When enabling Elastic Validation, the creator permanently locks the Subnet from future modification 
 (they relinquish their control keys), specifies an Avalanche Native Token (ANT) that validators must use for staking 
 and that will be distributed as staking rewards, and provides a set of parameters that govern how the Subnet’s staking 
-mechanics will work.`,
+mechanics will work.
+
+Use --dry-run to build the transformation transactions and check their fees, the keychain balances and
+the control key signatures without issuing them.
+
+Use --config to read the token and the staking parameters from a YAML file instead of prompting for them.
+Rates, fees and uptime are given as percentages, durations accept the d and w units, and omitted parameters
+take the Mainnet values. The parameters are validated against the constraints the P-Chain enforces on the
+transformation. For example:
+
+  tokenName: My Token
+  tokenSymbol: MTK
+  denomination: 9
+  initialSupply: 240000000
+  maxSupply: 720000000
+  minConsumptionRate: 10
+  maxConsumptionRate: 12
+  minValidatorStake: 2000
+  maxValidatorStake: 3000000
+  minStakeDuration: 2w
+  maxStakeDuration: 365d
+  minDelegationFee: 2
+  minDelegatorStake: 25
+  maxValidatorWeightFactor: 5
+  uptimeRequirement: 80
+  preview:
+    - stake: 10000
+      duration: 30d
+      delegationFee: 5
+      delegators:
+        - stake: 500
+          duration: 2w
+
+The validators and delegators under preview are used to show the rewards they would get before the
+transformation is issued. Use --calculate to only show the config and those projected rewards, without
+transforming the subnet. Without a preview, the min and max stakes and durations are projected.
+
+Use elastic status to show the staking parameters, supply and stakers of an elastic subnet as read from
+the P-Chain, and to compare them with the local records.`,
 		SilenceUsage:      true,
 		Args:              cobra.ExactArgs(1),
 		RunE:              transformElasticSubnet,
@@ -79,6 +117,10 @@ mechanics will work.`,
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji only]")
 	cmd.Flags().StringSliceVar(&subnetAuthKeys, "subnet-auth-keys", nil, "control keys that will be used to authenticate the transformSubnet tx")
 	cmd.Flags().StringVar(&outputTxPath, "output-tx-path", "", "file path of the transformSubnet tx")
+	cmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "build the transformation txs and check fees, balances and signers without issuing them")
+	cmd.Flags().StringVar(&elasticConfigPath, "config", "", "read the token and elastic subnet parameters from this YAML file")
+	cmd.Flags().BoolVar(&calculateRewards, "calculate", false, "only show the elastic subnet config and the projected staking rewards")
+	cmd.AddCommand(newElasticStatusCmd())
 	return cmd
 }
 
@@ -145,6 +187,25 @@ func importFromXChain(deployer *subnet.PublicDeployer,
 func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 	subnetName := args[0]
 
+	configFile, fileElasticSubnetConfig, err := loadElasticConfigFile()
+	if err != nil {
+		return err
+	}
+	if calculateRewards {
+		return calculateElasticRewards(configFile, fileElasticSubnetConfig)
+	}
+	if configFile != nil {
+		if tokenNameFlag == "" {
+			tokenNameFlag = configFile.TokenName
+		}
+		if tokenSymbolFlag == "" {
+			tokenSymbolFlag = configFile.TokenSymbol
+		}
+		if denominationFlag == -1 && configFile.Denomination != nil {
+			denominationFlag = *configFile.Denomination
+		}
+	}
+
 	if err := DeploySubnetFirst(cmd, subnetName, false, elasticSupportedNetworkOptions); err != nil {
 		return err
 	}
@@ -165,6 +226,10 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if dryRun && network.Kind == models.Local {
+		return fmt.Errorf("--%s is only supported on public networks", dryRunFlag)
+	}
+
 	if outputTxPath != "" {
 		if _, err := os.Stat(outputTxPath); err == nil {
 			return fmt.Errorf("outputTxPath %q already exists", outputTxPath)
@@ -229,9 +294,20 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	elasticSubnetConfig, err := es.GetElasticSubnetConfig(app, tokenSymbol, useDefaultConfig)
-	if err != nil {
-		return err
+	var elasticSubnetConfig models.ElasticSubnetConfig
+	if fileElasticSubnetConfig != nil {
+		elasticSubnetConfig = *fileElasticSubnetConfig
+		printElasticSubnetConfig(elasticSubnetConfig)
+		if len(configFile.Preview) > 0 {
+			if err := previewElasticRewards(elasticSubnetConfig, configFile.Preview, tokenSymbol); err != nil {
+				return err
+			}
+		}
+	} else {
+		elasticSubnetConfig, err = es.GetElasticSubnetConfig(app, tokenSymbol, useDefaultConfig)
+		if err != nil {
+			return err
+		}
 	}
 	elasticSubnetConfig.SubnetID = subnetID
 
@@ -261,6 +337,52 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 	}
 
 	recipientAddr := kc.Addresses().List()[0]
+
+	transferSubnetOwnershipTxID := sc.Networks[network.Name()].TransferSubnetOwnershipTxID
+
+	controlKeys, threshold, err := txutils.GetOwners(network, subnetID, transferSubnetOwnershipTxID)
+	if err != nil {
+		return err
+	}
+
+	// add control keys to the keychain whenever possible
+	if err := kc.AddAddresses(controlKeys); err != nil {
+		return err
+	}
+
+	kcKeys, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return err
+	}
+
+	// get keys for add validator tx signing
+	if subnetAuthKeys != nil {
+		if err := prompts.CheckSubnetAuthKeys(kcKeys, subnetAuthKeys, controlKeys, threshold); err != nil {
+			return err
+		}
+	} else {
+		subnetAuthKeys, err = prompts.GetSubnetAuthKeys(app.Prompt, kcKeys, controlKeys, threshold)
+		if err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("Your subnet auth keys for issue transform subnet tx: %s", subnetAuthKeys)
+
+	if dryRun {
+		return planElasticTransform(
+			sc,
+			network,
+			kc,
+			controlKeys,
+			threshold,
+			elasticSubnetConfig,
+			tokenName,
+			tokenSymbol,
+			tokenDenomination,
+			recipientAddr,
+		)
+	}
+
 	deployer := subnet.NewPublicDeployer(app, kc, network)
 	txHasOccurred, txID := checkIfTxHasOccurred(&sc, network, "CreateAssetTx")
 	var assetID ids.ID
@@ -311,36 +433,6 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 		ux.Logger.PrintToUser("Skipping ImportTx...")
 	}
 
-	transferSubnetOwnershipTxID := sc.Networks[network.Name()].TransferSubnetOwnershipTxID
-
-	controlKeys, threshold, err := txutils.GetOwners(network, subnetID, transferSubnetOwnershipTxID)
-	if err != nil {
-		return err
-	}
-
-	// add control keys to the keychain whenever possible
-	if err := kc.AddAddresses(controlKeys); err != nil {
-		return err
-	}
-
-	kcKeys, err := kc.PChainFormattedStrAddresses()
-	if err != nil {
-		return err
-	}
-
-	// get keys for add validator tx signing
-	if subnetAuthKeys != nil {
-		if err := prompts.CheckSubnetAuthKeys(kcKeys, subnetAuthKeys, controlKeys, threshold); err != nil {
-			return err
-		}
-	} else {
-		subnetAuthKeys, err = prompts.GetSubnetAuthKeys(app.Prompt, kcKeys, controlKeys, threshold)
-		if err != nil {
-			return err
-		}
-	}
-	ux.Logger.PrintToUser("Your subnet auth keys for issue transform subnet tx: %s", subnetAuthKeys)
-
 	isFullySigned, txID, tx, remainingSubnetAuthKeys, err := deployer.TransformSubnetTx(
 		controlKeys,
 		subnetAuthKeys,
@@ -385,6 +477,55 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// planElasticTransform prints the txs that are still pending to transform
+// the subnet into an elastic one, without issuing them
+func planElasticTransform(
+	sc models.Sidecar,
+	network models.Network,
+	kc *keychain.Keychain,
+	controlKeys []string,
+	threshold uint32,
+	elasticSubnetConfig models.ElasticSubnetConfig,
+	tokenName string,
+	tokenSymbol string,
+	tokenDenomination int,
+	recipientAddr ids.ShortID,
+) error {
+	if tokenDenomination > math.MaxUint8 {
+		return errors.New("token denomination cannot exceed 32")
+	}
+	plan := subnet.NewTxPlan(
+		app,
+		kc,
+		network,
+		elasticSubnetConfig.SubnetID,
+		sc.Networks[network.Name()].TransferSubnetOwnershipTxID,
+	)
+	// steps already issued by a previous run are skipped, as the command does
+	assetCreated, assetID := checkIfTxHasOccurred(&sc, network, "CreateAssetTx")
+	if !assetCreated {
+		if err := plan.CreateAsset(tokenName, tokenSymbol, byte(tokenDenomination), elasticSubnetConfig.MaxSupply, recipientAddr); err != nil {
+			return err
+		}
+	}
+	exported, _ := checkIfTxHasOccurred(&sc, network, "ExportTx")
+	if !exported {
+		if err := plan.ExportToPChain(assetID, elasticSubnetConfig.MaxSupply, recipientAddr); err != nil {
+			return err
+		}
+	}
+	imported, _ := checkIfTxHasOccurred(&sc, network, "ImportTx")
+	if !imported {
+		if err := plan.ImportFromXChain(exported, recipientAddr); err != nil {
+			return err
+		}
+	}
+	if err := plan.TransformSubnet(controlKeys, threshold, subnetAuthKeys, elasticSubnetConfig, assetID, imported); err != nil {
+		return err
+	}
+	return printTxPlan(plan, network)
+}
+
 func transformElasticSubnetLocal(sc models.Sidecar, subnetName string, tokenName string, tokenSymbol string, elasticSubnetConfig models.ElasticSubnetConfig, cmd *cobra.Command) error {
 	if checkIfSubnetIsElasticOnLocal(sc) {
 		return fmt.Errorf("%s is already an elastic subnet", subnetName)
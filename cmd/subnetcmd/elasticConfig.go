@@ -0,0 +1,137 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+
+	es "github.com/ava-labs/avalanche-cli/pkg/elasticsubnet"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/olekukonko/tablewriter"
+)
+
+var (
+	elasticConfigPath string
+	calculateRewards  bool
+)
+
+// loadElasticConfigFile loads the elastic subnet config file given with
+// --config, if any
+func loadElasticConfigFile() (*es.ConfigFile, *models.ElasticSubnetConfig, error) {
+	if elasticConfigPath == "" {
+		return nil, nil, nil
+	}
+	if useDefaultConfig {
+		return nil, nil, fmt.Errorf("--config can't be used together with --default")
+	}
+	configFile, err := es.LoadConfigFile(elasticConfigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	elasticSubnetConfig, err := configFile.ElasticSubnetConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid elastic subnet config %s: %w", elasticConfigPath, err)
+	}
+	return configFile, &elasticSubnetConfig, nil
+}
+
+// previewElasticRewards prints the rewards the validators and delegators of
+// [preview] would get under [elasticSubnetConfig]
+func previewElasticRewards(elasticSubnetConfig models.ElasticSubnetConfig, preview []es.StakePreview, tokenSymbol string) error {
+	projections, finalSupply, err := es.ProjectRewards(elasticSubnetConfig, preview)
+	if err != nil {
+		return fmt.Errorf("invalid rewards preview: %w", err)
+	}
+	if tokenSymbol == "" {
+		tokenSymbol = "tokens"
+	}
+	ux.Logger.PrintToUser("Projected staking rewards, in %s, for stakers added in this order right after the transformation:", tokenSymbol)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Staker", "Stake", "Duration", "Reward", "Delegation Fees", "Annualized Return"})
+	table.SetRowLine(true)
+	for i, validator := range projections {
+		table.Append([]string{
+			fmt.Sprintf("Validator %d (fee %s)", i+1, formatPercent(uint64(validator.DelegationFee))),
+			ux.ConvertToStringWithThousandSeparator(validator.Stake),
+			validator.Duration.String(),
+			ux.ConvertToStringWithThousandSeparator(validator.Reward),
+			ux.ConvertToStringWithThousandSeparator(validator.DelegationFeeReward),
+			annualizedReturn(validator.Reward+validator.DelegationFeeReward, validator.Stake, validator.Duration.Hours()),
+		})
+		for j, delegator := range validator.Delegators {
+			table.Append([]string{
+				fmt.Sprintf("  Delegator %d.%d", i+1, j+1),
+				ux.ConvertToStringWithThousandSeparator(delegator.Stake),
+				delegator.Duration.String(),
+				ux.ConvertToStringWithThousandSeparator(delegator.Reward),
+				"",
+				annualizedReturn(delegator.Reward, delegator.Stake, delegator.Duration.Hours()),
+			})
+		}
+	}
+	table.Render()
+	ux.Logger.PrintToUser("Supply after these rewards: %s of a maximum of %s %s",
+		ux.ConvertToStringWithThousandSeparator(finalSupply),
+		ux.ConvertToStringWithThousandSeparator(elasticSubnetConfig.MaxSupply),
+		tokenSymbol,
+	)
+	return nil
+}
+
+func formatPercent(value uint64) string {
+	return fmt.Sprintf("%.2f%%", float64(value)*100/reward.PercentDenominator)
+}
+
+func annualizedReturn(reward uint64, stake uint64, hours float64) string {
+	if stake == 0 || hours == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.2f%%", float64(reward)/float64(stake)*(365*24/hours)*100)
+}
+
+func printElasticSubnetConfig(elasticSubnetConfig models.ElasticSubnetConfig) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Parameter", "Value"})
+	table.SetRowLine(true)
+	table.Append([]string{"Initial Supply", ux.ConvertToStringWithThousandSeparator(elasticSubnetConfig.InitialSupply)})
+	table.Append([]string{"Max Supply", ux.ConvertToStringWithThousandSeparator(elasticSubnetConfig.MaxSupply)})
+	table.Append([]string{"Min Consumption Rate", formatPercent(elasticSubnetConfig.MinConsumptionRate)})
+	table.Append([]string{"Max Consumption Rate", formatPercent(elasticSubnetConfig.MaxConsumptionRate)})
+	table.Append([]string{"Min Validator Stake", ux.ConvertToStringWithThousandSeparator(elasticSubnetConfig.MinValidatorStake)})
+	table.Append([]string{"Max Validator Stake", ux.ConvertToStringWithThousandSeparator(elasticSubnetConfig.MaxValidatorStake)})
+	table.Append([]string{"Min Stake Duration", elasticSubnetConfig.MinStakeDuration.String()})
+	table.Append([]string{"Max Stake Duration", elasticSubnetConfig.MaxStakeDuration.String()})
+	table.Append([]string{"Min Delegation Fee", formatPercent(uint64(elasticSubnetConfig.MinDelegationFee))})
+	table.Append([]string{"Min Delegator Stake", ux.ConvertToStringWithThousandSeparator(elasticSubnetConfig.MinDelegatorStake)})
+	table.Append([]string{"Max Validator Weight Factor", fmt.Sprint(elasticSubnetConfig.MaxValidatorWeightFactor)})
+	table.Append([]string{"Uptime Requirement", formatPercent(uint64(elasticSubnetConfig.UptimeRequirement))})
+	table.Render()
+}
+
+// calculateElasticRewards is subnet elastic --calculate. It shows the elastic
+// subnet config and the projected rewards, without transforming the subnet
+func calculateElasticRewards(configFile *es.ConfigFile, elasticSubnetConfig *models.ElasticSubnetConfig) error {
+	tokenSymbol := tokenSymbolFlag
+	preview := []es.StakePreview{}
+	if configFile != nil {
+		if tokenSymbol == "" {
+			tokenSymbol = configFile.TokenSymbol
+		}
+		preview = configFile.Preview
+	}
+	if elasticSubnetConfig == nil {
+		defaultConfig, err := es.GetElasticSubnetConfig(app, tokenSymbol, true)
+		if err != nil {
+			return err
+		}
+		elasticSubnetConfig = &defaultConfig
+	}
+	if len(preview) == 0 {
+		preview = es.DefaultStakePreview(*elasticSubnetConfig)
+	}
+	printElasticSubnetConfig(*elasticSubnetConfig)
+	return previewElasticRewards(*elasticSubnetConfig, preview, tokenSymbol)
+}
@@ -0,0 +1,298 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	es "github.com/ava-labs/avalanche-cli/pkg/elasticsubnet"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var reconcileValidators bool
+
+// avalanche subnet elastic status
+func newElasticStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [subnetName]",
+		Short: "Shows the staking parameters, supply and stakers of an elastic subnet",
+		Long: `The elastic status command reads the state of an elastic subnet from the P-Chain. It shows the
+staking parameters set by the transformation, the current supply of the staking asset, and the current
+validators and delegators with their stake, end time, delegation fee and potential reward.
+
+The chain state is compared with the local records of the subnet: the elastic subnet config and the
+permissionless validators added with the CLI. Use --reconcile to update the local validator records to
+match the P-Chain.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         elasticStatus,
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, elasticSupportedNetworkOptions)
+	cmd.Flags().BoolVar(&reconcileValidators, "reconcile", false, "update the local permissionless validator records to match the P-Chain")
+	return cmd
+}
+
+func elasticStatus(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return fmt.Errorf("unable to load sidecar: %w", err)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		true,
+		elasticSupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	subnetID := sc.Networks[network.Name()].SubnetID
+	if subnetID == ids.Empty {
+		return errNoSubnetID
+	}
+	record, hasRecord := sc.ElasticSubnet[network.Name()]
+
+	pClient := platformvm.NewClient(network.Endpoint)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	supply, _, err := pClient.GetCurrentSupply(ctx, subnetID)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+		if hasRecord {
+			return fmt.Errorf("%s is recorded locally as elastic on %s, but it is not an elastic subnet on the P-Chain", subnetName, network.Name())
+		}
+		return fmt.Errorf("%s is not an elastic subnet on %s", subnetName, network.Name())
+	}
+
+	ux.Logger.PrintToUser("Elastic subnet %s on %s", subnetName, network.Name())
+	ux.Logger.PrintToUser("Subnet ID: %s", subnetID)
+	discrepancies := []string{}
+	if !hasRecord {
+		discrepancies = append(discrepancies, "the subnet is elastic on the P-Chain, but there is no local record of its transformation")
+	}
+
+	var chainConfig *models.ElasticSubnetConfig
+	if record.PChainTXID != ids.Empty {
+		chainConfig, err = getTransformSubnetConfig(pClient, record.PChainTXID)
+		if err != nil {
+			return err
+		}
+	}
+	if chainConfig != nil {
+		if chainConfig.SubnetID != subnetID {
+			return fmt.Errorf("transformation tx %s is for subnet %s, not %s", record.PChainTXID, chainConfig.SubnetID, subnetID)
+		}
+		tokenSymbol := record.TokenSymbol
+		if tokenSymbol == "" {
+			tokenSymbol = "unknown symbol"
+		}
+		ux.Logger.PrintToUser("Asset ID: %s (%s)", chainConfig.AssetID, tokenSymbol)
+		ux.Logger.PrintToUser("Transformation TX ID: %s", record.PChainTXID)
+		ux.Logger.PrintToUser("Current Supply: %s of a maximum of %s",
+			ux.ConvertToStringWithThousandSeparator(supply),
+			ux.ConvertToStringWithThousandSeparator(chainConfig.MaxSupply),
+		)
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Staking parameters on the P-Chain:")
+		printElasticSubnetConfig(*chainConfig)
+		if record.AssetID != chainConfig.AssetID {
+			discrepancies = append(discrepancies, fmt.Sprintf("asset ID is %s in the sidecar and %s on-chain", record.AssetID, chainConfig.AssetID))
+		}
+		localConfig, err := app.LoadElasticSubnetConfig(subnetName)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			discrepancies = append(discrepancies, "there is no local elastic subnet config")
+		case err != nil:
+			return err
+		case localConfig.SubnetID == ids.Empty || localConfig.SubnetID == subnetID:
+			for _, diff := range es.DiffConfigs(localConfig, *chainConfig) {
+				discrepancies = append(discrepancies, "elastic subnet config "+diff)
+			}
+		}
+	} else {
+		ux.Logger.PrintToUser("Current Supply: %s", ux.ConvertToStringWithThousandSeparator(supply))
+		if hasRecord {
+			discrepancies = append(discrepancies, "the transformation tx ID is not recorded locally, so the staking parameters can't be read")
+		}
+	}
+
+	validators, err := getElasticStakers(pClient, subnetID)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("")
+	printElasticValidators(validators)
+
+	reconciliation := es.ReconcileValidators(record.Validators, validators)
+	if len(reconciliation) > 0 {
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Permissionless validators, local records vs P-Chain:")
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"NodeID", "Local TxID", "Chain TxID", "Status"})
+		table.SetRowLine(true)
+		for _, r := range reconciliation {
+			table.Append([]string{r.NodeID, formatTxID(r.LocalTxID), formatTxID(r.ChainTxID), r.Status})
+			if r.Status != es.ValidatorInSync {
+				discrepancies = append(discrepancies, fmt.Sprintf("validator %s is %s", r.NodeID, r.Status))
+			}
+		}
+		table.Render()
+	}
+
+	ux.Logger.PrintToUser("")
+	if len(discrepancies) == 0 {
+		ux.Logger.PrintToUser("Local records match the P-Chain")
+		return nil
+	}
+	ux.Logger.PrintToUser("Discrepancies between local records and the P-Chain:")
+	for _, discrepancy := range discrepancies {
+		ux.Logger.PrintToUser("  - %s", discrepancy)
+	}
+	if !reconcileValidators {
+		return nil
+	}
+	if !hasRecord {
+		return fmt.Errorf("can't reconcile the validators of %s without a local record of its transformation", subnetName)
+	}
+	record.Validators = es.ReconciledValidators(validators)
+	sc.ElasticSubnet[network.Name()] = record
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Local permissionless validator records updated to match the P-Chain")
+	return nil
+}
+
+// getTransformSubnetConfig reads the staking parameters set by the
+// transformation tx [txID]
+func getTransformSubnetConfig(pClient platformvm.Client, txID ids.ID) (*models.ElasticSubnetConfig, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	txBytes, err := pClient.GetTx(ctx, txID)
+	if err != nil {
+		return nil, fmt.Errorf("tx %s query error: %w", txID, err)
+	}
+	var tx txs.Tx
+	if _, err := txs.Codec.Unmarshal(txBytes, &tx); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal tx %s: %w", txID, err)
+	}
+	transformSubnetTx, ok := tx.Unsigned.(*txs.TransformSubnetTx)
+	if !ok {
+		return nil, fmt.Errorf("got unexpected type %T for transformation tx %s", tx.Unsigned, txID)
+	}
+	config := es.ConfigFromTransformTx(transformSubnetTx)
+	return &config, nil
+}
+
+// getElasticStakers returns the current validators of [subnetID]. The
+// P-Chain only details the delegators when asked for a single validator,
+// so validators with delegators are queried one by one
+func getElasticStakers(pClient platformvm.Client, subnetID ids.ID) ([]platformvm.ClientPermissionlessValidator, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	validators, err := pClient.GetCurrentValidators(ctx, subnetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, validator := range validators {
+		if validator.DelegatorCount == nil || *validator.DelegatorCount == 0 {
+			continue
+		}
+		ctx, cancel := utils.GetAPIContext()
+		detailed, err := pClient.GetCurrentValidators(ctx, subnetID, []ids.NodeID{validator.NodeID})
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		if len(detailed) == 1 {
+			validators[i].Delegators = detailed[0].Delegators
+		}
+	}
+	return validators, nil
+}
+
+func printElasticValidators(validators []platformvm.ClientPermissionlessValidator) {
+	if len(validators) == 0 {
+		ux.Logger.PrintToUser("The subnet has no current validators")
+		return
+	}
+	ux.Logger.PrintToUser("Current validators:")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"NodeID", "Type", "Stake", "End Time", "Delegation Fee", "Potential Reward", "Delegators"})
+	table.SetRowLine(true)
+	delegators := 0
+	for _, validator := range validators {
+		validatorType := "permissioned"
+		delegationFee := ""
+		if es.IsPermissionless(validator) {
+			validatorType = "permissionless"
+			delegationFee = fmt.Sprintf("%.2f%%", validator.DelegationFee)
+		}
+		table.Append([]string{
+			validator.NodeID.String(),
+			validatorType,
+			ux.ConvertToStringWithThousandSeparator(validator.Weight),
+			formatStakerEndTime(validator.EndTime),
+			delegationFee,
+			formatOptionalAmount(validator.PotentialReward),
+			formatOptionalAmount(validator.DelegatorCount),
+		})
+		delegators += len(validator.Delegators)
+	}
+	table.Render()
+	if delegators == 0 {
+		return
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Current delegators:")
+	table = tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Validator", "TxID", "Stake", "End Time", "Potential Reward"})
+	table.SetRowLine(true)
+	for _, validator := range validators {
+		for _, delegator := range validator.Delegators {
+			table.Append([]string{
+				validator.NodeID.String(),
+				delegator.TxID.String(),
+				ux.ConvertToStringWithThousandSeparator(delegator.Weight),
+				formatStakerEndTime(delegator.EndTime),
+				formatOptionalAmount(delegator.PotentialReward),
+			})
+		}
+	}
+	table.Render()
+}
+
+func formatStakerEndTime(endTime uint64) string {
+	return time.Unix(int64(endTime), 0).UTC().Format(constants.TimeParseLayout) + " UTC"
+}
+
+func formatOptionalAmount(amount *uint64) string {
+	if amount == nil {
+		return ""
+	}
+	return ux.ConvertToStringWithThousandSeparator(*amount)
+}
+
+func formatTxID(txID ids.ID) string {
+	if txID == ids.Empty {
+		return "-"
+	}
+	return txID.String()
+}
@@ -45,6 +45,10 @@ func TestExportImportSubnet(t *testing.T) {
 		"",
 		false,
 		false,
+		"",
+		nil,
+		nil,
+		"",
 	)
 	require.NoError(err)
 	err = app.WriteGenesisFile(testSubnet, genBytes)
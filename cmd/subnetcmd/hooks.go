@@ -0,0 +1,153 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hooksSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Fuji, networkoptions.Mainnet, networkoptions.Cluster, networkoptions.Devnet}
+
+	hooksRPCURL string
+)
+
+// avalanche subnet hooks
+func newHooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Run the deploy hooks of a subnet",
+		Long: `The subnet hooks command suite runs the deploy hooks set with
+avalanche subnet configure <subnetName> --deploy-hooks <file>.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newHooksRunCmd())
+	return cmd
+}
+
+// avalanche subnet hooks run
+func newHooksRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [subnetName]",
+		Short: "Run the post-deploy hooks of a deployed subnet",
+		Long: `The subnet hooks run command runs the post-deploy hooks of a subnet deployed
+into the given network, and records their results in the sidecar.
+
+Post-deploy hooks run right after local deploys. On other networks the chain is
+only served once its validators have joined and bootstrapped it, so they are run
+with this command instead. The hooks talk to the chain through its RPC URL on the
+network API endpoint. Use --rpc-url to point them to another node serving the
+chain, such as one of its validators.
+
+Before the hooks, the command checks that the genesis contracts of Subnet-EVM
+chains are deployed, as local deploys do.`,
+		RunE:         runPostDeployHooks,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, hooksSupportedNetworkOptions)
+	cmd.Flags().StringVar(&hooksRPCURL, "rpc-url", "", "RPC URL of the chain the hooks talk to (defaults to the one of the network API endpoint)")
+	return cmd
+}
+
+func runPostDeployHooks(_ *cobra.Command, args []string) error {
+	chain := args[0]
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		false,
+		hooksSupportedNetworkOptions,
+		chain,
+	)
+	if err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(chain)
+	if err != nil {
+		return err
+	}
+	blockchainID := sc.Networks[network.Name()].BlockchainID
+	if blockchainID == ids.Empty {
+		return fmt.Errorf("%s is not deployed into %s", chain, network.Name())
+	}
+	subnetData, _, err := app.GetSubnetNetworkData(&sc, network)
+	if err != nil {
+		return err
+	}
+	rpcURL := hooksRPCURL
+	if rpcURL == "" {
+		rpcURL = network.BlockchainEndpoint(blockchainID.String())
+	}
+	if err := checkChainServed(rpcURL); err != nil {
+		return err
+	}
+	if sc.VM == models.SubnetEvm {
+		genesis, err := app.LoadRawGenesis(chain)
+		if err != nil {
+			return err
+		}
+		if err := vm.VerifyGenesisContracts(rpcURL, genesis); err != nil {
+			return err
+		}
+	}
+	hooks, err := newDeployHookRunner(chain, network, false)
+	if err != nil {
+		return err
+	}
+	if len(hooks.hooks.PostDeploy) == 0 {
+		ux.Logger.PrintToUser("%s has no post-deploy hooks", chain)
+		return nil
+	}
+	return hooks.postDeploy(
+		subnetData.SubnetID,
+		blockchainID,
+		rpcURL,
+		sc.Networks[network.Name()].TeleporterMessengerAddress,
+		sc.Networks[network.Name()].TeleporterRegistryAddress,
+	)
+}
+
+// genesisContracts returns the addresses of the contracts in the genesis of
+// [chain], if it is a Subnet-EVM genesis
+func genesisContracts(chain string) []common.Address {
+	sc, err := app.LoadSidecar(chain)
+	if err != nil || sc.VM != models.SubnetEvm {
+		return nil
+	}
+	genesis, err := app.LoadRawGenesis(chain)
+	if err != nil {
+		return nil
+	}
+	addresses, err := vm.GenesisContractAddresses(genesis)
+	if err != nil {
+		return nil
+	}
+	return addresses
+}
+
+// checkChainServed fails if the chain is not served at [rpcURL]
+func checkChainServed(rpcURL string) error {
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return fmt.Errorf("chain is not served at %s: %w", rpcURL, err)
+	}
+	defer client.Close()
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	if _, err := client.ChainID(ctx); err != nil {
+		return fmt.Errorf("chain is not served at %s yet, its validators may still be bootstrapping it. Use --rpc-url to point to a node serving it: %w", rpcURL, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckChainServed(t *testing.T) {
+	require := require.New(t)
+	served := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(json.NewDecoder(r.Body).Decode(&req))
+		require.Equal("eth_chainId", req.Method)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":"0x1869f"}`))
+	}))
+	defer served.Close()
+	require.NoError(checkChainServed(served.URL))
+
+	// public API nodes answer 404 for chains they don't serve
+	notServed := httptest.NewServer(http.NotFoundHandler())
+	defer notServed.Close()
+	require.ErrorContains(checkChainServed(notServed.URL), "is not served at")
+}
+
+func TestGenesisContracts(t *testing.T) {
+	require := require.New(t)
+	app = application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, prompts.NewPrompter(), nil)
+	defer func() {
+		app = nil
+	}()
+	genesis := []byte(`{"gasLimit": "0x7a1200", "difficulty": "0x0", "alloc": {
+		"0x1111111111111111111111111111111111111111": {"balance": "0x1"},
+		"0x2222222222222222222222222222222222222222": {"balance": "0x0", "code": "0x6080"}
+	}}`)
+	for _, sc := range []models.Sidecar{
+		{Name: "evm", VM: models.SubnetEvm, Subnet: "evm"},
+		{Name: "custom", VM: models.CustomVM, Subnet: "custom"},
+	} {
+		require.NoError(app.WriteGenesisFile(sc.Name, genesis))
+		require.NoError(app.CreateSidecar(&sc))
+	}
+	require.Equal([]common.Address{common.HexToAddress("0x2222222222222222222222222222222222222222")}, genesisContracts("evm"))
+	// only Subnet-EVM genesis are checked
+	require.Empty(genesisContracts("custom"))
+	require.Empty(genesisContracts("missing"))
+}
@@ -136,6 +136,9 @@ func importFromFile(importPath string) error {
 			return fmt.Errorf("build script must be defined for custom vm import")
 		}
 
+		if importable.Sidecar.CustomVMBuild == nil {
+			ux.Logger.PrintToUser("No build record for custom VM of %s: the binary built from branch %s can't be verified", subnetName, importable.Sidecar.CustomVMBranch)
+		}
 		if err := vm.BuildCustomVM(app, &importable.Sidecar); err != nil {
 			return err
 		}
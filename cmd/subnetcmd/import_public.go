@@ -127,34 +127,18 @@ func importPublic(*cobra.Command, []string) error {
 		}
 	}
 
-	client := platformvm.NewClient(network.Endpoint)
-	ctx, cancel := utils.GetAPIContext()
-	defer cancel()
-	options := []rpc.Option{}
-
 	ux.Logger.PrintToUser("Getting information from the %s network...", network.Name())
 
-	txBytes, err := client.GetTx(ctx, blockchainID, options...)
+	createChainTx, err := getCreateChainTx(network, blockchainID)
 	if err != nil {
 		return err
 	}
 
 	var (
 		vmID, subnetID ids.ID
-		tx             txs.Tx
 		subnetName     string
 	)
 
-	_, err = txs.Codec.Unmarshal(txBytes, &tx)
-	if err != nil {
-		return fmt.Errorf("failed unmarshaling the createChainTx: %w", err)
-	}
-
-	createChainTx, ok := tx.Unsigned.(*txs.CreateChainTx)
-	if !ok {
-		return fmt.Errorf("expected a CreateChainTx, got %T", tx.Unsigned)
-	}
-
 	vmID = createChainTx.VMID
 	subnetID = createChainTx.SubnetID
 	subnetName = createChainTx.ChainName
@@ -257,3 +241,24 @@ func importPublic(*cobra.Command, []string) error {
 
 	return nil
 }
+
+// getCreateChainTx fetches from the P-Chain of [network] the tx that created
+// [blockchainID], which holds the chain name, VMID, subnet and genesis
+func getCreateChainTx(network models.Network, blockchainID ids.ID) (*txs.CreateChainTx, error) {
+	client := platformvm.NewClient(network.Endpoint)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	txBytes, err := client.GetTx(ctx, blockchainID)
+	if err != nil {
+		return nil, err
+	}
+	var tx txs.Tx
+	if _, err := txs.Codec.Unmarshal(txBytes, &tx); err != nil {
+		return nil, fmt.Errorf("failed unmarshaling the createChainTx: %w", err)
+	}
+	createChainTx, ok := tx.Unsigned.(*txs.CreateChainTx)
+	if !ok {
+		return nil, fmt.Errorf("expected a CreateChainTx, got %T", tx.Unsigned)
+	}
+	return createChainTx, nil
+}
@@ -7,10 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/keychain"
@@ -18,6 +17,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/plugins"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/sdk"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -85,12 +85,14 @@ This command currently only supports Subnets deployed on the Fuji Testnet and Ma
 	cmd.Flags().StringVar(&nodeIDStr, "nodeID", "", "set the NodeID of the validator to check")
 	cmd.Flags().BoolVar(&forceWrite, "force-write", false, "if true, skip to prompt to overwrite the config file")
 	cmd.Flags().BoolVar(&joinElastic, "elastic", false, "set flag as true if joining elastic subnet")
+	cmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "with --elastic, build the add permissionless validator tx and check fee and balance without issuing it")
 	cmd.Flags().Uint64Var(&stakeAmount, "stake-amount", 0, "amount of tokens to stake on validator")
 	cmd.Flags().StringVar(&startTimeStr, "start-time", "", "start time that validator starts validating")
 	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long validator validates for after start time")
 	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji only]")
 	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji)")
 	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	cmd.Flags().StringVar(&chainName, "chain", "", "only install the VM of this chain of the subnet (defaults to all its deployed chains)")
 	return cmd
 }
 
@@ -128,6 +130,10 @@ func joinCmd(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	if dryRun && (!joinElastic || network.Kind == models.Local) {
+		return fmt.Errorf("--%s is only supported with --elastic on public networks", dryRunFlag)
+	}
+
 	if joinElastic {
 		return handleValidatorJoinElasticSubnet(sc, network, subnetName)
 	}
@@ -140,13 +146,18 @@ func joinCmd(_ *cobra.Command, args []string) error {
 	}
 	subnetIDStr := subnetID.String()
 
+	joinChains, err := getChainsToJoin(args[0], chains, network)
+	if err != nil {
+		return err
+	}
+
 	if printManual {
 		pluginDir = app.GetTmpPluginDir()
-		vmPath, err := plugins.CreatePlugin(app, sc.Name, pluginDir)
+		vmPaths, err := createChainPlugins(joinChains, pluginDir)
 		if err != nil {
 			return err
 		}
-		printJoinCmd(subnetIDStr, network, vmPath)
+		printJoinCmd(subnetIDStr, network, vmPaths)
 		return nil
 	}
 
@@ -167,11 +178,11 @@ func joinCmd(_ *cobra.Command, args []string) error {
 		}
 		if choice == choiceManual {
 			pluginDir = app.GetTmpPluginDir()
-			vmPath, err := plugins.CreatePlugin(app, sc.Name, pluginDir)
+			vmPaths, err := createChainPlugins(joinChains, pluginDir)
 			if err != nil {
 				return err
 			}
-			printJoinCmd(subnetIDStr, network, vmPath)
+			printJoinCmd(subnetIDStr, network, vmPaths)
 			return nil
 		}
 	}
@@ -204,12 +215,6 @@ func joinCmd(_ *cobra.Command, args []string) error {
 		}
 	}
 
-	// ...but not this
-	avagoConfigPath, err := plugins.SanitizePath(avagoConfigPath)
-	if err != nil {
-		return err
-	}
-
 	// avagoConfigPath was set but not pluginDir
 	// if **both** flags were set, this will be skipped...
 	if pluginDir == "" {
@@ -237,113 +242,66 @@ func joinCmd(_ *cobra.Command, args []string) error {
 		}
 	}
 
-	// ...but not this
-	pluginDir, err := plugins.SanitizePath(pluginDir)
-	if err != nil {
-		return err
-	}
-
-	vmPath, err := plugins.CreatePlugin(app, sc.Name, pluginDir)
-	if err != nil {
-		return err
-	}
-
-	ux.Logger.PrintToUser("VM binary written to %s", vmPath)
-
-	if forceWrite {
-		if err := writeAvagoChainConfigFiles(app, dataDir, subnetName, sc, network); err != nil {
+	if !forceWrite {
+		warn := "This will edit your existing config file. This edit is nondestructive,\n" +
+			"but it's always good to have a backup."
+		ux.Logger.PrintToUser(warn)
+		yes, err := app.Prompt.CaptureYesNo("Proceed?")
+		if err != nil {
 			return err
 		}
+		if !yes {
+			ux.Logger.PrintToUser("Canceled by user")
+			return nil
+		}
 	}
 
-	subnetAvagoConfigFile := ""
-	if app.AvagoNodeConfigExists(subnetName) {
-		subnetAvagoConfigFile = app.GetAvagoNodeConfigPath(subnetName)
-	}
-
-	if err := plugins.EditConfigFile(
-		app,
-		subnetIDStr,
-		network,
-		avagoConfigPath,
-		forceWrite,
-		subnetAvagoConfigFile,
-	); err != nil {
-		return err
+	for _, chain := range joinChains {
+		if _, err := sdk.New(app).Join(sdk.JoinOptions{
+			SubnetName:            chain,
+			Network:               network,
+			AvalancheGoConfigPath: avagoConfigPath,
+			PluginDir:             pluginDir,
+			WriteChainConfigs:     forceWrite,
+			DataDir:               dataDir,
+		}); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
-func writeAvagoChainConfigFiles(
-	app *application.Avalanche,
-	dataDir string,
-	subnetName string,
-	sc models.Sidecar,
-	network models.Network,
-) error {
-	if dataDir == "" {
-		dataDir = utils.UserHomePath(".avalanchego")
-	}
-
-	subnetID := sc.Networks[network.Name()].SubnetID
-	if subnetID == ids.Empty {
-		return errNoSubnetID
-	}
-	subnetIDStr := subnetID.String()
-	blockchainID := sc.Networks[network.Name()].BlockchainID
-
-	configsPath := filepath.Join(dataDir, "configs")
-
-	subnetConfigsPath := filepath.Join(configsPath, "subnets")
-	subnetConfigPath := filepath.Join(subnetConfigsPath, subnetIDStr+".json")
-	if app.AvagoSubnetConfigExists(subnetName) {
-		if err := os.MkdirAll(subnetConfigsPath, constants.DefaultPerms755); err != nil {
-			return err
-		}
-		subnetConfig, err := app.LoadRawAvagoSubnetConfig(subnetName)
+// getChainsToJoin returns the chain given with --chain, or else all the chains
+// of the subnet deployed on [network], as a node validating the subnet needs
+// the VMs of all of them
+func getChainsToJoin(subnetName string, chains []string, network models.Network) ([]string, error) {
+	if chainName != "" {
+		chain, err := SelectSubnetChain(subnetName, chains, chainName)
 		if err != nil {
-			return err
-		}
-		if err := os.WriteFile(subnetConfigPath, subnetConfig, constants.DefaultPerms755); err != nil {
-			return err
+			return nil, err
 		}
-	} else {
-		_ = os.RemoveAll(subnetConfigPath)
+		return []string{chain}, nil
 	}
+	deployed, err := GetDeployedChains(chains, network)
+	if err != nil {
+		return nil, err
+	}
+	if len(deployed) == 0 {
+		return chains[:1], nil
+	}
+	return deployed, nil
+}
 
-	if blockchainID != ids.Empty && app.ChainConfigExists(subnetName) || app.NetworkUpgradeExists(subnetName) {
-		chainConfigsPath := filepath.Join(configsPath, "chains", blockchainID.String())
-		if err := os.MkdirAll(chainConfigsPath, constants.DefaultPerms755); err != nil {
-			return err
-		}
-		chainConfigPath := filepath.Join(chainConfigsPath, "config.json")
-		if app.ChainConfigExists(subnetName) {
-			chainConfig, err := app.LoadRawChainConfig(subnetName)
-			if err != nil {
-				return err
-			}
-			if err := os.WriteFile(chainConfigPath, chainConfig, constants.DefaultPerms755); err != nil {
-				return err
-			}
-		} else {
-			_ = os.RemoveAll(chainConfigPath)
-		}
-		networkUpgradesPath := filepath.Join(chainConfigsPath, "upgrade.json")
-		if app.NetworkUpgradeExists(subnetName) {
-			networkUpgrades, err := app.LoadRawNetworkUpgrades(subnetName)
-			if err != nil {
-				return err
-			}
-			if err := os.WriteFile(networkUpgradesPath, networkUpgrades, constants.DefaultPerms755); err != nil {
-				return err
-			}
-		} else {
-			_ = os.RemoveAll(networkUpgradesPath)
+func createChainPlugins(chains []string, pluginDir string) ([]string, error) {
+	vmPaths := []string{}
+	for _, chain := range chains {
+		vmPath, err := plugins.CreatePlugin(app, chain, pluginDir)
+		if err != nil {
+			return nil, err
 		}
+		vmPaths = append(vmPaths, vmPath)
 	}
-
-	return nil
+	return vmPaths, nil
 }
 
 func handleValidatorJoinElasticSubnet(sc models.Sidecar, network models.Network, subnetName string) error {
@@ -377,8 +335,10 @@ func handleValidatorJoinElasticSubnet(sc models.Sidecar, network models.Network,
 		return err
 	}
 	endTime := start.Add(stakeDuration)
-	ux.Logger.PrintToUser("Inputs complete, issuing transaction for the provided validator to join elastic subnet...")
-	ux.Logger.PrintToUser("")
+	if !dryRun {
+		ux.Logger.PrintToUser("Inputs complete, issuing transaction for the provided validator to join elastic subnet...")
+		ux.Logger.PrintToUser("")
+	}
 	switch network.Kind {
 	case models.Local:
 		return handleValidatorJoinElasticSubnetLocal(sc, network, subnetName, nodeID, stakedTokenAmount, start, endTime)
@@ -411,6 +371,22 @@ func handleValidatorJoinElasticSubnet(sc models.Sidecar, network models.Network,
 		return err
 	}
 	delegationFee := network.GenesisParams().MinDelegationFee
+	if dryRun {
+		plan := subnet.NewTxPlan(app, kc, network, subnetID)
+		if err := plan.AddPermissionlessValidator(
+			subnetID,
+			assetID,
+			nodeID,
+			stakedTokenAmount,
+			uint64(start.Unix()),
+			uint64(endTime.Unix()),
+			recipientAddr,
+			delegationFee,
+		); err != nil {
+			return err
+		}
+		return printTxPlan(plan, network)
+	}
 	txID, err := deployer.AddPermissionlessValidator(subnetID, assetID, nodeID, stakedTokenAmount, uint64(start.Unix()), uint64(endTime.Unix()), recipientAddr, delegationFee, nil, nil)
 	if err != nil {
 		return err
@@ -628,7 +604,7 @@ func promptStakeAmount(subnetName string, isValidator bool, network models.Netwo
 	return initialSupply, nil
 }
 
-func printJoinCmd(subnetID string, network models.Network, vmPath string) {
+func printJoinCmd(subnetID string, network models.Network, vmPaths []string) {
 	msg := `
 To setup your node, you must do two things:
 
@@ -662,7 +638,7 @@ this tool will try to update the file automatically (make sure it can write to i
 After you update your config, you will need to restart your node for the changes to
 take effect.`
 
-	ux.Logger.PrintToUser(msg, vmPath, subnetID, network.NetworkIDFlagValue(), subnetID, subnetID)
+	ux.Logger.PrintToUser(msg, strings.Join(vmPaths, ", "), subnetID, network.NetworkIDFlagValue(), subnetID, subnetID)
 }
 
 func getAssetBalance(pClient platformvm.Client, addr string, assetID ids.ID) (uint64, error) {
@@ -64,7 +64,7 @@ func listSubnets(cmd *cobra.Command, args []string) error {
 
 	rows := subnetMatrix{}
 
-	cars, err := getSidecars(app)
+	cars, err := GetSidecars(app)
 	if err != nil {
 		return err
 	}
@@ -107,8 +107,8 @@ func listSubnets(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func getSidecars(app *application.Avalanche) ([]*models.Sidecar, error) {
-	subnets, err := os.ReadDir(filepath.Join(app.GetBaseDir(), constants.SubnetDir))
+func GetSidecars(app *application.Avalanche) ([]*models.Sidecar, error) {
+	subnets, err := os.ReadDir(app.GetSubnetDir())
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +155,7 @@ func listDeployInfo(*cobra.Command, []string) error {
 		// DO NOT FAIL, just print No for deployed status
 		app.Log.Warn("problem contacting server to get deployed subnets")
 	}
-	cars, err := getSidecars(app)
+	cars, err := GetSidecars(app)
 	if err != nil {
 		return err
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/sdk"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/txutils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -163,46 +164,36 @@ func removeValidator(_ *cobra.Command, args []string) error {
 		}
 	}
 
-	// check that this guy actually is a validator on the subnet
-	isValidator, err := subnet.IsSubnetValidator(subnetID, nodeID, network)
-	if err != nil {
-		// just warn the user, don't fail
-		ux.Logger.PrintToUser("failed to check if node is a validator on the subnet: %s", err)
-	} else if !isValidator {
-		// this is actually an error
-		return fmt.Errorf("node %s is not a validator on subnet %s", nodeID, subnetID)
-	}
-
 	ux.Logger.PrintToUser("NodeID: %s", nodeID.String())
 	ux.Logger.PrintToUser("Network: %s", network.Name())
 	ux.Logger.PrintToUser("Inputs complete, issuing transaction to remove the specified validator...")
 
-	deployer := subnet.NewPublicDeployer(app, kc, network)
-	isFullySigned, tx, remainingSubnetAuthKeys, err := deployer.RemoveValidator(
-		controlKeys,
-		subnetAuthKeys,
-		subnetID,
-		transferSubnetOwnershipTxID,
-		nodeID,
-	)
+	result, err := sdk.New(app).RemoveValidator(sdk.RemoveValidatorOptions{
+		ValidatorOptions: sdk.ValidatorOptions{
+			SubnetName:     subnetName,
+			Network:        network,
+			Keychain:       kc,
+			NodeID:         nodeID,
+			ControlKeys:    controlKeys,
+			Threshold:      threshold,
+			SubnetAuthKeys: subnetAuthKeys,
+		},
+	})
 	if err != nil {
 		return err
 	}
-	if !isFullySigned {
-		if err := SaveNotFullySignedTx(
+	if !result.FullySigned {
+		return SaveNotFullySignedTx(
 			"Remove Validator",
-			tx,
+			result.Tx,
 			subnetName,
-			subnetAuthKeys,
-			remainingSubnetAuthKeys,
+			result.SubnetAuthKeys,
+			result.RemainingSubnetAuthKeys,
 			outputTxPath,
 			false,
-		); err != nil {
-			return err
-		}
+		)
 	}
-
-	return err
+	return nil
 }
 
 func removeFromLocal(subnetName string) error {
@@ -70,5 +70,17 @@ manage your Subnet configurations and live deployments.`,
 	cmd.AddCommand(newAddPermissionlessDelegatorCmd())
 	// subnet changeOwner
 	cmd.AddCommand(newChangeOwnerCmd())
+	// subnet validate-genesis
+	cmd.AddCommand(newValidateGenesisCmd())
+	// subnet template
+	cmd.AddCommand(newTemplateCmd())
+	// subnet clone
+	cmd.AddCommand(newCloneCmd())
+	// subnet diff
+	cmd.AddCommand(newDiffCmd())
+	// subnet vm-template
+	cmd.AddCommand(newVMTemplateCmd())
+	// subnet hooks
+	cmd.AddCommand(newHooksCmd())
 	return cmd
 }
@@ -0,0 +1,199 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+// avalanche subnet template
+func newTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage Subnet-EVM genesis templates",
+		Long: `The subnet template command suite manages the genesis templates that can be
+used with subnet create --template.
+
+Besides the builtin templates, templates are loaded from the *.yaml files of
+the genesis-templates dir of the CLI base dir, and of every dir registered
+with subnet template register. A template file holds a name, a version, a
+description, a list of params, and a genesis text/template rendering the
+chainId, tokenName, feeConfig, allowFeeRecipients, precompiles and alloc
+settings.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newTemplateListCmd())
+	cmd.AddCommand(newTemplateDescribeCmd())
+	cmd.AddCommand(newTemplateRegisterCmd())
+	cmd.AddCommand(newTemplateUnregisterCmd())
+	return cmd
+}
+
+// avalanche subnet template list
+func newTemplateListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List the available genesis templates",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			templates, err := loadGenesisTemplates()
+			if err != nil {
+				return err
+			}
+			vm.SortGenesisTemplates(templates)
+			table := tablewriter.NewWriter(ux.Logger.Writer)
+			table.SetHeader([]string{"Name", "Version", "Source", "Description"})
+			table.SetRowLine(true)
+			table.SetAutoWrapText(false)
+			for _, t := range templates {
+				table.Append([]string{t.Name, t.Version, t.Source, t.Description})
+			}
+			table.Render()
+			return nil
+		},
+	}
+}
+
+// avalanche subnet template describe
+func newTemplateDescribeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "describe [name[@version]]",
+		Short:        "Show the params and genesis of a template",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			templates, err := loadGenesisTemplates()
+			if err != nil {
+				return err
+			}
+			t, err := vm.FindGenesisTemplate(templates, args[0])
+			if err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("%s (%s)", t.Ref(), t.Source)
+			ux.Logger.PrintToUser(t.Description)
+			ux.Logger.PrintToUser("")
+			if len(t.Params) > 0 {
+				table := tablewriter.NewWriter(ux.Logger.Writer)
+				table.SetHeader([]string{"Param", "Type", "Default", "Description"})
+				table.SetAutoWrapText(false)
+				for _, param := range t.Params {
+					paramType := param.Type
+					if paramType == "" {
+						paramType = "string"
+					}
+					defaultValue := "(required)"
+					if param.Default != nil {
+						defaultValue = *param.Default
+					}
+					table.Append([]string{param.Name, paramType, defaultValue, param.Description})
+				}
+				table.Render()
+				ux.Logger.PrintToUser("")
+			}
+			ux.Logger.PrintToUser(t.Genesis)
+			return nil
+		},
+	}
+}
+
+// avalanche subnet template register
+func newTemplateRegisterCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "register [dir]",
+		Short:        "Register a dir of genesis templates",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			dir, err := filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+			if info, err := os.Stat(dir); err != nil {
+				return err
+			} else if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			// fail early on broken templates
+			if _, err := vm.LoadGenesisTemplates([]string{dir}); err != nil {
+				return err
+			}
+			dirs := app.Conf.GetConfigStringSliceValue(constants.ConfigGenesisTemplateDirsKey)
+			if slices.Contains(dirs, dir) {
+				ux.Logger.PrintToUser("%s is already registered", dir)
+				return nil
+			}
+			if err := app.Conf.SetConfigValue(constants.ConfigGenesisTemplateDirsKey, append(dirs, dir)); err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("Registered genesis template dir %s", dir)
+			return nil
+		},
+	}
+}
+
+// avalanche subnet template unregister
+func newTemplateUnregisterCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "unregister [dir]",
+		Short:        "Stop loading genesis templates from a registered dir",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			dir, err := filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+			dirs := app.Conf.GetConfigStringSliceValue(constants.ConfigGenesisTemplateDirsKey)
+			idx := slices.Index(dirs, dir)
+			if idx == -1 {
+				return fmt.Errorf("%s is not a registered genesis template dir", dir)
+			}
+			if err := app.Conf.SetConfigValue(constants.ConfigGenesisTemplateDirsKey, slices.Delete(dirs, idx, idx+1)); err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("Unregistered genesis template dir %s", dir)
+			return nil
+		},
+	}
+}
+
+// loadGenesisTemplates loads the builtin templates, then the ones in the
+// default user dir, then the ones in the registered dirs
+func loadGenesisTemplates() ([]*vm.GenesisTemplate, error) {
+	dirs := []string{app.GetGenesisTemplatesDir()}
+	dirs = append(dirs, app.Conf.GetConfigStringSliceValue(constants.ConfigGenesisTemplateDirsKey)...)
+	return vm.LoadGenesisTemplates(dirs)
+}
+
+// getEvmGenesisTemplate renders the template selected with --template, if any
+func getEvmGenesisTemplate() (*vm.EvmGenesisTemplate, error) {
+	if genesisTemplate == "" {
+		if len(genesisTemplateParams) > 0 {
+			return nil, errors.New("--template-param requires --template")
+		}
+		return nil, nil
+	}
+	templates, err := loadGenesisTemplates()
+	if err != nil {
+		return nil, err
+	}
+	t, err := vm.FindGenesisTemplate(templates, genesisTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return t.Render(genesisTemplateParams)
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/exp/slices"
+)
+
+const dryRunFlag = "dry-run"
+
+func formatAVAX(amount uint64) string {
+	return fmt.Sprintf("%.9f AVAX", float64(amount)/float64(units.Avax))
+}
+
+func formatAssetAmount(amount uint64, assetID ids.ID, avaxAssetID ids.ID) string {
+	if assetID == avaxAssetID {
+		return formatAVAX(amount)
+	}
+	return fmt.Sprintf("%d of asset %s", amount, assetID)
+}
+
+// printTxPlan prints the steps of [plan] together with the funds they need,
+// and fails if the keychain can't pay for them
+func printTxPlan(plan *subnet.TxPlan, network models.Network) error {
+	avaxAssetID, err := plan.AVAXAssetID()
+	if err != nil {
+		return err
+	}
+	balances, err := plan.Balances()
+	if err != nil {
+		return err
+	}
+	required := plan.Required(avaxAssetID)
+
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Dry run on %s: no transaction has been issued", network.Name())
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Step", "Tx", "Chain", "Fee", "Spent", "Signers", "Status"})
+	table.SetRowLine(true)
+	remainingSigners := []string{}
+	for i, step := range plan.Steps {
+		spent := []string{}
+		for assetID, amount := range step.Spent {
+			spent = append(spent, formatAssetAmount(amount, assetID, avaxAssetID))
+		}
+		signers := "-"
+		if step.Signers != nil {
+			signers = fmt.Sprintf(
+				"%d of %d control keys (%d in keychain)",
+				step.Signers.Threshold,
+				len(step.Signers.ControlKeys),
+				len(step.Signers.SubnetAuthKeys)-len(step.Signers.Remaining),
+			)
+			for _, signer := range step.Signers.Remaining {
+				if !slices.Contains(remainingSigners, signer) {
+					remainingSigners = append(remainingSigners, signer)
+				}
+			}
+		}
+		status := "built"
+		if !step.Built {
+			status = "estimated, depends on previous steps"
+		}
+		table.Append([]string{
+			fmt.Sprintf("%d", i+1),
+			step.Name,
+			step.Chain + "-Chain",
+			formatAVAX(step.Fee),
+			strings.Join(spent, "\n"),
+			signers,
+			status,
+		})
+	}
+	table.Render()
+
+	for _, chain := range []string{subnet.PChain, subnet.XChain} {
+		for assetID, amount := range required[chain] {
+			ux.Logger.PrintToUser(
+				"%s-Chain: %s needed, %s available",
+				chain,
+				formatAssetAmount(amount, assetID, avaxAssetID),
+				formatAssetAmount(balances[chain][assetID], assetID, avaxAssetID),
+			)
+		}
+	}
+	if len(remainingSigners) > 0 {
+		ux.Logger.PrintToUser("The txs authorized by the subnet will also need the signatures of %s", strings.Join(remainingSigners, ", "))
+	}
+	if err := subnet.CheckFunds(required, balances); err != nil {
+		return fmt.Errorf("the keychain can't pay for the planned txs:\n%w", err)
+	}
+	ux.Logger.PrintToUser("The keychain can pay for all the planned txs")
+	return nil
+}
@@ -51,7 +51,10 @@ var (
 	avalanchegoChainConfigFlag       = "avalanchego-chain-config-dir"
 	avalanchegoChainConfigDir        string
 
-	print bool
+	print         bool
+	skipRehearsal bool
+
+	skipRehearsalFlag = "skip-rehearsal"
 )
 
 // avalanche subnet upgrade apply
@@ -69,7 +72,15 @@ to upgrade your node manually.
 
 After you update your validator's configuration, you need to restart your validator manually.
 If you provide the --avalanchego-chain-config-dir flag, this command attempts to write the upgrade file at that path.
-Refer to https://docs.avax.network/nodes/maintain/chain-config-flags#subnet-chain-configs for related documentation.`,
+Refer to https://docs.avax.network/nodes/maintain/chain-config-flags#subnet-chain-configs for related documentation.
+
+For nodes managed by the CLI, use --cluster to upload the upgrade file to the chain config dir of every
+node of the cluster over SSH. Nodes are restarted one at a time, and the next node is only restarted once
+the previous one is healthy, validating the subnet again (tracking it, for API nodes), and has bootstrapped
+the blockchain. The lock file is updated only if all the nodes were upgraded.
+
+On public networks the upgrade file must have passed a rehearsal with avalanche subnet upgrade rehearse first,
+unless --skip-rehearsal is given.`,
 		RunE: applyCmd,
 		Args: cobra.ExactArgs(1),
 	}
@@ -82,6 +93,8 @@ Refer to https://docs.avax.network/nodes/maintain/chain-config-flags#subnet-chai
 	cmd.Flags().BoolVar(&print, "print", false, "if true, print the manual config without prompting (for public networks only)")
 	cmd.Flags().BoolVar(&force, "force", false, "If true, don't prompt for confirmation of timestamps in the past")
 	cmd.Flags().StringVar(&avalanchegoChainConfigDir, avalanchegoChainConfigFlag, os.ExpandEnv(avalanchegoChainConfigDirDefault), "avalanchego's chain config file directory")
+	cmd.Flags().BoolVar(&skipRehearsal, skipRehearsalFlag, false, "apply on public networks without a passing rehearsal of the upgrade file")
+	cmd.Flags().StringVar(&clusterName, "cluster", "", "apply upgrade on all the nodes of the given cluster")
 
 	return cmd
 }
@@ -98,6 +111,13 @@ func applyCmd(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("unable to load sidecar: %w", err)
 	}
 
+	if clusterName != "" {
+		if useConfig || useLocal || useFuji || useMainnet || print {
+			return errors.New("--cluster can't be used together with a network flag or --print")
+		}
+		return applyClusterUpgrade(subnetName, clusterName, &sc)
+	}
+
 	networkToUpgrade, err := selectNetworkToUpgrade(sc, []string{})
 	if err != nil {
 		return err
@@ -242,9 +262,7 @@ func applyLocalNetworkUpgrade(subnetName, networkKey string, sc *models.Sidecar)
 func applyPublicNetworkUpgrade(subnetName, networkKey string, sc *models.Sidecar) error {
 	if print {
 		blockchainIDstr := "<your-blockchain-id>"
-		if sc.Networks != nil &&
-			sc.Networks[networkKey] != (models.NetworkData{}) &&
-			sc.Networks[networkKey].BlockchainID != ids.Empty {
+		if sc.Networks[networkKey].BlockchainID != ids.Empty {
 			blockchainIDstr = sc.Networks[networkKey].BlockchainID.String()
 		}
 		ux.Logger.PrintToUser("To install the upgrade file on your validator:")
@@ -275,6 +293,11 @@ func applyPublicNetworkUpgrade(subnetName, networkKey string, sc *models.Sidecar
 	if err != nil {
 		return err
 	}
+	if !skipRehearsal {
+		if err := checkRehearsal(subnetName); err != nil {
+			return err
+		}
+	}
 
 	ux.Logger.PrintToUser("The chain config dir avalanchego uses is set at %s", avalanchegoChainConfigDir)
 	// give the user the chance to check if they indeed want to use the default
@@ -308,7 +331,7 @@ func applyPublicNetworkUpgrade(subnetName, networkKey string, sc *models.Sidecar
 
 func validateUpgrade(subnetName, networkKey string, sc *models.Sidecar, skipPrompting bool) ([]params.PrecompileUpgrade, string, error) {
 	// if there's no entry in the Sidecar, we assume there hasn't been a deploy yet
-	if sc.Networks[networkKey] == (models.NetworkData{}) {
+	if _, ok := sc.Networks[networkKey]; !ok {
 		return nil, "", subnetNotYetDeployed()
 	}
 	chainID := sc.Networks[networkKey].BlockchainID
@@ -0,0 +1,299 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/olekukonko/tablewriter"
+)
+
+var (
+	// vars so that tests don't wait on failing nodes
+	clusterNodeCheckTimeout  = 5 * time.Minute
+	clusterNodeCheckInterval = 5 * time.Second
+)
+
+var clusterName string
+
+// clusterNodeUpgrade is the outcome of applying the upgrade bytes on a cluster node
+type clusterNodeUpgrade struct {
+	node         string
+	uploaded     bool
+	restarted    bool
+	healthy      bool
+	syncStatus   string
+	bootstrapped bool
+	err          error
+}
+
+// clusterNodeRunner runs the steps of an upgrade on the nodes of a cluster
+type clusterNodeRunner interface {
+	UploadUpgradeBytes(host *models.Host, upgradeBytesPath string, remoteUpgradeBytesPath string) error
+	RestartNode(host *models.Host) error
+	IsHealthy(host *models.Host) (bool, error)
+	GetSyncStatus(host *models.Host, blockchainID string) (string, error)
+	IsBootstrapped(host *models.Host, blockchainID string) (bool, error)
+}
+
+// sshClusterNodeRunner runs the upgrade steps over SSH
+type sshClusterNodeRunner struct{}
+
+func (sshClusterNodeRunner) UploadUpgradeBytes(host *models.Host, upgradeBytesPath string, remoteUpgradeBytesPath string) error {
+	return ssh.RunSSHUploadUpgradeBytes(host, upgradeBytesPath, remoteUpgradeBytesPath)
+}
+
+func (sshClusterNodeRunner) RestartNode(host *models.Host) error {
+	return ssh.RunSSHRestartNode(host)
+}
+
+func (sshClusterNodeRunner) IsHealthy(host *models.Host) (bool, error) {
+	resp, err := ssh.RunSSHCheckHealthy(host)
+	if err != nil {
+		return false, err
+	}
+	var result struct {
+		Result struct {
+			Healthy bool `json:"healthy"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return false, fmt.Errorf("unable to parse node healthy status: %w", err)
+	}
+	return result.Result.Healthy, nil
+}
+
+func (sshClusterNodeRunner) GetSyncStatus(host *models.Host, blockchainID string) (string, error) {
+	resp, err := ssh.RunSSHSubnetSyncStatus(host, blockchainID)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Result struct {
+			Status string `json:"status"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil || result.Result.Status == "" {
+		return "", errors.New("unable to parse subnet sync status")
+	}
+	return result.Result.Status, nil
+}
+
+func (sshClusterNodeRunner) IsBootstrapped(host *models.Host, blockchainID string) (bool, error) {
+	resp, err := ssh.RunSSHCheckChainBootstrapped(host, blockchainID)
+	if err != nil {
+		return false, err
+	}
+	var result struct {
+		Result struct {
+			IsBootstrapped bool `json:"isBootstrapped"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return false, fmt.Errorf("unable to parse chain bootstrapped status: %w", err)
+	}
+	return result.Result.IsBootstrapped, nil
+}
+
+// applyClusterUpgrade installs the upgrade file of [subnetName] on all the
+// nodes of cluster [clusterName], restarting them one at a time. The next node
+// is only restarted after the previous one is back healthy, validating and
+// bootstrapped, and the lock file is updated only if the upgrade succeeded on all the nodes
+func applyClusterUpgrade(subnetName string, clusterName string, sc *models.Sidecar) error {
+	exists, err := app.ClusterExists(clusterName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("cluster %q does not exist", clusterName)
+	}
+	clusterConfig, err := app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	network, err := app.GetClusterNetwork(clusterName)
+	if err != nil {
+		return err
+	}
+	networkKey := network.Name()
+	precmpUpgrades, _, err := validateUpgrade(subnetName, networkKey, sc, force)
+	if err != nil {
+		return err
+	}
+	if (network.Kind == models.Fuji || network.Kind == models.Mainnet) && !skipRehearsal {
+		if err := checkRehearsal(subnetName); err != nil {
+			return err
+		}
+	}
+	blockchainID := sc.Networks[networkKey].BlockchainID.String()
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	if len(hosts) == 0 {
+		return fmt.Errorf("cluster %q has no nodes", clusterName)
+	}
+	defer func() {
+		for _, host := range hosts {
+			_ = host.Disconnect()
+		}
+	}()
+
+	ux.Logger.PrintToUser("Applying the upgrade file of %s on the %d nodes of cluster %s, one at a time", subnetName, len(hosts), clusterName)
+	return rolloutClusterUpgrade(sshClusterNodeRunner{}, hosts, clusterName, clusterConfig, subnetName, blockchainID, precmpUpgrades)
+}
+
+// rolloutClusterUpgrade upgrades [hosts] in order with [runner], stopping on the
+// first failure. The lock file is written only if all the nodes were upgraded
+func rolloutClusterUpgrade(
+	runner clusterNodeRunner,
+	hosts []*models.Host,
+	clusterName string,
+	clusterConfig models.ClusterConfig,
+	subnetName string,
+	blockchainID string,
+	precmpUpgrades []params.PrecompileUpgrade,
+) error {
+	results := []clusterNodeUpgrade{}
+	for i, host := range hosts {
+		ux.Logger.PrintToUser("[%d/%d] Upgrading node %s...", i+1, len(hosts), host.GetCloudID())
+		// API nodes track the subnet without validating it
+		expectedStatus := status.Validating
+		if clusterConfig.IsAPIHost(host.GetCloudID()) {
+			expectedStatus = status.Syncing
+		}
+		result := upgradeClusterNode(runner, host, subnetName, blockchainID, expectedStatus)
+		results = append(results, result)
+		if result.err != nil {
+			// stop the rollout so a bad upgrade file does not take down more nodes
+			break
+		}
+	}
+	printClusterUpgrade(results, len(hosts))
+	for _, result := range results {
+		if result.err != nil {
+			return fmt.Errorf("failed to upgrade node %s, the remaining nodes were not upgraded and the lock file was not updated: %w", result.node, result.err)
+		}
+	}
+	if err := writeLockFile(precmpUpgrades, subnetName); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Upgrade file successfully applied on all the nodes of cluster %s", clusterName)
+	return nil
+}
+
+// remoteUpgradeBytesPath is where cloud nodes read the upgrade file of
+// [blockchainID] from, next to its chain config
+func remoteUpgradeBytesPath(blockchainID string) string {
+	return filepath.Join(constants.CloudNodeChainConfigPath, blockchainID, constants.UpgradeBytesFileName)
+}
+
+// upgradeClusterNode uploads the upgrade file to [host] and restarts it. The
+// node is upgraded once it is healthy, reports [expectedStatus] for the
+// blockchain, and has bootstrapped it again
+func upgradeClusterNode(
+	runner clusterNodeRunner,
+	host *models.Host,
+	subnetName string,
+	blockchainID string,
+	expectedStatus status.BlockchainStatus,
+) clusterNodeUpgrade {
+	result := clusterNodeUpgrade{node: host.GetCloudID()}
+	if result.err = runner.UploadUpgradeBytes(
+		host,
+		app.GetUpgradeBytesFilePath(subnetName),
+		remoteUpgradeBytesPath(blockchainID),
+	); result.err != nil {
+		return result
+	}
+	result.uploaded = true
+	if result.err = runner.RestartNode(host); result.err != nil {
+		return result
+	}
+	result.restarted = true
+	if result.err = waitForClusterNode(func() (bool, error) { return runner.IsHealthy(host) }); result.err != nil {
+		result.err = fmt.Errorf("node is not healthy after the restart: %w", result.err)
+		return result
+	}
+	result.healthy = true
+	result.err = waitForClusterNode(func() (bool, error) {
+		var err error
+		result.syncStatus, err = runner.GetSyncStatus(host, blockchainID)
+		if err != nil {
+			return false, err
+		}
+		if result.syncStatus != expectedStatus.String() {
+			return false, fmt.Errorf("blockchain status is %s, expected %s", result.syncStatus, expectedStatus)
+		}
+		return true, nil
+	})
+	if result.err != nil {
+		result.err = fmt.Errorf("node is not back on the blockchain after the restart: %w", result.err)
+		return result
+	}
+	result.err = waitForClusterNode(func() (bool, error) { return runner.IsBootstrapped(host, blockchainID) })
+	if result.err != nil {
+		result.err = fmt.Errorf("node did not bootstrap the blockchain after the restart: %w", result.err)
+		return result
+	}
+	result.bootstrapped = true
+	return result
+}
+
+// waitForClusterNode polls [check] until it succeeds or the node check
+// timeout expires. Errors are retried, as the node API is not available while
+// it restarts
+func waitForClusterNode(check func() (bool, error)) error {
+	deadline := time.Now().Add(clusterNodeCheckTimeout)
+	for {
+		ok, err := check()
+		if ok {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if err == nil {
+				err = errors.New("timeout")
+			}
+			return err
+		}
+		time.Sleep(clusterNodeCheckInterval)
+	}
+}
+
+func printClusterUpgrade(results []clusterNodeUpgrade, numNodes int) {
+	ux.Logger.PrintToUser("")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Node", "Uploaded", "Restarted", "Healthy", "Sync Status", "Bootstrapped", "Error"})
+	table.SetRowLine(true)
+	for _, result := range results {
+		errStr := ""
+		if result.err != nil {
+			errStr = result.err.Error()
+		}
+		table.Append([]string{
+			result.node,
+			fmt.Sprint(result.uploaded),
+			fmt.Sprint(result.restarted),
+			fmt.Sprint(result.healthy),
+			result.syncStatus,
+			fmt.Sprint(result.bootstrapped),
+			errStr,
+		})
+	}
+	table.Render()
+	if len(results) < numNodes {
+		ux.Logger.PrintToUser("%d node(s) were not upgraded", numNodes-len(results))
+	}
+}
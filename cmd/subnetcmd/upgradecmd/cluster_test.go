@@ -0,0 +1,129 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNodeRunner records the upgrade steps run on each node, and reports
+// the given status for them
+type fakeNodeRunner struct {
+	steps []string
+	// remote paths the upgrade file is uploaded to
+	remotePaths []string
+	// nodes whose restart fails
+	failRestart map[string]bool
+	// blockchain status reported by each node, Validating if not set
+	syncStatus map[string]string
+	// nodes that never bootstrap the blockchain
+	notBootstrapped map[string]bool
+}
+
+func (r *fakeNodeRunner) UploadUpgradeBytes(host *models.Host, _ string, remoteUpgradeBytesPath string) error {
+	r.steps = append(r.steps, "upload "+host.GetCloudID())
+	r.remotePaths = append(r.remotePaths, remoteUpgradeBytesPath)
+	return nil
+}
+
+func (r *fakeNodeRunner) RestartNode(host *models.Host) error {
+	r.steps = append(r.steps, "restart "+host.GetCloudID())
+	if r.failRestart[host.GetCloudID()] {
+		return errors.New("restart failed")
+	}
+	return nil
+}
+
+func (*fakeNodeRunner) IsHealthy(*models.Host) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeNodeRunner) GetSyncStatus(host *models.Host, _ string) (string, error) {
+	if syncStatus, ok := r.syncStatus[host.GetCloudID()]; ok {
+		return syncStatus, nil
+	}
+	return status.Validating.String(), nil
+}
+
+func (r *fakeNodeRunner) IsBootstrapped(host *models.Host, _ string) (bool, error) {
+	return !r.notBootstrapped[host.GetCloudID()], nil
+}
+
+func setupClusterUpgradeTest(t *testing.T) (string, []*models.Host) {
+	app = &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	clusterNodeCheckTimeout = 0
+	clusterNodeCheckInterval = time.Millisecond
+	t.Cleanup(func() {
+		clusterNodeCheckTimeout = 5 * time.Minute
+		clusterNodeCheckInterval = 5 * time.Second
+	})
+	subnetName := "test"
+	require.NoError(t, os.MkdirAll(filepath.Join(app.GetSubnetDir(), subnetName), constants.DefaultPerms755))
+	require.NoError(t, app.WriteUpgradeFile(subnetName, []byte(`{"precompileUpgrades":[]}`)))
+	hosts := []*models.Host{}
+	for _, cloudID := range []string{"i-1", "i-2", "i-3"} {
+		hosts = append(hosts, &models.Host{NodeID: constants.AWSNodeAnsiblePrefix + "_" + cloudID})
+	}
+	return subnetName, hosts
+}
+
+func TestRolloutClusterUpgrade(t *testing.T) {
+	require := require.New(t)
+	subnetName, hosts := setupClusterUpgradeTest(t)
+	runner := &fakeNodeRunner{
+		syncStatus: map[string]string{"i-3": status.Syncing.String()},
+	}
+	clusterConfig := models.ClusterConfig{APINodes: []string{"i-3"}}
+
+	require.NoError(rolloutClusterUpgrade(runner, hosts, "cluster", clusterConfig, subnetName, "blockchainID", nil))
+	// nodes are upgraded one at a time, in order
+	require.Equal([]string{
+		"upload i-1", "restart i-1",
+		"upload i-2", "restart i-2",
+		"upload i-3", "restart i-3",
+	}, runner.steps)
+	// avalanchego reads chain configs and upgrade files from the configs dir
+	for _, remotePath := range runner.remotePaths {
+		require.Equal("/home/ubuntu/.avalanchego/configs/chains/blockchainID/upgrade.json", remotePath)
+	}
+	require.Len(runner.remotePaths, 3)
+	_, err := app.ReadLockUpgradeFile(subnetName)
+	require.NoError(err)
+}
+
+func TestRolloutClusterUpgradeStopsOnFailure(t *testing.T) {
+	for name, runner := range map[string]*fakeNodeRunner{
+		"restart fails":    {failRestart: map[string]bool{"i-2": true}},
+		"not validating":   {syncStatus: map[string]string{"i-2": status.Syncing.String()}},
+		"not bootstrapped": {notBootstrapped: map[string]bool{"i-2": true}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			subnetName, hosts := setupClusterUpgradeTest(t)
+
+			err := rolloutClusterUpgrade(runner, hosts, "cluster", models.ClusterConfig{}, subnetName, "blockchainID", nil)
+			require.ErrorContains(err, "failed to upgrade node i-2")
+			// the third node is left untouched
+			require.Equal([]string{"upload i-1", "restart i-1", "upload i-2", "restart i-2"}, runner.steps)
+			_, err = app.ReadLockUpgradeFile(subnetName)
+			require.ErrorIs(err, os.ErrNotExist)
+		})
+	}
+}
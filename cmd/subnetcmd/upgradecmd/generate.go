@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/models"
@@ -44,7 +45,10 @@ const (
 	adminLabel   = "admin"
 )
 
-var subnetName string
+var (
+	subnetName      string
+	upgradeSpecPath string
+)
 
 // avalanche subnet upgrade generate
 func newUpgradeGenerateCmd() *cobra.Command {
@@ -52,10 +56,32 @@ func newUpgradeGenerateCmd() *cobra.Command {
 		Use:   "generate [subnetName]",
 		Short: "Generate the configuration file to upgrade subnet nodes",
 		Long: `The subnet upgrade generate command builds a new upgrade.json file to customize your Subnet. It
-guides the user through the process using an interactive wizard.`,
+guides the user through the process using an interactive wizard.
+
+With --from, the upgrades are read from a YAML spec file instead, and appended to the ones
+already applied according to the lock file:
+
+upgrades:
+  - precompile: feeManagerConfig
+    in: 1d
+    adminAddresses: [0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC]
+    initialFeeConfig: {gasLimit: 15000000, targetBlockRate: 2, minBaseFee: 25000000000, ...}
+  - precompile: txAllowListConfig
+    action: reconfigure
+    at: "2024-07-01 12:00:00"
+    adminAddresses: [0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC]
+  - precompile: contractNativeMinterConfig
+    action: disable
+    in: 2w
+
+Precompiles are given by their upgrade.json key. The action is enable (default), disable, or
+reconfigure, which disables an enabled precompile and enables it again one second later with
+the new settings (eg. to change its admins). Times are either absolute ('YYYY-MM-DD HH:MM:SS'
+UTC or RFC3339) with at, or relative to now (eg. 30m, 1d, 2w) with in.`,
 		RunE: upgradeGenerateCmd,
 		Args: cobra.ExactArgs(1),
 	}
+	cmd.Flags().StringVar(&upgradeSpecPath, "from", "", "generate the upgrades non interactively from this YAML spec file")
 	return cmd
 }
 
@@ -80,6 +106,10 @@ func upgradeGenerateCmd(_ *cobra.Command, args []string) error {
 			"https://docs.avax.network/subnets/customize-a-subnet#network-upgrades-enabledisable-precompiles ") +
 			logging.Reset.Wrap("for more information")))
 
+	if upgradeSpecPath != "" {
+		return generateFromSpec(subnetName, upgradeSpecPath)
+	}
+
 	txt := "Press [Enter] to continue, or abort by choosing 'no'"
 	yes, err := app.Prompt.CaptureYesNo(txt)
 	if err != nil {
@@ -146,6 +176,65 @@ func upgradeGenerateCmd(_ *cobra.Command, args []string) error {
 	return app.WriteUpgradeFile(subnetName, jsonBytes)
 }
 
+// generateFromSpec writes the upgrade file of [subnetName] from the spec at
+// [specPath], keeping the upgrades already recorded in the lock file
+func generateFromSpec(subnetName string, specPath string) error {
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("upgrade specs are only supported for %s subnets", models.SubnetEvm)
+	}
+	spec, err := loadUpgradeSpec(specPath)
+	if err != nil {
+		return err
+	}
+	genesis, err := app.LoadEvmGenesis(subnetName)
+	if err != nil {
+		return err
+	}
+	lockUpgradeBytes, err := app.ReadLockUpgradeFile(subnetName)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	applied := []params.PrecompileUpgrade{}
+	if len(lockUpgradeBytes) > 0 {
+		applied, err = getAllUpgrades(lockUpgradeBytes)
+		if err != nil {
+			return err
+		}
+	}
+	chainConfig := *genesis.Config
+	// the nodes set the network upgrades, and all the supported networks are past durango
+	if chainConfig.DurangoTimestamp == nil {
+		chainConfig.DurangoTimestamp = subnetevmutils.NewUint64(0)
+	}
+	upgrades, err := buildSpecUpgrades(spec, &chainConfig, applied, time.Now())
+	if err != nil {
+		return err
+	}
+	jsonBytes, err := json.Marshal(&params.UpgradeConfig{PrecompileUpgrades: upgrades})
+	if err != nil {
+		return err
+	}
+	if _, err := validateUpgradeBytes(jsonBytes, lockUpgradeBytes, true); err != nil {
+		return err
+	}
+	if len(applied) > 0 {
+		ux.Logger.PrintToUser("Keeping the %d upgrades already applied according to the lock file", len(applied))
+	}
+	for _, upgrade := range upgrades[len(applied):] {
+		action := enableAction
+		if upgrade.IsDisabled() {
+			action = disableAction
+		}
+		ux.Logger.PrintToUser("%s %s at %s", action, upgrade.Key(),
+			time.Unix(int64(*upgrade.Timestamp()), 0).UTC().Format(constants.TimeParseLayout))
+	}
+	return app.WriteUpgradeFile(subnetName, jsonBytes)
+}
+
 func queryActivationTimestamp() (time.Time, error) {
 	const (
 		in5min   = "In 5 minutes"
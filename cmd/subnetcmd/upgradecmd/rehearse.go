@@ -0,0 +1,599 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/localnetworkinterface"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	ANRclient "github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// rehearsalActivationDelay leaves time to restart the rehearsal network
+	// with the upgrade bytes before the first upgrade activates
+	rehearsalActivationDelay = time.Minute
+	rehearsalActivationStep  = 5 * time.Second
+	rehearsalSnapshotName    = "upgrade-rehearsal"
+	rehearsalBackupSuffix    = "-rehearsal-backup"
+)
+
+var (
+	rehearsalAvagoVersion string
+
+	errNoUpgradesToRehearse = errors.New("the upgrade file has no new upgrades on top of the lock file to rehearse")
+)
+
+// avalanche subnet upgrade rehearse
+func newUpgradeRehearseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rehearse [subnetName]",
+		Short: "Rehearse the upgrade bytes on a local network",
+		Long: `The subnet upgrade rehearse command rehearses the upgrade file of a Subnet-EVM Subnet before it is
+applied on a public network, where upgrades are irreversible.
+
+It boots a local network with the subnet's genesis, chain and subnet configs and the upgrades already
+applied according to the lock file. Then it restarts the network with the new upgrade file, moving the
+activation times of the new upgrades a minute into the future, and once they are active it checks that
+blocks are still produced and that the chain reports the expected precompile configs through RPC.
+
+The local network must be stopped. Its state is set aside during the rehearsal and restored afterwards,
+also when the rehearsal is interrupted. If the rehearsal is killed, the next one restores it first.
+
+The tx checking block production is issued from ewoq or a stored key with a balance on the chain,
+which must be on the tx allow list if the chain has one.
+
+upgrade apply on Fuji or Mainnet requires a passing rehearsal of the current upgrade file.`,
+		RunE: rehearseCmd,
+		Args: cobra.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&rehearsalAvagoVersion, "avalanchego-version", "", "use this version of avalanchego (default: latest compatible with the subnet)")
+	return cmd
+}
+
+func rehearseCmd(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.SubnetConfigExists(subnetName) {
+		return errors.New("subnet does not exist")
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return fmt.Errorf("unable to load sidecar: %w", err)
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("upgrade rehearsals are only supported for %s subnets", models.SubnetEvm)
+	}
+	upgradeBytes, err := app.ReadUpgradeFile(subnetName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			ux.Logger.PrintToUser("No file with upgrade specs for the given subnet has been found")
+			ux.Logger.PrintToUser("You may need to first create it with the `avalanche subnet upgrade generate` command or import it")
+		}
+		return err
+	}
+	lockUpgradeBytes, err := app.ReadLockUpgradeFile(subnetName)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	upgrades, err := validateUpgradeBytes(upgradeBytes, lockUpgradeBytes, true)
+	if err != nil {
+		return err
+	}
+	applied := []params.PrecompileUpgrade{}
+	if len(lockUpgradeBytes) > 0 {
+		applied, err = getAllUpgrades(lockUpgradeBytes)
+		if err != nil {
+			return err
+		}
+	}
+	pending := pendingUpgrades(upgrades, applied)
+	if len(pending) == 0 {
+		return errNoUpgradesToRehearse
+	}
+
+	_, _, networkRunning, err := localnetworkinterface.NewStatusChecker().GetCurrentNetworkVersion()
+	if err != nil {
+		return err
+	}
+	if networkRunning {
+		return errors.New("the rehearsal boots its own local network. Stop the local network first with avalanche network stop")
+	}
+
+	checks, err := rehearse(subnetName, &sc, applied, pending, lockUpgradeBytes)
+	if err != nil {
+		return err
+	}
+	rehearsal := &models.UpgradeRehearsal{
+		UpgradeSHA256: upgradeFileHash(upgradeBytes),
+		Time:          time.Now().UTC(),
+		Passed:        true,
+		Checks:        checks,
+	}
+	for _, check := range checks {
+		rehearsal.Passed = rehearsal.Passed && check.Passed
+	}
+	printRehearsal(rehearsal)
+	if err := app.WriteUpgradeRehearsal(subnetName, rehearsal); err != nil {
+		return err
+	}
+	if !rehearsal.Passed {
+		return errors.New("the upgrade rehearsal failed")
+	}
+	return nil
+}
+
+// pendingUpgrades returns the upgrades of [upgrades] not yet in [applied]
+func pendingUpgrades(upgrades []params.PrecompileUpgrade, applied []params.PrecompileUpgrade) []params.PrecompileUpgrade {
+	pending := []params.PrecompileUpgrade{}
+	for _, u := range upgrades {
+		isApplied := false
+		for _, a := range applied {
+			if reflect.DeepEqual(u, a) {
+				isApplied = true
+				break
+			}
+		}
+		if !isApplied {
+			pending = append(pending, u)
+		}
+	}
+	return pending
+}
+
+// rehearsalUpgrades moves the timestamps of [pending] to [start] and after,
+// [step] apart, keeping their order and which of them activate together
+func rehearsalUpgrades(pending []params.PrecompileUpgrade, start time.Time, step time.Duration) ([]params.PrecompileUpgrade, error) {
+	rehearsed := []params.PrecompileUpgrade{}
+	ts := uint64(start.Unix())
+	for i, upgrade := range pending {
+		if upgrade.Timestamp() == nil {
+			return nil, errNoBlockTimestamp
+		}
+		if i > 0 && *upgrade.Timestamp() != *pending[i-1].Timestamp() {
+			ts += uint64(step.Seconds())
+		}
+		upgradeBytes, err := json.Marshal(&upgrade)
+		if err != nil {
+			return nil, err
+		}
+		var upgradeMap map[string]map[string]interface{}
+		if err := json.Unmarshal(upgradeBytes, &upgradeMap); err != nil {
+			return nil, err
+		}
+		for _, config := range upgradeMap {
+			config[blockTimestampKey] = ts
+		}
+		upgradeBytes, err = json.Marshal(upgradeMap)
+		if err != nil {
+			return nil, err
+		}
+		var moved params.PrecompileUpgrade
+		if err := json.Unmarshal(upgradeBytes, &moved); err != nil {
+			return nil, err
+		}
+		rehearsed = append(rehearsed, moved)
+	}
+	return rehearsed, nil
+}
+
+// rehearse deploys the subnet into a fresh local network with the [applied]
+// upgrades, restarts it with the [pending] ones moved into the near future,
+// and checks the chain once they are active
+func rehearse(
+	subnetName string,
+	sc *models.Sidecar,
+	applied []params.PrecompileUpgrade,
+	pending []params.PrecompileUpgrade,
+	lockUpgradeBytes []byte,
+) ([]models.UpgradeRehearsalCheck, error) {
+	restoreState, err := setAsideLocalNetworkState(app.GetSnapshotsDir())
+	if err != nil {
+		return nil, err
+	}
+	defer restoreState()
+	// an interrupted rehearsal still stops its network and restores the local
+	// network state. If it is killed, the next rehearsal restores it
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	defer func() {
+		signal.Stop(sigc)
+		close(done)
+	}()
+	go func() {
+		select {
+		case <-sigc:
+			ux.Logger.PrintToUser("Rehearsal interrupted, restoring the local network state...")
+			stopRehearsalNetwork()
+			restoreState()
+			os.Exit(1)
+		case <-done:
+		}
+	}()
+
+	_, vmBin, err := binutils.SetupSubnetEVM(app, sc.VMVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install subnet-evm: %w", err)
+	}
+	avagoVersion := rehearsalAvagoVersion
+	if avagoVersion == "" {
+		avagoVersion, err = vm.GetLatestAvalancheGoByProtocolVersion(app, sc.RPCVersion, constants.AvalancheGoCompatibilityURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	genesis, err := app.LoadRawGenesis(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	deployer := subnet.NewLocalDeployer(app, avagoVersion, "", vmBin)
+	if len(lockUpgradeBytes) > 0 {
+		lockUpgradePath := app.GetUpgradeBytesFilePath(subnetName) + constants.UpgradeBytesLockExtension
+		deployer.SetNetworkUpgrade(lockUpgradePath)
+	}
+
+	ux.Logger.PrintToUser("Booting the rehearsal network with %s and its %d applied upgrades...", subnetName, len(applied))
+	defer stopRehearsalNetwork()
+	deployInfo, err := deployer.DeployToLocalNetwork(subnetName, genesis, app.GetGenesisPath(subnetName), true, "")
+	if err != nil {
+		return nil, err
+	}
+	checks := []models.UpgradeRehearsalCheck{{Name: "Network booted with the applied upgrades", Passed: true}}
+
+	rehearsed, err := rehearsalUpgrades(pending, time.Now().Add(rehearsalActivationDelay), rehearsalActivationStep)
+	if err != nil {
+		return nil, err
+	}
+	allUpgrades := append(append([]params.PrecompileUpgrade{}, applied...), rehearsed...)
+	rehearsalBytes, err := json.Marshal(&params.UpgradeConfig{PrecompileUpgrades: allUpgrades})
+	if err != nil {
+		return nil, err
+	}
+	ux.Logger.PrintToUser("Restarting the rehearsal network with the %d new upgrades...", len(pending))
+	if err := restartWithUpgrades(deployInfo.BlockchainID.String(), string(rehearsalBytes)); err != nil {
+		checks = append(checks, models.UpgradeRehearsalCheck{
+			Name:    "Nodes restarted healthy with the upgrade bytes",
+			Details: err.Error(),
+		})
+		return checks, nil
+	}
+	checks = append(checks, models.UpgradeRehearsalCheck{Name: "Nodes restarted healthy with the upgrade bytes", Passed: true})
+
+	rpcURL := models.NewLocalNetwork().BlockchainEndpoint(deployInfo.BlockchainID.String())
+	checks = append(checks, checkLoadedUpgrades(rpcURL, len(allUpgrades)))
+
+	lastActivation := time.Unix(int64(*rehearsed[len(rehearsed)-1].Timestamp()), 0)
+	ux.Logger.PrintToUser("Waiting for the upgrades to activate at %s...", lastActivation.Local().Format(constants.TimeParseLayout))
+	time.Sleep(time.Until(lastActivation) + rehearsalActivationStep)
+
+	evmGenesis, err := app.LoadEvmGenesis(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	blockCheck := checkBlockProduction(rpcURL, lastActivation, activeTxAllowList(evmGenesis.Config, allUpgrades))
+	checks = append(checks, blockCheck)
+	if blockCheck.Passed {
+		checks = append(checks, checkActivePrecompiles(rpcURL, evmGenesis.Config, allUpgrades))
+	}
+	return checks, nil
+}
+
+// setAsideLocalNetworkState moves the local network state in [snapshotsDir]
+// aside, so the rehearsal starts from the bootstrap snapshot. A state left
+// aside by an interrupted rehearsal is restored first. The returned func
+// removes the rehearsal state and restores the local network one, and can be
+// called more than once
+func setAsideLocalNetworkState(snapshotsDir string) (func(), error) {
+	defaultSnapshotPath := filepath.Join(snapshotsDir, "anr-snapshot-"+constants.DefaultSnapshotName)
+	backupPath := defaultSnapshotPath + rehearsalBackupSuffix
+	if utils.DirectoryExists(backupPath) {
+		if err := restoreLocalNetworkState(defaultSnapshotPath, backupPath); err != nil {
+			return nil, fmt.Errorf("failed to restore the local network state from %s: %w", backupPath, err)
+		}
+		ux.Logger.PrintToUser("Restored the local network state left aside by an interrupted rehearsal")
+	}
+	hasState := utils.DirectoryExists(defaultSnapshotPath)
+	if hasState {
+		if err := os.Rename(defaultSnapshotPath, backupPath); err != nil {
+			return nil, fmt.Errorf("failed to set aside the local network state: %w", err)
+		}
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if !hasState {
+				if err := os.RemoveAll(defaultSnapshotPath); err != nil {
+					app.Log.Warn("failed to remove the rehearsal network state", zap.Error(err))
+				}
+				return
+			}
+			if err := restoreLocalNetworkState(defaultSnapshotPath, backupPath); err != nil {
+				ux.Logger.PrintToUser("Failed to restore the local network state from %s: %s", backupPath, err)
+			}
+		})
+	}, nil
+}
+
+// restoreLocalNetworkState replaces the snapshot at [defaultSnapshotPath],
+// left by a rehearsal, with the local network state at [backupPath]
+func restoreLocalNetworkState(defaultSnapshotPath string, backupPath string) error {
+	if err := os.RemoveAll(defaultSnapshotPath); err != nil {
+		return err
+	}
+	return os.Rename(backupPath, defaultSnapshotPath)
+}
+
+// restartWithUpgrades restarts the local network with [upgradeBytes] as the
+// upgrade file of [blockchainID], the same way upgrade apply does
+func restartWithUpgrades(blockchainID string, upgradeBytes string) error {
+	cli, err := binutils.NewGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	if _, err := cli.SaveSnapshot(ctx, rehearsalSnapshotName); err != nil {
+		return err
+	}
+	if _, err := cli.LoadSnapshot(
+		ctx,
+		rehearsalSnapshotName,
+		ANRclient.WithUpgradeConfigs(map[string]string{blockchainID: upgradeBytes}),
+	); err != nil {
+		return err
+	}
+	_, err = subnet.WaitForHealthy(ctx, cli)
+	return err
+}
+
+func stopRehearsalNetwork() {
+	cli, err := binutils.NewGRPCClient(binutils.WithAvoidRPCVersionCheck(true))
+	if err == nil {
+		ctx, cancel := utils.GetANRContext()
+		if _, err := cli.Stop(ctx); err != nil {
+			app.Log.Debug("failed to stop the rehearsal network", zap.Error(err))
+		}
+		if _, err := cli.RemoveSnapshot(ctx, rehearsalSnapshotName); err != nil {
+			app.Log.Debug("failed to remove the rehearsal snapshot", zap.Error(err))
+		}
+		cancel()
+		cli.Close()
+	}
+	if err := binutils.KillgRPCServerProcess(app); err != nil {
+		app.Log.Warn("failed killing server process", zap.Error(err))
+	}
+}
+
+func checkLoadedUpgrades(rpcURL string, expected int) models.UpgradeRehearsalCheck {
+	check := models.UpgradeRehearsalCheck{Name: "Nodes loaded the upgrade bytes"}
+	chainConfig, err := evm.GetChainConfig(rpcURL)
+	if err != nil {
+		check.Details = err.Error()
+		return check
+	}
+	loaded := len(chainConfig.UpgradeConfig.PrecompileUpgrades)
+	check.Passed = loaded == expected
+	check.Details = fmt.Sprintf("%d of %d upgrades loaded", loaded, expected)
+	return check
+}
+
+// checkBlockProduction issues a tx once the upgrades are active, and checks it
+// gets into a block built under the new rules. If [txAllowList] is not nil,
+// the tx is issued from a key allowed by it
+func checkBlockProduction(rpcURL string, lastActivation time.Time, txAllowList *allowlist.AllowListConfig) models.UpgradeRehearsalCheck {
+	check := models.UpgradeRehearsalCheck{Name: "Blocks produced after activation"}
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		check.Details = err.Error()
+		return check
+	}
+	privateKey, address, err := findFundedKey(client, txAllowList)
+	if err != nil {
+		check.Details = err.Error()
+		return check
+	}
+	if err := evm.FundAddress(client, privateKey, address, big.NewInt(0)); err != nil {
+		check.Details = fmt.Sprintf("tx from %s failed: %s", address, err)
+		return check
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		check.Details = err.Error()
+		return check
+	}
+	blockTime := time.Unix(int64(header.Time), 0)
+	check.Passed = !blockTime.Before(lastActivation)
+	check.Details = fmt.Sprintf("block %d at %s", header.Number, blockTime.Local().Format(constants.TimeParseLayout))
+	return check
+}
+
+// activeTxAllowList returns the tx allow list in force once [upgrades] are
+// active on top of [chainConfig], or nil if txs are not restricted
+func activeTxAllowList(chainConfig *params.ChainConfig, upgrades []params.PrecompileUpgrade) *allowlist.AllowListConfig {
+	txAllowList := vm.GetAllowListConfig(chainConfig.GenesisPrecompiles[txallowlist.ConfigKey])
+	for _, upgrade := range upgrades {
+		if upgrade.Key() != txallowlist.ConfigKey {
+			continue
+		}
+		if upgrade.IsDisabled() {
+			txAllowList = nil
+		} else {
+			txAllowList = vm.GetAllowListConfig(upgrade.Config)
+		}
+	}
+	return txAllowList
+}
+
+// isTxAllowed tells if [address] can issue txs under [txAllowList]
+func isTxAllowed(txAllowList *allowlist.AllowListConfig, address common.Address) bool {
+	if txAllowList == nil {
+		return true
+	}
+	return slices.Contains(txAllowList.AdminAddresses, address) ||
+		slices.Contains(txAllowList.ManagerAddresses, address) ||
+		slices.Contains(txAllowList.EnabledAddresses, address)
+}
+
+// findFundedKey returns the first of ewoq and the stored keys allowed by
+// [txAllowList] with a balance on the chain of [client]
+func findFundedKey(client ethclient.Client, txAllowList *allowlist.AllowListConfig) (string, string, error) {
+	network := models.NewLocalNetwork()
+	candidates := []*key.SoftKey{}
+	if k, err := key.LoadEwoq(network.ID); err == nil {
+		candidates = append(candidates, k)
+	}
+	files, err := os.ReadDir(app.GetKeyDir())
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", err
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), constants.KeySuffix) {
+			continue
+		}
+		if k, err := key.LoadSoft(network.ID, filepath.Join(app.GetKeyDir(), f.Name())); err == nil {
+			candidates = append(candidates, k)
+		}
+	}
+	for _, k := range candidates {
+		if !isTxAllowed(txAllowList, common.HexToAddress(k.C())) {
+			continue
+		}
+		balance, err := evm.GetAddressBalance(client, k.C())
+		if err != nil {
+			return "", "", err
+		}
+		if balance.Sign() > 0 {
+			return hex.EncodeToString(k.Raw()), k.C(), nil
+		}
+	}
+	if txAllowList != nil {
+		return "", "", errors.New("none of ewoq and the stored keys is both on the tx allow list and funded on the chain to issue a tx. Import one with avalanche key create --file")
+	}
+	return "", "", errors.New("none of ewoq and the stored keys is funded on the chain to issue a tx")
+}
+
+// checkActivePrecompiles compares the precompiles the chain reports as active
+// with the ones expected after [upgrades]
+func checkActivePrecompiles(rpcURL string, chainConfig *params.ChainConfig, upgrades []params.PrecompileUpgrade) models.UpgradeRehearsalCheck {
+	check := models.UpgradeRehearsalCheck{Name: "Precompile configs read via RPC"}
+	active, err := evm.GetActivePrecompiles(rpcURL, nil)
+	if err != nil {
+		check.Details = err.Error()
+		return check
+	}
+	expected := map[string]params.PrecompileUpgrade{}
+	for key, config := range chainConfig.GenesisPrecompiles {
+		if config.Timestamp() != nil {
+			expected[key] = params.PrecompileUpgrade{Config: config}
+		}
+	}
+	for _, upgrade := range upgrades {
+		if upgrade.IsDisabled() {
+			delete(expected, upgrade.Key())
+		} else {
+			expected[upgrade.Key()] = upgrade
+		}
+	}
+	mismatches := []string{}
+	for key, upgrade := range expected {
+		config, ok := active[key]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, key+" is not active")
+		case !config.Equal(upgrade.Config):
+			mismatches = append(mismatches, key+" has an unexpected config")
+		}
+	}
+	for key := range active {
+		if _, ok := expected[key]; !ok {
+			mismatches = append(mismatches, key+" should not be active")
+		}
+	}
+	check.Passed = len(mismatches) == 0
+	if check.Passed {
+		check.Details = fmt.Sprintf("%d precompiles active as expected", len(expected))
+	} else {
+		check.Details = strings.Join(mismatches, ", ")
+	}
+	return check
+}
+
+func printRehearsal(rehearsal *models.UpgradeRehearsal) {
+	ux.Logger.PrintToUser("")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Check", "Result", "Details"})
+	table.SetRowLine(true)
+	for _, check := range rehearsal.Checks {
+		result := "PASS"
+		if !check.Passed {
+			result = "FAIL"
+		}
+		table.Append([]string{check.Name, result, check.Details})
+	}
+	table.Render()
+	if rehearsal.Passed {
+		ux.Logger.PrintToUser("Rehearsal passed. The upgrade file can be applied on public networks")
+	} else {
+		ux.Logger.PrintToUser("Rehearsal failed. Fix the upgrade file before applying it on public networks")
+	}
+}
+
+func upgradeFileHash(upgradeBytes []byte) string {
+	hash := sha256.Sum256(upgradeBytes)
+	return hex.EncodeToString(hash[:])
+}
+
+// checkRehearsal fails unless the current upgrade file of [subnetName] passed
+// a rehearsal
+func checkRehearsal(subnetName string) error {
+	upgradeBytes, err := app.ReadUpgradeFile(subnetName)
+	if err != nil {
+		return err
+	}
+	rehearsal, err := app.LoadUpgradeRehearsal(subnetName)
+	if err != nil {
+		return err
+	}
+	hint := fmt.Sprintf("rehearse it first with avalanche subnet upgrade rehearse %s, or use --%s", subnetName, skipRehearsalFlag)
+	switch {
+	case rehearsal == nil || rehearsal.UpgradeSHA256 != upgradeFileHash(upgradeBytes):
+		return fmt.Errorf("the upgrade file has not been rehearsed: %s", hint)
+	case !rehearsal.Passed:
+		return fmt.Errorf("the rehearsal of the upgrade file failed at %s: %s", rehearsal.Time.Local().Format(constants.TimeParseLayout), hint)
+	}
+	return nil
+}
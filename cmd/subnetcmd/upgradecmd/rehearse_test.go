@@ -0,0 +1,148 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRehearsalUpgrades(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	spec, err := loadUpgradeSpec(writeSpec(t, `
+upgrades:
+  - precompile: txAllowListConfig
+    action: reconfigure
+    in: 30d
+    enabledAddresses: [`+testAdmin+`]
+  - precompile: feeManagerConfig
+    in: 30d
+    adminAddresses: [`+testAdmin+`]
+  - precompile: contractNativeMinterConfig
+    in: 60d
+    adminAddresses: [`+testAdmin+`]
+`))
+	require.NoError(err)
+	upgrades, err := buildSpecUpgrades(spec, testChainConfig(), nil, now)
+	require.NoError(err)
+	require.Len(upgrades, 4)
+
+	start := time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC)
+	rehearsed, err := rehearsalUpgrades(upgrades, start, 5*time.Second)
+	require.NoError(err)
+	require.Len(rehearsed, 4)
+	ts := uint64(start.Unix())
+	// the allow list disable and the fee manager activate together in the upgrade file
+	for i, expected := range []uint64{ts, ts, ts + 5, ts + 10} {
+		require.Equal(upgrades[i].Key(), rehearsed[i].Key())
+		require.Equal(upgrades[i].IsDisabled(), rehearsed[i].IsDisabled())
+		require.Equal(expected, *rehearsed[i].Timestamp())
+	}
+	require.NoError(verifyPrecompileUpgrades(testChainConfig(), rehearsed))
+
+	require.Empty(pendingUpgrades(upgrades, upgrades))
+	require.Equal(upgrades[2:], pendingUpgrades(upgrades, upgrades[:2]))
+}
+
+func TestCheckRehearsal(t *testing.T) {
+	require := require.New(t)
+	app = &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+	subnetName := "test"
+	require.NoError(os.MkdirAll(filepath.Join(app.GetSubnetDir(), subnetName), 0o755))
+	upgradeBytes := []byte(`{"precompileUpgrades":[]}`)
+	require.NoError(app.WriteUpgradeFile(subnetName, upgradeBytes))
+
+	require.ErrorContains(checkRehearsal(subnetName), "has not been rehearsed")
+
+	rehearsal := &models.UpgradeRehearsal{UpgradeSHA256: upgradeFileHash(upgradeBytes), Time: time.Now()}
+	require.NoError(app.WriteUpgradeRehearsal(subnetName, rehearsal))
+	require.ErrorContains(checkRehearsal(subnetName), "rehearsal of the upgrade file failed")
+
+	rehearsal.Passed = true
+	require.NoError(app.WriteUpgradeRehearsal(subnetName, rehearsal))
+	require.NoError(checkRehearsal(subnetName))
+
+	// changing the upgrade file requires a new rehearsal
+	require.NoError(app.WriteUpgradeFile(subnetName, []byte(`{"precompileUpgrades":[{}]}`)))
+	require.ErrorContains(checkRehearsal(subnetName), "has not been rehearsed")
+}
+
+func TestActiveTxAllowList(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	admin := common.HexToAddress(testAdmin)
+	enabled := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+
+	txAllowList := activeTxAllowList(testChainConfig(), nil)
+	require.NotNil(txAllowList)
+	require.True(isTxAllowed(txAllowList, admin))
+	require.False(isTxAllowed(txAllowList, enabled))
+
+	spec, err := loadUpgradeSpec(writeSpec(t, `
+upgrades:
+  - precompile: txAllowListConfig
+    action: reconfigure
+    in: 30d
+    enabledAddresses: [`+enabled.Hex()+`]
+`))
+	require.NoError(err)
+	upgrades, err := buildSpecUpgrades(spec, testChainConfig(), nil, now)
+	require.NoError(err)
+	txAllowList = activeTxAllowList(testChainConfig(), upgrades)
+	require.NotNil(txAllowList)
+	require.True(isTxAllowed(txAllowList, enabled))
+	require.False(isTxAllowed(txAllowList, admin))
+
+	// disabling the allow list lets anybody issue txs
+	txAllowList = activeTxAllowList(testChainConfig(), upgrades[:1])
+	require.Nil(txAllowList)
+	require.True(isTxAllowed(txAllowList, admin))
+}
+
+func TestSetAsideLocalNetworkState(t *testing.T) {
+	require := require.New(t)
+	app = &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	snapshotsDir := t.TempDir()
+	defaultSnapshotPath := filepath.Join(snapshotsDir, "anr-snapshot-"+constants.DefaultSnapshotName)
+	backupPath := defaultSnapshotPath + rehearsalBackupSuffix
+	require.NoError(os.MkdirAll(defaultSnapshotPath, 0o755))
+	require.NoError(os.WriteFile(filepath.Join(defaultSnapshotPath, "state"), []byte("local"), 0o600))
+
+	_, err := setAsideLocalNetworkState(snapshotsDir)
+	require.NoError(err)
+	require.NoDirExists(defaultSnapshotPath)
+	require.DirExists(backupPath)
+
+	// the rehearsal is killed after leaving its own state
+	require.NoError(os.MkdirAll(defaultSnapshotPath, 0o755))
+	require.NoError(os.WriteFile(filepath.Join(defaultSnapshotPath, "state"), []byte("rehearsal"), 0o600))
+
+	// the next rehearsal restores the local network state before setting it aside
+	restore, err := setAsideLocalNetworkState(snapshotsDir)
+	require.NoError(err)
+	require.NoDirExists(defaultSnapshotPath)
+	require.DirExists(backupPath)
+	restore()
+	restore()
+	state, err := os.ReadFile(filepath.Join(defaultSnapshotPath, "state"))
+	require.NoError(err)
+	require.Equal("local", string(state))
+	require.NoDirExists(backupPath)
+}
@@ -0,0 +1,323 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ava-labs/subnet-evm/precompile/precompileconfig"
+	subnetevmutils "github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	enableAction   = "enable"
+	disableAction  = "disable"
+	reconfigAction = "reconfigure"
+)
+
+// upgradeSpec is the non interactive description of the upgrades generated
+// by upgrade generate --from
+type upgradeSpec struct {
+	Upgrades []upgradeSpecEntry `json:"upgrades"`
+}
+
+// upgradeSpecEntry enables, disables or reconfigures a precompile, given by
+// its upgrade.json key (eg. feeManagerConfig), at an absolute time [At]
+// ('YYYY-MM-DD HH:MM:SS' UTC or RFC3339) or after a duration [In] (eg. 10m,
+// 2d, 1w)
+type upgradeSpecEntry struct {
+	Precompile string `json:"precompile"`
+	// Action defaults to enable. Reconfigure disables an enabled precompile
+	// and enables it again one second later with the new config
+	Action string `json:"action,omitempty"`
+	At     string `json:"at,omitempty"`
+	In     string `json:"in,omitempty"`
+	// allow list settings of all precompiles but warp
+	AdminAddresses   []common.Address `json:"adminAddresses,omitempty"`
+	ManagerAddresses []common.Address `json:"managerAddresses,omitempty"`
+	EnabledAddresses []common.Address `json:"enabledAddresses,omitempty"`
+	// feeManagerConfig only
+	InitialFeeConfig *commontype.FeeConfig `json:"initialFeeConfig,omitempty"`
+	// contractNativeMinterConfig only, amounts in wei
+	InitialMint map[common.Address]json.Number `json:"initialMint,omitempty"`
+	// rewardManagerConfig only
+	InitialRewardConfig *rewardmanager.InitialRewardConfig `json:"initialRewardConfig,omitempty"`
+	// warpConfig only
+	QuorumNumerator uint64 `json:"quorumNumerator,omitempty"`
+}
+
+// loadUpgradeSpec reads a YAML (or JSON) upgrade spec, rejecting unknown fields
+func loadUpgradeSpec(path string) (*upgradeSpec, error) {
+	specBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// the precompile types only know how to decode themselves from JSON
+	var doc interface{}
+	if err := yaml.Unmarshal(specBytes, &doc); err != nil {
+		return nil, fmt.Errorf("invalid upgrade spec %s: %w", path, err)
+	}
+	jsonBytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upgrade spec %s: %w", path, err)
+	}
+	spec := &upgradeSpec{}
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(spec); err != nil {
+		return nil, fmt.Errorf("invalid upgrade spec %s: %w", path, err)
+	}
+	if len(spec.Upgrades) == 0 {
+		return nil, fmt.Errorf("upgrade spec %s has no upgrades", path)
+	}
+	return spec, nil
+}
+
+func (e upgradeSpecEntry) timestamp(now time.Time) (uint64, error) {
+	var t time.Time
+	switch {
+	case e.At != "" && e.In != "":
+		return 0, errors.New("only one of at and in can be given")
+	case e.At != "":
+		var err error
+		t, err = time.Parse(constants.TimeParseLayout, e.At)
+		if err != nil {
+			t, err = time.Parse(time.RFC3339, e.At)
+			if err != nil {
+				return 0, fmt.Errorf("invalid time %q, expected 'YYYY-MM-DD HH:MM:SS' or RFC3339", e.At)
+			}
+		}
+	case e.In != "":
+		d, err := utils.ParseDuration(e.In)
+		if err != nil {
+			return 0, err
+		}
+		t = now.Add(d)
+	default:
+		return 0, errors.New("one of at and in must be given")
+	}
+	if !t.After(now) {
+		return 0, fmt.Errorf("activation time %s is not in the future", t.UTC().Format(constants.TimeParseLayout))
+	}
+	return uint64(t.Unix()), nil
+}
+
+func (e upgradeSpecEntry) enableConfig(timestamp uint64) (precompileconfig.Config, error) {
+	ts := subnetevmutils.NewUint64(timestamp)
+	if e.Precompile != feemanager.ConfigKey && e.InitialFeeConfig != nil {
+		return nil, errors.New("initialFeeConfig is only supported by " + feemanager.ConfigKey)
+	}
+	if e.Precompile != nativeminter.ConfigKey && len(e.InitialMint) > 0 {
+		return nil, errors.New("initialMint is only supported by " + nativeminter.ConfigKey)
+	}
+	if e.Precompile != rewardmanager.ConfigKey && e.InitialRewardConfig != nil {
+		return nil, errors.New("initialRewardConfig is only supported by " + rewardmanager.ConfigKey)
+	}
+	if e.Precompile != warp.ConfigKey && e.QuorumNumerator != 0 {
+		return nil, errors.New("quorumNumerator is only supported by " + warp.ConfigKey)
+	}
+	hasAllowList := len(e.AdminAddresses)+len(e.ManagerAddresses)+len(e.EnabledAddresses) > 0
+	switch e.Precompile {
+	case warp.ConfigKey:
+		if hasAllowList {
+			return nil, errors.New(warp.ConfigKey + " has no allow list")
+		}
+		return warp.NewConfig(ts, e.QuorumNumerator), nil
+	case rewardmanager.ConfigKey:
+		return rewardmanager.NewConfig(ts, e.AdminAddresses, e.EnabledAddresses, e.ManagerAddresses, e.InitialRewardConfig), nil
+	}
+	if !hasAllowList {
+		return nil, fmt.Errorf("at least one of %s and %s is required", adminAddressesKey, enabledAddressesKey)
+	}
+	switch e.Precompile {
+	case deployerallowlist.ConfigKey:
+		return deployerallowlist.NewConfig(ts, e.AdminAddresses, e.EnabledAddresses, e.ManagerAddresses), nil
+	case txallowlist.ConfigKey:
+		return txallowlist.NewConfig(ts, e.AdminAddresses, e.EnabledAddresses, e.ManagerAddresses), nil
+	case feemanager.ConfigKey:
+		return feemanager.NewConfig(ts, e.AdminAddresses, e.EnabledAddresses, e.ManagerAddresses, e.InitialFeeConfig), nil
+	case nativeminter.ConfigKey:
+		initialMint := map[common.Address]*math.HexOrDecimal256{}
+		for addr, amountStr := range e.InitialMint {
+			amount, ok := new(big.Int).SetString(amountStr.String(), 10)
+			if !ok || amount.Sign() < 0 {
+				return nil, fmt.Errorf("invalid %s amount %q for %s, big amounts must be quoted", initialMintKey, amountStr, addr.Hex())
+			}
+			initialMint[addr] = (*math.HexOrDecimal256)(amount)
+		}
+		return nativeminter.NewConfig(ts, e.AdminAddresses, e.EnabledAddresses, e.ManagerAddresses, initialMint), nil
+	}
+	return nil, fmt.Errorf("unknown precompile %q", e.Precompile)
+}
+
+func disableConfig(key string, timestamp uint64) (precompileconfig.Config, error) {
+	ts := subnetevmutils.NewUint64(timestamp)
+	switch key {
+	case deployerallowlist.ConfigKey:
+		return deployerallowlist.NewDisableConfig(ts), nil
+	case txallowlist.ConfigKey:
+		return txallowlist.NewDisableConfig(ts), nil
+	case feemanager.ConfigKey:
+		return feemanager.NewDisableConfig(ts), nil
+	case nativeminter.ConfigKey:
+		return nativeminter.NewDisableConfig(ts), nil
+	case rewardmanager.ConfigKey:
+		return rewardmanager.NewDisableConfig(ts), nil
+	case warp.ConfigKey:
+		return warp.NewDisableConfig(ts), nil
+	}
+	return nil, fmt.Errorf("unknown precompile %q", key)
+}
+
+// enabledPrecompiles returns which precompiles are enabled after the genesis
+// of [chainConfig] and [upgrades]
+func enabledPrecompiles(chainConfig *params.ChainConfig, upgrades []params.PrecompileUpgrade) map[string]bool {
+	enabled := map[string]bool{}
+	for key, config := range chainConfig.GenesisPrecompiles {
+		enabled[key] = config.Timestamp() != nil
+	}
+	for _, upgrade := range upgrades {
+		enabled[upgrade.Key()] = !upgrade.IsDisabled()
+	}
+	return enabled
+}
+
+// buildSpecUpgrades appends the upgrades of [spec] to [applied], the upgrades
+// recorded in the lock file, and verifies the result against the genesis
+// chain config [chainConfig]
+func buildSpecUpgrades(
+	spec *upgradeSpec,
+	chainConfig *params.ChainConfig,
+	applied []params.PrecompileUpgrade,
+	now time.Time,
+) ([]params.PrecompileUpgrade, error) {
+	type entry struct {
+		timestamp uint64
+		index     int
+		upgrade   upgradeSpecEntry
+	}
+	entries := []entry{}
+	for i, upgrade := range spec.Upgrades {
+		ts, err := upgrade.timestamp(now)
+		if err != nil {
+			return nil, fmt.Errorf("upgrade %d (%s): %w", i+1, upgrade.Precompile, err)
+		}
+		entries = append(entries, entry{timestamp: ts, index: i, upgrade: upgrade})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].timestamp < entries[j].timestamp })
+
+	upgrades := append([]params.PrecompileUpgrade{}, applied...)
+	enabled := enabledPrecompiles(chainConfig, applied)
+	for _, e := range entries {
+		var configs []precompileconfig.Config
+		action := e.upgrade.Action
+		if action == "" {
+			action = enableAction
+		}
+		switch action {
+		case disableAction:
+			config, err := disableConfig(e.upgrade.Precompile, e.timestamp)
+			if err != nil {
+				return nil, fmt.Errorf("upgrade %d: %w", e.index+1, err)
+			}
+			configs = append(configs, config)
+		case enableAction, reconfigAction:
+			enableTimestamp := e.timestamp
+			if action == reconfigAction && enabled[e.upgrade.Precompile] {
+				config, err := disableConfig(e.upgrade.Precompile, e.timestamp)
+				if err != nil {
+					return nil, fmt.Errorf("upgrade %d: %w", e.index+1, err)
+				}
+				configs = append(configs, config)
+				enableTimestamp++
+			}
+			config, err := e.upgrade.enableConfig(enableTimestamp)
+			if err != nil {
+				return nil, fmt.Errorf("upgrade %d (%s): %w", e.index+1, e.upgrade.Precompile, err)
+			}
+			configs = append(configs, config)
+		default:
+			return nil, fmt.Errorf("upgrade %d: unknown action %q, expected %s, %s or %s",
+				e.index+1, action, enableAction, disableAction, reconfigAction)
+		}
+		for _, config := range configs {
+			upgrades = append(upgrades, params.PrecompileUpgrade{Config: config})
+			enabled[config.Key()] = !config.IsDisabled()
+		}
+	}
+	// a reconfigure may have moved an enable past upgrades of other keys
+	newUpgrades := upgrades[len(applied):]
+	sort.SliceStable(newUpgrades, func(i, j int) bool {
+		return *newUpgrades[i].Timestamp() < *newUpgrades[j].Timestamp()
+	})
+	if err := verifyPrecompileUpgrades(chainConfig, upgrades); err != nil {
+		return nil, err
+	}
+	return upgrades, nil
+}
+
+// verifyPrecompileUpgrades follows the rules subnet-evm applies to the
+// upgrades of a chain: timestamps never decrease, each precompile alternates
+// enables and disables at increasing timestamps, and each config is valid
+func verifyPrecompileUpgrades(chainConfig *params.ChainConfig, upgrades []params.PrecompileUpgrade) error {
+	type lastUpgrade struct {
+		timestamp *uint64
+		disabled  bool
+	}
+	last := map[string]lastUpgrade{}
+	for key, config := range chainConfig.GenesisPrecompiles {
+		if config.Timestamp() != nil {
+			last[key] = lastUpgrade{timestamp: config.Timestamp()}
+		}
+	}
+	var previousTimestamp *uint64
+	for i, upgrade := range upgrades {
+		key := upgrade.Key()
+		ts := upgrade.Timestamp()
+		if ts == nil {
+			return fmt.Errorf("upgrade %s at [%d]: %w", key, i, errNoBlockTimestamp)
+		}
+		if previousTimestamp != nil && *ts < *previousTimestamp {
+			return fmt.Errorf("upgrade %s at [%d]: timestamp %d is before the previous upgrade at %d", key, i, *ts, *previousTimestamp)
+		}
+		prev, ok := last[key]
+		if !ok {
+			prev = lastUpgrade{disabled: true}
+		}
+		if prev.disabled == upgrade.IsDisabled() {
+			if prev.disabled {
+				return fmt.Errorf("upgrade %s at [%d]: the precompile is already disabled", key, i)
+			}
+			return fmt.Errorf("upgrade %s at [%d]: the precompile is already enabled, disable it first or use the %s action", key, i, reconfigAction)
+		}
+		if prev.timestamp != nil && *ts <= *prev.timestamp {
+			return fmt.Errorf("upgrade %s at [%d]: timestamp %d must be after the previous upgrade of the precompile at %d", key, i, *ts, *prev.timestamp)
+		}
+		if err := upgrade.Verify(chainConfig); err != nil {
+			return fmt.Errorf("upgrade %s at [%d]: %w", key, i, err)
+		}
+		last[key] = lastUpgrade{timestamp: ts, disabled: upgrade.IsDisabled()}
+		previousTimestamp = ts
+	}
+	return nil
+}
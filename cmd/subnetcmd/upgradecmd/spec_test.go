@@ -0,0 +1,162 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	subnetevmutils "github.com/ava-labs/subnet-evm/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdmin = "0xb794F5eA0ba39494cE839613fffBA74279579268"
+
+func writeSpec(t *testing.T, spec string) string {
+	path := filepath.Join(t.TempDir(), "upgrade-spec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(spec), 0o600))
+	return path
+}
+
+func testChainConfig() *params.ChainConfig {
+	return &params.ChainConfig{
+		NetworkUpgrades: params.NetworkUpgrades{DurangoTimestamp: subnetevmutils.NewUint64(0)},
+		GenesisPrecompiles: params.Precompiles{
+			txallowlist.ConfigKey: txallowlist.NewConfig(
+				subnetevmutils.NewUint64(0),
+				[]common.Address{common.HexToAddress(testAdmin)},
+				nil,
+				nil,
+			),
+		},
+	}
+}
+
+func TestBuildSpecUpgrades(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	spec, err := loadUpgradeSpec(writeSpec(t, `
+upgrades:
+  - precompile: contractNativeMinterConfig
+    at: "2024-01-03 00:00:00"
+    adminAddresses: [`+testAdmin+`]
+    initialMint:
+      `+testAdmin+`: "1000000000000000000000"
+  - precompile: txAllowListConfig
+    action: reconfigure
+    in: 1d
+    enabledAddresses: [`+testAdmin+`]
+  - precompile: contractNativeMinterConfig
+    action: disable
+    in: 1w
+`))
+	require.NoError(err)
+
+	upgrades, err := buildSpecUpgrades(spec, testChainConfig(), nil, now)
+	require.NoError(err)
+	require.Len(upgrades, 4)
+	// sorted by time, and the reconfigure disables the genesis allow list first
+	require.Equal(txallowlist.ConfigKey, upgrades[0].Key())
+	require.True(upgrades[0].IsDisabled())
+	require.Equal(uint64(now.Add(24*time.Hour).Unix()), *upgrades[0].Timestamp())
+	require.Equal(txallowlist.ConfigKey, upgrades[1].Key())
+	require.False(upgrades[1].IsDisabled())
+	require.Equal(uint64(now.Add(24*time.Hour).Unix())+1, *upgrades[1].Timestamp())
+	require.Equal(nativeminter.ConfigKey, upgrades[2].Key())
+	require.False(upgrades[2].IsDisabled())
+	require.Equal(nativeminter.ConfigKey, upgrades[3].Key())
+	require.True(upgrades[3].IsDisabled())
+	require.Equal(uint64(now.Add(7*24*time.Hour).Unix()), *upgrades[3].Timestamp())
+
+	// the generated file keeps the lock file semantics
+	lockBytes, err := json.Marshal(&params.UpgradeConfig{PrecompileUpgrades: upgrades[:2]})
+	require.NoError(err)
+	lockUpgrades, err := getAllUpgrades(lockBytes)
+	require.NoError(err)
+	spec, err = loadUpgradeSpec(writeSpec(t, `
+upgrades:
+  - precompile: feeManagerConfig
+    in: 2w
+    adminAddresses: [`+testAdmin+`]
+`))
+	require.NoError(err)
+	upgrades, err = buildSpecUpgrades(spec, testChainConfig(), lockUpgrades, now)
+	require.NoError(err)
+	require.Len(upgrades, 3)
+	require.Equal(feemanager.ConfigKey, upgrades[2].Key())
+	upgradeBytes, err := json.Marshal(&params.UpgradeConfig{PrecompileUpgrades: upgrades})
+	require.NoError(err)
+	_, err = validateUpgradeBytes(upgradeBytes, lockBytes, true)
+	require.NoError(err)
+}
+
+func TestBuildSpecUpgradesErrors(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for name, spec := range map[string]string{
+		"already enabled": `
+upgrades:
+  - precompile: txAllowListConfig
+    in: 1d
+    adminAddresses: [` + testAdmin + `]`,
+		"already disabled": `
+upgrades:
+  - precompile: feeManagerConfig
+    action: disable
+    in: 1d`,
+		"in the past": `
+upgrades:
+  - precompile: feeManagerConfig
+    at: "2023-12-31 00:00:00"
+    adminAddresses: [` + testAdmin + `]`,
+		"no time": `
+upgrades:
+  - precompile: feeManagerConfig
+    adminAddresses: [` + testAdmin + `]`,
+		"no allow list": `
+upgrades:
+  - precompile: feeManagerConfig
+    in: 1d`,
+		"wrong precompile setting": `
+upgrades:
+  - precompile: txAllowListConfig
+    action: reconfigure
+    in: 1d
+    adminAddresses: [` + testAdmin + `]
+    quorumNumerator: 70`,
+		"unknown precompile": `
+upgrades:
+  - precompile: fooConfig
+    in: 1d
+    adminAddresses: [` + testAdmin + `]`,
+		"unknown action": `
+upgrades:
+  - precompile: feeManagerConfig
+    action: pause
+    in: 1d`,
+		"same address as admin and enabled": `
+upgrades:
+  - precompile: feeManagerConfig
+    in: 1d
+    adminAddresses: [` + testAdmin + `]
+    enabledAddresses: [` + testAdmin + `]`,
+	} {
+		upgradeSpec, err := loadUpgradeSpec(writeSpec(t, spec))
+		require.NoError(t, err, name)
+		_, err = buildSpecUpgrades(upgradeSpec, testChainConfig(), nil, now)
+		require.Error(t, err, name)
+	}
+
+	_, err := loadUpgradeSpec(writeSpec(t, `
+upgrades:
+  - precompile: feeManagerConfig
+    when: 1d`))
+	require.ErrorContains(t, err, "unknown field")
+}
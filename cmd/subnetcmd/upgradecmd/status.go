@@ -0,0 +1,477 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"github.com/ava-labs/subnet-evm/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	upgradeStatusActive    = "active"
+	upgradeStatusScheduled = "scheduled"
+	upgradeStatusMissed    = "missed"
+)
+
+var statusEndpoint string
+
+// avalanche subnet upgrade status
+func newUpgradeStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [subnetName]",
+		Short: "Show the timeline of the subnet upgrades on a network",
+		Long: `The subnet upgrade status command shows the precompile upgrades of a Subnet-EVM Subnet deployed
+on a network, as a timeline that combines the upgrade file, the lock file of the applied upgrades and
+the upgrades loaded by the nodes. For each upgrade it shows its activation time and whether it is
+already active, and then the precompile configs currently in effect on-chain, including the roles of
+the allow list addresses.
+
+The upgrades loaded by the nodes are read from the chain config RPC. Use --cluster to read them from
+every node of a cluster, or --endpoint to read them from a given node RPC endpoint on public networks.
+
+The command flags drift when a node is missing upgrade bytes that are already active on-chain, or when
+it loaded upgrades that are not in the upgrade file.`,
+		RunE: upgradeStatusCmd,
+		Args: cobra.ExactArgs(1),
+	}
+	cmd.Flags().BoolVar(&useLocal, "local", false, "show the upgrades of the `local` deployment")
+	cmd.Flags().BoolVar(&useFuji, "fuji", false, "show the upgrades of the `fuji` deployment (alias for `testnet`)")
+	cmd.Flags().BoolVar(&useFuji, "testnet", false, "show the upgrades of the `testnet` deployment (alias for `fuji`)")
+	cmd.Flags().BoolVar(&useMainnet, "mainnet", false, "show the upgrades of the `mainnet` deployment")
+	cmd.Flags().StringVar(&clusterName, "cluster", "", "show the upgrades of the deployment of the cluster, read from all its nodes")
+	cmd.Flags().StringVar(&statusEndpoint, "endpoint", "", "read the upgrades from this RPC endpoint of the blockchain")
+	return cmd
+}
+
+// chainCaller issues a JSON-RPC request to a node of the blockchain
+type chainCaller func(result interface{}, method string, args ...interface{}) error
+
+func rpcChainCaller(rpcURL string) chainCaller {
+	return func(result interface{}, method string, args ...interface{}) error {
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		client, err := rpc.DialContext(ctx, rpcURL)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		return client.CallContext(ctx, result, method, args...)
+	}
+}
+
+func sshChainCaller(host *models.Host, blockchainID string) chainCaller {
+	return func(result interface{}, method string, args ...interface{}) error {
+		if args == nil {
+			args = []interface{}{}
+		}
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  method,
+			"params":  args,
+		})
+		if err != nil {
+			return err
+		}
+		resp, err := ssh.RunSSHChainRPC(host, blockchainID, string(requestBody))
+		if err != nil {
+			return err
+		}
+		var response struct {
+			Result json.RawMessage `json:"result"`
+			Error  *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(resp, &response); err != nil {
+			return fmt.Errorf("unable to parse %s response: %w", method, err)
+		}
+		if response.Error != nil {
+			return errors.New(response.Error.Message)
+		}
+		return json.Unmarshal(response.Result, result)
+	}
+}
+
+// upgradeSource is a node the upgrades loaded by the chain are read from
+type upgradeSource struct {
+	name   string
+	caller chainCaller
+}
+
+func upgradeStatusCmd(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	if !app.SubnetConfigExists(subnetName) {
+		return errors.New("subnet does not exist")
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return fmt.Errorf("unable to load sidecar: %w", err)
+	}
+	if sc.VM != models.SubnetEvm {
+		return fmt.Errorf("upgrade status is only supported for %s subnets", models.SubnetEvm)
+	}
+
+	var (
+		network models.Network
+		sources []*upgradeSource
+	)
+	if clusterName != "" {
+		var hosts []*models.Host
+		network, hosts, sources, err = getClusterUpgradeSources(clusterName, sc)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			for _, host := range hosts {
+				_ = host.Disconnect()
+			}
+		}()
+	} else {
+		deployment, err := selectNetworkToUpgrade(sc, []string{})
+		if err != nil {
+			return err
+		}
+		switch deployment {
+		case localDeployment:
+			network = models.NewLocalNetwork()
+		case fujiDeployment:
+			network = models.NewFujiNetwork()
+		case mainnetDeployment:
+			network = models.NewMainnetNetwork()
+		default:
+			return errors.New("unknown deployment")
+		}
+		blockchainID, err := getDeployedBlockchainID(sc, network)
+		if err != nil {
+			return err
+		}
+		rpcURL := statusEndpoint
+		if rpcURL == "" {
+			rpcURL = network.BlockchainEndpoint(blockchainID)
+		}
+		sources = []*upgradeSource{{name: rpcURL, caller: rpcChainCaller(rpcURL)}}
+	}
+
+	fileUpgrades, err := readUpgradesIfExists(app.ReadUpgradeFile(subnetName))
+	if err != nil {
+		return err
+	}
+	lockUpgrades, err := readUpgradesIfExists(app.ReadLockUpgradeFile(subnetName))
+	if err != nil {
+		return err
+	}
+
+	// the first node that answers is used to read the chain state
+	var chain chainCaller
+	nodeUpgrades := map[string][]params.PrecompileUpgrade{}
+	for _, source := range sources {
+		var chainConfig params.ChainConfigWithUpgradesJSON
+		if err := source.caller(&chainConfig, "eth_getChainConfig"); err != nil {
+			ux.Logger.PrintToUser("Failed to read the chain config of %s: %s", source.name, err)
+			continue
+		}
+		nodeUpgrades[source.name] = chainConfig.UpgradeConfig.PrecompileUpgrades
+		if chain == nil {
+			chain = source.caller
+		}
+	}
+	if chain == nil {
+		return fmt.Errorf("failed to read the chain config from the nodes of %s", network.Name())
+	}
+	var head struct {
+		Time hexutil.Uint64 `json:"timestamp"`
+	}
+	if err := chain(&head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return fmt.Errorf("failed to read the last accepted block: %w", err)
+	}
+
+	timeline := buildUpgradeTimeline(fileUpgrades, lockUpgrades, nodeUpgrades, uint64(head.Time))
+	ux.Logger.PrintToUser("Upgrades of %s on %s (last accepted block at %s)", subnetName, network.Name(), formatTimestamp(uint64(head.Time)))
+	printUpgradeTimeline(timeline, len(nodeUpgrades))
+
+	active := params.Precompiles{}
+	if err := chain(&active, "eth_getActivePrecompilesAt", nil); err != nil {
+		return fmt.Errorf("failed to read the active precompiles: %w", err)
+	}
+	printActivePrecompiles(chain, active)
+
+	drift := upgradeDrift(timeline, nodeUpgrades)
+	if len(drift) == 0 {
+		ux.Logger.PrintToUser("No drift found between the upgrade files and the nodes")
+		return nil
+	}
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Drift found:")
+	for _, d := range drift {
+		ux.Logger.PrintToUser("  - %s", d)
+	}
+	return nil
+}
+
+func getClusterUpgradeSources(clusterName string, sc models.Sidecar) (models.Network, []*models.Host, []*upgradeSource, error) {
+	exists, err := app.ClusterExists(clusterName)
+	if err != nil {
+		return models.Network{}, nil, nil, err
+	}
+	if !exists {
+		return models.Network{}, nil, nil, fmt.Errorf("cluster %q does not exist", clusterName)
+	}
+	network, err := app.GetClusterNetwork(clusterName)
+	if err != nil {
+		return models.Network{}, nil, nil, err
+	}
+	blockchainID, err := getDeployedBlockchainID(sc, network)
+	if err != nil {
+		return models.Network{}, nil, nil, err
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return models.Network{}, nil, nil, err
+	}
+	sources := []*upgradeSource{}
+	for _, host := range hosts {
+		sources = append(sources, &upgradeSource{name: host.GetCloudID(), caller: sshChainCaller(host, blockchainID)})
+	}
+	return network, hosts, sources, nil
+}
+
+func getDeployedBlockchainID(sc models.Sidecar, network models.Network) (string, error) {
+	networkData, ok := sc.Networks[network.Name()]
+	if !ok || networkData.BlockchainID == ids.Empty || networkData.Pending {
+		return "", subnetNotYetDeployed()
+	}
+	return networkData.BlockchainID.String(), nil
+}
+
+func readUpgradesIfExists(upgradeBytes []byte, err error) ([]params.PrecompileUpgrade, error) {
+	if err != nil {
+		if os.IsNotExist(err) || err == os.ErrNotExist {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(upgradeBytes) == 0 {
+		return nil, nil
+	}
+	return getAllUpgrades(upgradeBytes)
+}
+
+// upgradeTimelineEntry is a precompile upgrade found in any of the upgrade
+// file, the lock file and the upgrades loaded by the nodes
+type upgradeTimelineEntry struct {
+	upgrade       params.PrecompileUpgrade
+	inUpgradeFile bool
+	inLockFile    bool
+	loadedBy      []string
+	status        string
+}
+
+func sameUpgrade(a params.PrecompileUpgrade, b params.PrecompileUpgrade) bool {
+	return a.Key() == b.Key() && a.Config.Equal(b.Config)
+}
+
+// buildUpgradeTimeline merges the upgrades of the upgrade file, the lock file
+// and the nodes, sorted by activation time. Upgrades due at [headTime] are
+// active, unless they are only in the upgrade file, in which case they were
+// missed
+func buildUpgradeTimeline(
+	fileUpgrades []params.PrecompileUpgrade,
+	lockUpgrades []params.PrecompileUpgrade,
+	nodeUpgrades map[string][]params.PrecompileUpgrade,
+	headTime uint64,
+) []*upgradeTimelineEntry {
+	timeline := []*upgradeTimelineEntry{}
+	getEntry := func(upgrade params.PrecompileUpgrade) *upgradeTimelineEntry {
+		for _, entry := range timeline {
+			if sameUpgrade(entry.upgrade, upgrade) {
+				return entry
+			}
+		}
+		entry := &upgradeTimelineEntry{upgrade: upgrade}
+		timeline = append(timeline, entry)
+		return entry
+	}
+	for _, upgrade := range fileUpgrades {
+		getEntry(upgrade).inUpgradeFile = true
+	}
+	for _, upgrade := range lockUpgrades {
+		getEntry(upgrade).inLockFile = true
+	}
+	for _, node := range sortedNodes(nodeUpgrades) {
+		for _, upgrade := range nodeUpgrades[node] {
+			entry := getEntry(upgrade)
+			entry.loadedBy = append(entry.loadedBy, node)
+		}
+	}
+	for _, entry := range timeline {
+		switch {
+		case *entry.upgrade.Timestamp() > headTime:
+			entry.status = upgradeStatusScheduled
+		case entry.inLockFile || len(entry.loadedBy) > 0:
+			entry.status = upgradeStatusActive
+		default:
+			entry.status = upgradeStatusMissed
+		}
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return *timeline[i].upgrade.Timestamp() < *timeline[j].upgrade.Timestamp()
+	})
+	return timeline
+}
+
+// upgradeDrift describes the differences between the upgrade files and the
+// upgrades loaded by each node in [nodeUpgrades]
+func upgradeDrift(timeline []*upgradeTimelineEntry, nodeUpgrades map[string][]params.PrecompileUpgrade) []string {
+	drift := []string{}
+	nodes := sortedNodes(nodeUpgrades)
+	for _, entry := range timeline {
+		description := fmt.Sprintf("%s %s upgrade at %s", entry.upgrade.Key(), upgradeAction(entry.upgrade), formatTimestamp(*entry.upgrade.Timestamp()))
+		if entry.status == upgradeStatusActive {
+			for _, node := range nodes {
+				if !slices.Contains(entry.loadedBy, node) {
+					drift = append(drift, fmt.Sprintf("node %s is missing the %s, already active on-chain", node, description))
+				}
+			}
+		}
+		if entry.status == upgradeStatusMissed {
+			drift = append(drift, fmt.Sprintf("the %s in the upgrade file is in the past but was never applied", description))
+		}
+		if !entry.inUpgradeFile && len(entry.loadedBy) > 0 {
+			drift = append(drift, fmt.Sprintf("the %s loaded by node(s) %s is not in the upgrade file", description, strings.Join(entry.loadedBy, ", ")))
+		}
+	}
+	return drift
+}
+
+func sortedNodes(nodeUpgrades map[string][]params.PrecompileUpgrade) []string {
+	nodes := []string{}
+	for node := range nodeUpgrades {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+func upgradeAction(upgrade params.PrecompileUpgrade) string {
+	if upgrade.IsDisabled() {
+		return "disable"
+	}
+	return "enable"
+}
+
+func formatTimestamp(ts uint64) string {
+	return time.Unix(int64(ts), 0).Local().Format(constants.TimeParseLayout)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func printUpgradeTimeline(timeline []*upgradeTimelineEntry, numNodes int) {
+	if len(timeline) == 0 {
+		ux.Logger.PrintToUser("No precompile upgrades found")
+		return
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Precompile", "Action", "Activation", "Status", "Upgrade File", "Lock File", "Loaded By Nodes"})
+	table.SetRowLine(true)
+	for _, entry := range timeline {
+		table.Append([]string{
+			entry.upgrade.Key(),
+			upgradeAction(entry.upgrade),
+			formatTimestamp(*entry.upgrade.Timestamp()),
+			entry.status,
+			yesNo(entry.inUpgradeFile),
+			yesNo(entry.inLockFile),
+			fmt.Sprintf("%d/%d", len(entry.loadedBy), numNodes),
+		})
+	}
+	table.Render()
+}
+
+// readAllowListRole reads the role of [address] in the allow list of the
+// precompile at [precompileAddress] with an eth_call
+func readAllowListRole(chain chainCaller, precompileAddress common.Address, address common.Address) (allowlist.Role, error) {
+	input, err := allowlist.PackReadAllowList(address)
+	if err != nil {
+		return allowlist.NoRole, err
+	}
+	var output hexutil.Bytes
+	call := map[string]interface{}{
+		"to":   precompileAddress,
+		"data": hexutil.Bytes(input),
+	}
+	if err := chain(&output, "eth_call", call, "latest"); err != nil {
+		return allowlist.NoRole, err
+	}
+	return allowlist.Role(common.BytesToHash(output)), nil
+}
+
+func printActivePrecompiles(chain chainCaller, active params.Precompiles) {
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Precompile configs in effect:")
+	if len(active) == 0 {
+		ux.Logger.PrintToUser("No precompiles are active")
+		return
+	}
+	keys := []string{}
+	for key := range active {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Precompile", "Config", "On-chain Roles"})
+	table.SetRowLine(true)
+	table.SetAutoWrapText(false)
+	for _, key := range keys {
+		config := active[key]
+		configBytes, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			configBytes = []byte(err.Error())
+		}
+		roles := []string{}
+		if allowList := vm.GetAllowListConfig(config); allowList != nil {
+			module, ok := modules.GetPrecompileModule(key)
+			if ok {
+				addresses := append(append(append([]common.Address{}, allowList.AdminAddresses...), allowList.ManagerAddresses...), allowList.EnabledAddresses...)
+				for _, address := range addresses {
+					role, err := readAllowListRole(chain, module.Address, address)
+					if err != nil {
+						roles = append(roles, fmt.Sprintf("%s: %s", address.Hex(), err))
+						continue
+					}
+					roles = append(roles, fmt.Sprintf("%s: %s", address.Hex(), role))
+				}
+			}
+		}
+		table.Append([]string{key, string(configBytes), strings.Join(roles, "\n")})
+	}
+	table.Render()
+}
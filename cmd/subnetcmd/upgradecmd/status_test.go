@@ -0,0 +1,75 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package upgradecmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildUpgradeTimeline(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	spec, err := loadUpgradeSpec(writeSpec(t, `
+upgrades:
+  - precompile: contractNativeMinterConfig
+    in: 3d
+    adminAddresses: [`+testAdmin+`]
+  - precompile: txAllowListConfig
+    action: disable
+    in: 1d
+  - precompile: feeManagerConfig
+    in: 2d
+    adminAddresses: [`+testAdmin+`]
+`))
+	require.NoError(err)
+	upgrades, err := buildSpecUpgrades(spec, testChainConfig(), nil, now)
+	require.NoError(err)
+	require.Len(upgrades, 3)
+	// sorted by time: allow list disable, fee manager, native minter
+	disableAllowList, enableFeeManager, enableMinter := upgrades[0], upgrades[1], upgrades[2]
+	require.Equal(txallowlist.ConfigKey, disableAllowList.Key())
+	require.Equal(feemanager.ConfigKey, enableFeeManager.Key())
+	require.Equal(nativeminter.ConfigKey, enableMinter.Key())
+
+	// the chain is past the fee manager activation. node2 misses the allow
+	// list disable, and the fee manager upgrade was never applied
+	lockUpgrades := upgrades[:1]
+	nodeUpgrades := map[string][]params.PrecompileUpgrade{
+		"node1": {disableAllowList, enableMinter},
+		"node2": {enableMinter},
+	}
+	headTime := uint64(now.Add(60 * time.Hour).Unix())
+	timeline := buildUpgradeTimeline(upgrades, lockUpgrades, nodeUpgrades, headTime)
+	require.Len(timeline, 3)
+	require.True(sameUpgrade(disableAllowList, timeline[0].upgrade))
+	require.Equal(upgradeStatusActive, timeline[0].status)
+	require.True(timeline[0].inUpgradeFile)
+	require.True(timeline[0].inLockFile)
+	require.Equal([]string{"node1"}, timeline[0].loadedBy)
+	require.Equal(upgradeStatusMissed, timeline[1].status)
+	require.Empty(timeline[1].loadedBy)
+	require.Equal(upgradeStatusScheduled, timeline[2].status)
+	require.Equal([]string{"node1", "node2"}, timeline[2].loadedBy)
+
+	drift := upgradeDrift(timeline, nodeUpgrades)
+	require.Len(drift, 2)
+	require.Contains(drift[0], "node node2 is missing the txAllowListConfig disable upgrade")
+	require.Contains(drift[1], "feeManagerConfig enable upgrade")
+	require.Contains(drift[1], "never applied")
+
+	// upgrades loaded by the nodes and not in the upgrade file are drift too
+	nodeUpgrades = map[string][]params.PrecompileUpgrade{
+		"node1": {disableAllowList, enableMinter},
+	}
+	timeline = buildUpgradeTimeline(upgrades[:1], lockUpgrades, nodeUpgrades, headTime)
+	drift = upgradeDrift(timeline, nodeUpgrades)
+	require.Len(drift, 1)
+	require.Contains(drift[0], "loaded by node(s) node1 is not in the upgrade file")
+}
@@ -38,5 +38,9 @@ updating your developmental and deployed Subnets.`,
 	cmd.AddCommand(newUpgradePrintCmd())
 	// subnet upgrade apply
 	cmd.AddCommand(newUpgradeApplyCmd())
+	// subnet upgrade rehearse
+	cmd.AddCommand(newUpgradeRehearseCmd())
+	// subnet upgrade status
+	cmd.AddCommand(newUpgradeStatusCmd())
 	return cmd
 }
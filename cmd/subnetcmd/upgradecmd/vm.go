@@ -126,7 +126,8 @@ func upgradeVM(_ *cobra.Command, args []string) error {
 	}
 
 	vmType := sc.VM
-	if vmType == models.SubnetEvm {
+	if vmType != models.CustomVM {
+		// Subnet-EVM and VM templates are versioned
 		return selectUpdateOption(vmType, sc, networkToUpgrade)
 	}
 
@@ -225,10 +226,23 @@ func updateToLatestVersion(vmType models.VMType, sc models.Sidecar, networkToUpg
 	currentVersion := sc.VMVersion
 
 	// check latest version
-	latestVersion, err := app.Downloader.GetLatestReleaseVersion(binutils.GetGithubLatestReleaseURL(
-		constants.AvaLabsOrg,
-		vmType.RepoName(),
-	))
+	var (
+		latestVersion string
+		err           error
+	)
+	if vmType == models.SubnetEvm {
+		latestVersion, err = app.Downloader.GetLatestReleaseVersion(binutils.GetGithubLatestReleaseURL(
+			constants.AvaLabsOrg,
+			vmType.RepoName(),
+		))
+	} else {
+		var vmTemplate vm.VMTemplate
+		vmTemplate, err = vm.GetVMTemplateForSidecar(app, &sc)
+		if err != nil {
+			return err
+		}
+		latestVersion, err = vm.DefaultVMTemplateVersion(app, vmTemplate)
+	}
 	if err != nil {
 		return err
 	}
@@ -249,7 +263,11 @@ func updateToSpecificVersion(sc models.Sidecar, networkToUpgrade string) error {
 	// Get version to update to
 	var err error
 	if targetVersion == "" {
-		targetVersion, err = app.Prompt.CaptureVersion("Enter version")
+		if sc.VMTemplate != nil && sc.VMTemplate.Binary.Build != nil {
+			targetVersion, err = app.Prompt.CaptureString("Enter branch or commit")
+		} else {
+			targetVersion, err = app.Prompt.CaptureVersion("Enter version")
+		}
 		if err != nil {
 			return err
 		}
@@ -293,6 +311,9 @@ func updateToCustomBin(sc models.Sidecar, networkToUpgrade, binaryPath string, u
 	}
 
 	sc.VM = models.CustomVM
+	sc.VMTemplate = nil
+	// the binary no longer comes from the recorded source build
+	sc.CustomVMBuild = nil
 	if updateVMBinaryProtocolVersion {
 		sc.RPCVersion, err = vm.GetVMBinaryProtocolVersion(binaryPath)
 		if err != nil {
@@ -305,6 +326,17 @@ func updateToCustomBin(sc models.Sidecar, networkToUpgrade, binaryPath string, u
 }
 
 func updateFutureVM(sc models.Sidecar, targetVersion string) error {
+	if sc.VM != models.SubnetEvm && sc.VM != models.CustomVM {
+		// install or build the new version now, to fail early and to get
+		// its RPC version
+		vmTemplate, err := vm.GetVMTemplateForSidecar(app, &sc)
+		if err != nil {
+			return err
+		}
+		if err := vm.SetupVMTemplateSidecar(app, &sc, vmTemplate, targetVersion); err != nil {
+			return err
+		}
+	}
 	// to switch to new version, just need to update sidecar
 	sc.VMVersion = targetVersion
 	if err := app.UpdateSidecar(&sc); err != nil {
@@ -332,7 +364,14 @@ func updateExistingLocalVM(sc models.Sidecar, targetVersion string) error {
 		vmBin = binutils.SetupCustomBin(app, sc.Name)
 
 	default:
-		return errors.New("unknown VM type " + string(sc.VM))
+		vmTemplate, err := vm.GetVMTemplateForSidecar(app, &sc)
+		if err != nil {
+			return fmt.Errorf("unknown VM type %s: %w", sc.VM, err)
+		}
+		vmBin, err = vmTemplate.SetupBinary(app, &sc, targetVersion)
+		if err != nil {
+			return err
+		}
 	}
 
 	rpcVersion, err := vm.GetVMBinaryProtocolVersion(vmBin)
@@ -0,0 +1,111 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const skipGenesisValidationFlag = "skip-genesis-validation"
+
+var (
+	lintGenesisFile     string
+	lintTeleporterReady bool
+)
+
+// avalanche subnet validate-genesis
+func newValidateGenesisCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate-genesis [subnetName]",
+		Short: "Check a Subnet-EVM genesis for common mistakes",
+		Long: `The subnet validate-genesis command checks the genesis of the given subnet, or the
+genesis file given with --genesis, for problems that would otherwise only show up
+at deploy time or once the chain runs: chain IDs colliding with well known networks,
+allow list admins without balance, incoherent fee configs, gas limits out of sane
+bounds or a missing warp config for teleporter ready subnets.
+
+Issues are reported with a severity. The command fails if any is an error. The same
+checks run automatically before subnet deploy.`,
+		RunE:         validateGenesis,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&lintGenesisFile, "genesis", "", "file path of the genesis to validate")
+	cmd.Flags().BoolVar(&lintTeleporterReady, "teleporter", false, "expect the genesis to support teleporter (for --genesis)")
+	return cmd
+}
+
+func validateGenesis(_ *cobra.Command, args []string) error {
+	if (len(args) == 0) == (lintGenesisFile == "") {
+		return errors.New("provide either a subnet name or a --genesis file")
+	}
+	var (
+		genesisBytes    []byte
+		teleporterReady bool
+		err             error
+	)
+	if lintGenesisFile != "" {
+		genesisBytes, err = os.ReadFile(lintGenesisFile)
+		if err != nil {
+			return err
+		}
+		teleporterReady = lintTeleporterReady
+	} else {
+		chains, err := ValidateSubnetNameAndGetChains(args)
+		if err != nil {
+			return err
+		}
+		sc, err := app.LoadSidecar(chains[0])
+		if err != nil {
+			return err
+		}
+		genesisBytes, err = app.LoadRawGenesis(chains[0])
+		if err != nil {
+			return err
+		}
+		teleporterReady = sc.TeleporterReady
+	}
+	result := vm.LintGenesis(genesisBytes, teleporterReady)
+	if len(result) == 0 {
+		ux.Logger.GreenCheckmarkToUser("No issues found in genesis")
+		return nil
+	}
+	printLintResult(result)
+	if result.HasErrors() {
+		return fmt.Errorf("genesis has %d error(s)", len(result.Errors()))
+	}
+	return nil
+}
+
+// lintGenesisBeforeDeploy prints any genesis issue and fails on errors,
+// so that invalid genesis are caught before paying for chain creation
+func lintGenesisBeforeDeploy(genesisBytes []byte, teleporterReady bool) error {
+	result := vm.LintGenesis(genesisBytes, teleporterReady)
+	if len(result) == 0 {
+		return nil
+	}
+	ux.Logger.PrintToUser("Genesis validation found the following issues:")
+	printLintResult(result)
+	if result.HasErrors() {
+		return fmt.Errorf("genesis has %d error(s). Fix them or use --%s to deploy anyway", len(result.Errors()), skipGenesisValidationFlag)
+	}
+	return nil
+}
+
+func printLintResult(result vm.LintResult) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Severity", "Rule", "Issue"})
+	table.SetRowLine(true)
+	table.SetAutoWrapText(true)
+	for _, issue := range result {
+		table.Append([]string{issue.Severity.String(), issue.Rule, issue.Message})
+	}
+	table.Render()
+}
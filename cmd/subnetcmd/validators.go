@@ -5,6 +5,7 @@ package subnetcmd
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"time"
@@ -12,13 +13,22 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
 )
 
-var validatorsSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Fuji, networkoptions.Mainnet, networkoptions.Cluster, networkoptions.Devnet}
+var (
+	validatorsSupportedNetworkOptions = []networkoptions.NetworkOption{networkoptions.Local, networkoptions.Fuji, networkoptions.Mainnet, networkoptions.Cluster, networkoptions.Devnet}
+
+	expiringWithin string
+)
 
 // avalanche subnet validators
 func newValidatorsCmd() *cobra.Command {
@@ -26,12 +36,21 @@ func newValidatorsCmd() *cobra.Command {
 		Use:   "validators [subnetName]",
 		Short: "List a subnet's validators",
 		Long: `The subnet validators command lists the validators of a subnet and provides
-severarl statistics about them.`,
+severarl statistics about them.
+
+Use --expiring-within to only list the validators whose staking period ends within the given
+duration, e.g. 14d, together with the end of their primary network validation. A subnet validation
+can't outlast the primary network one, so the validators whose primary network validation also ends
+within that duration can't be renewed until it is extended. The validators added with the CLI whose
+period has already ended, and that silently dropped off the subnet, are listed as well. Use
+validators renew to re-add the renewable ones with a new staking period.`,
 		RunE:         printValidators,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
 	}
 	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, false, validatorsSupportedNetworkOptions)
+	cmd.Flags().StringVar(&expiringWithin, "expiring-within", "", "only list the validators whose staking period ends within this duration, e.g. 14d")
+	cmd.AddCommand(newValidatorsRenewCmd())
 	return cmd
 }
 
@@ -62,6 +81,23 @@ func printValidators(_ *cobra.Command, args []string) error {
 
 	subnetID := deployInfo.SubnetID
 
+	if expiringWithin != "" {
+		window, err := utils.ParseDuration(expiringWithin)
+		if err != nil {
+			return fmt.Errorf("invalid --expiring-within: %w", err)
+		}
+		expiring, err := getExpiringValidators(network, subnetName, subnetID, window)
+		if err != nil {
+			return err
+		}
+		if len(expiring) == 0 {
+			ux.Logger.PrintToUser("No validator of %s ends its staking period within %s", subnetName, ux.FormatDuration(window))
+			return nil
+		}
+		printExpiringValidators(expiring)
+		return nil
+	}
+
 	if network.Kind == models.Local {
 		return printLocalValidators(subnetID)
 	} else {
@@ -122,3 +158,102 @@ func printValidatorsFromList(validators []platformvm.ClientPermissionlessValidat
 func formatUnixTime(unixTime uint64) string {
 	return time.Unix(int64(unixTime), 0).Format(time.RFC3339)
 }
+
+// getExpiringValidators returns the validators of [subnetID] whose staking
+// period ends within [window], and the validators recorded in the sidecar
+// of [subnetName] whose period has already ended
+func getExpiringValidators(network models.Network, subnetName string, subnetID ids.ID, window time.Duration) ([]subnet.ExpiringValidator, error) {
+	pClient := platformvm.NewClient(network.Endpoint)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	subnetValidators, err := pClient.GetCurrentValidators(ctx, subnetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	nodeIDs := []ids.NodeID{}
+	for _, validator := range subnetValidators {
+		nodeIDs = append(nodeIDs, validator.NodeID)
+	}
+	pastValidators, err := getPastValidators(network, subnetName, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, validator := range pastValidators {
+		nodeIDs = append(nodeIDs, validator.NodeID)
+	}
+	primaryValidators := []platformvm.ClientPermissionlessValidator{}
+	if len(nodeIDs) > 0 {
+		primaryValidators, err = pClient.GetCurrentValidators(ctx, avagoconstants.PrimaryNetworkID, nodeIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return subnet.ExpiringValidators(subnetValidators, pastValidators, primaryValidators, time.Now(), window), nil
+}
+
+// getPastValidators reads from the P-Chain the last add validator tx of the
+// validators recorded in the sidecar of the subnet of [subnetName], except
+// for the [current] ones
+func getPastValidators(network models.Network, subnetName string, current []ids.NodeID) ([]platformvm.ClientPermissionlessValidator, error) {
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	if sc.IsAddedChain() {
+		sc, err = app.LoadSidecar(sc.GetSubnetName())
+		if err != nil {
+			return nil, err
+		}
+	}
+	pastValidators := []platformvm.ClientPermissionlessValidator{}
+	for nodeIDStr, txID := range sc.Networks[network.Name()].ValidatorTxs {
+		nodeID, err := ids.NodeIDFromString(nodeIDStr)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(current, nodeID) {
+			continue
+		}
+		tx, err := txutils.GetAddSubnetValidatorTx(network, txID)
+		if err != nil {
+			return nil, err
+		}
+		pastValidators = append(pastValidators, platformvm.ClientPermissionlessValidator{
+			ClientStaker: platformvm.ClientStaker{
+				TxID:      txID,
+				NodeID:    nodeID,
+				StartTime: uint64(tx.StartTime().Unix()),
+				EndTime:   uint64(tx.EndTime().Unix()),
+				Weight:    tx.Weight(),
+			},
+		})
+	}
+	return pastValidators, nil
+}
+
+func printExpiringValidators(expiring []subnet.ExpiringValidator) {
+	header := []string{"NodeID", "Weight", "End Time", "Primary Network End Time", "Status"}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetRowLine(true)
+	for _, validator := range expiring {
+		endTime := validator.EndTime.Format(time.RFC3339)
+		if validator.Ended || validator.EndTime.Before(time.Now()) {
+			endTime += " (ended)"
+		} else {
+			endTime += " (in " + ux.FormatDuration(time.Until(validator.EndTime).Round(time.Minute)) + ")"
+		}
+		primaryEndTime := "-"
+		if !validator.PrimaryEndTime.IsZero() {
+			primaryEndTime = validator.PrimaryEndTime.Format(time.RFC3339)
+		}
+		table.Append([]string{
+			validator.NodeID.String(),
+			strconv.FormatUint(validator.Weight, 10),
+			endTime,
+			primaryEndTime,
+			validator.Status,
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,295 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/networkoptions"
+	"github.com/ava-labs/avalanche-cli/pkg/sdk"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	defaultRenewalWindow = "14d"
+	// renewalPollInterval is how often the P-Chain is checked for the end of
+	// a validation that is going to be renewed
+	renewalPollInterval = 10 * time.Second
+	// renewalRemovalTimeout is how long the P-Chain is given to remove a
+	// validator after its end time, before giving up on renewing it
+	renewalRemovalTimeout = 5 * time.Minute
+)
+
+var (
+	renewNodeIDs  []string
+	renewalWait   time.Duration
+	renewalWindow string
+
+	errStillValidating = errors.New("validator is still validating")
+)
+
+type validatorRenewal struct {
+	nodeID  ids.NodeID
+	endTime time.Time
+	result  string
+}
+
+// avalanche subnet validators renew
+func newValidatorsRenewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "renew [subnetName]",
+		Short: "Re-add the expiring validators of a subnet with a new staking period",
+		Long: `The subnet validators renew command re-adds, in one batch, the subnet validators whose
+staking period ends within --expiring-within (14d by default), using the same transaction as
+addValidator. Each validator keeps its weight, and is re-added until its primary network validation
+ends, or for --staking-period.
+
+The validators added with the CLI whose period has already ended are re-added right away. The P-Chain
+doesn't accept a new validation for a node that still validates the subnet, so the validators that
+are still validating are skipped, unless their period ends within --wait, in which case the command
+waits for it to end and re-adds them. Validators whose primary network validation also ends within
+the window are never renewed, as a subnet validation can't outlast the primary network one.
+
+Use --nodeIDs to only renew some of the expiring validators.`,
+		SilenceUsage: true,
+		RunE:         renewValidators,
+		Args:         cobra.ExactArgs(1),
+	}
+	networkoptions.AddNetworkFlagsToCmd(cmd, &globalNetworkFlags, true, addValidatorSupportedNetworkOptions)
+	cmd.Flags().StringVar(&renewalWindow, "expiring-within", defaultRenewalWindow, "renew the validators whose staking period ends within this duration")
+	cmd.Flags().StringSliceVar(&renewNodeIDs, "nodeIDs", nil, "only renew the given expiring validators")
+	cmd.Flags().DurationVar(&renewalWait, "wait", 0, "wait up to this long for the validators still validating to end their period, and re-add them")
+	cmd.Flags().DurationVar(&duration, "staking-period", 0, "how long the renewed validators will be staking (defaults to until their primary network validation ends)")
+	cmd.Flags().StringVarP(&keyName, "key", "k", "", "select the key to use [fuji/devnet only]")
+	cmd.Flags().StringSliceVar(&subnetAuthKeys, "subnet-auth-keys", nil, "control keys that will be used to authenticate the add validator txs")
+	cmd.Flags().BoolVarP(&useEwoq, "ewoq", "e", false, "use ewoq key [fuji/devnet only]")
+	cmd.Flags().BoolVarP(&useLedger, "ledger", "g", false, "use ledger instead of key (always true on mainnet, defaults to false on fuji/devnet)")
+	cmd.Flags().StringSliceVar(&ledgerAddresses, "ledger-addrs", []string{}, "use the given ledger addresses")
+	return cmd
+}
+
+func renewValidators(_ *cobra.Command, args []string) error {
+	subnetName := args[0]
+	window, err := utils.ParseDuration(renewalWindow)
+	if err != nil {
+		return fmt.Errorf("invalid --expiring-within: %w", err)
+	}
+	requestedNodeIDs := []ids.NodeID{}
+	for _, nodeIDStr := range renewNodeIDs {
+		nodeID, err := ids.NodeIDFromString(nodeIDStr)
+		if err != nil {
+			return err
+		}
+		requestedNodeIDs = append(requestedNodeIDs, nodeID)
+	}
+	network, err := networkoptions.GetNetworkFromCmdLineFlags(
+		app,
+		globalNetworkFlags,
+		true,
+		addValidatorSupportedNetworkOptions,
+		subnetName,
+	)
+	if err != nil {
+		return err
+	}
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	subnetID := sc.Networks[network.Name()].SubnetID
+	if subnetID == ids.Empty {
+		return errNoSubnetID
+	}
+
+	expiring, err := getExpiringValidators(network, subnetName, subnetID, window)
+	if err != nil {
+		return err
+	}
+	if len(requestedNodeIDs) > 0 {
+		selected := []subnet.ExpiringValidator{}
+		for _, validator := range expiring {
+			if slices.Contains(requestedNodeIDs, validator.NodeID) {
+				selected = append(selected, validator)
+			}
+		}
+		for _, nodeID := range requestedNodeIDs {
+			if !slices.ContainsFunc(selected, func(v subnet.ExpiringValidator) bool { return v.NodeID == nodeID }) {
+				ux.Logger.PrintToUser("Node %s is not a subnet validator ending its period within %s", nodeID, ux.FormatDuration(window))
+			}
+		}
+		expiring = selected
+	}
+	if len(expiring) == 0 {
+		ux.Logger.PrintToUser("No validator of %s to renew", subnetName)
+		return nil
+	}
+	printExpiringValidators(expiring)
+
+	renewable := []subnet.ExpiringValidator{}
+	for _, validator := range expiring {
+		if validator.IsRenewable() {
+			renewable = append(renewable, validator)
+		}
+	}
+	if len(renewable) == 0 {
+		ux.Logger.PrintToUser("None of the expiring validators can be renewed before its primary network validation is extended")
+		return nil
+	}
+
+	fee := network.GenesisParams().AddSubnetValidatorFee * uint64(len(renewable))
+	kc, err := keychain.GetKeychainFromCmdLineFlags(
+		app,
+		constants.PayTxsFeesMsg,
+		network,
+		keyName,
+		useEwoq,
+		useLedger,
+		ledgerAddresses,
+		fee,
+	)
+	if err != nil {
+		return err
+	}
+	network.HandlePublicNetworkSimulation()
+	if err := UpdateKeychainWithSubnetControlKeys(kc, network, subnetName); err != nil {
+		return err
+	}
+
+	// validators are sorted by end time, so waiting for each of them in turn
+	// doesn't wait beyond --wait
+	waitDeadline := time.Now().Add(renewalWait)
+	renewals := []validatorRenewal{}
+	failed := 0
+	for _, validator := range renewable {
+		result, err := renewValidator(network, kc, subnetName, subnetID, validator, waitDeadline)
+		if err != nil {
+			failed++
+			result = "failed: " + err.Error()
+		}
+		renewals = append(renewals, validatorRenewal{
+			nodeID:  validator.NodeID,
+			endTime: validator.EndTime,
+			result:  result,
+		})
+	}
+	ux.Logger.PrintToUser("")
+	printValidatorRenewals(renewals)
+	if failed > 0 {
+		return fmt.Errorf("%d of the %d renewable validators failed to be renewed", failed, len(renewable))
+	}
+	return nil
+}
+
+// renewValidator re-adds [validator] to [subnetID] once its current period
+// ends, and describes the outcome
+func renewValidator(
+	network models.Network,
+	kc *keychain.Keychain,
+	subnetName string,
+	subnetID ids.ID,
+	validator subnet.ExpiringValidator,
+	waitDeadline time.Time,
+) (string, error) {
+	if err := waitForValidationEnd(network, subnetID, validator, waitDeadline); err != nil {
+		if errors.Is(err, errStillValidating) {
+			return fmt.Sprintf("skipped: validates until %s, renew it once it ends", validator.EndTime.Format(constants.TimeParseLayout)), nil
+		}
+		return "", err
+	}
+	leadTime := constants.StakingStartLeadTime
+	if network.Kind == models.Devnet {
+		leadTime = constants.DevnetStakingStartLeadTime
+	}
+	start := time.Now().Add(leadTime)
+	selectedDuration := duration
+	if selectedDuration == 0 {
+		selectedDuration = validator.PrimaryEndTime.Sub(start)
+	}
+	ux.Logger.PrintToUser("Re-adding validator %s with weight %d until %s", validator.NodeID, validator.Weight, start.Add(selectedDuration).Format(constants.TimeParseLayout))
+	result, err := sdk.New(app).AddValidator(sdk.AddValidatorOptions{
+		ValidatorOptions: sdk.ValidatorOptions{
+			SubnetName:     subnetName,
+			Network:        network,
+			Keychain:       kc,
+			NodeID:         validator.NodeID,
+			SubnetAuthKeys: subnetAuthKeys,
+		},
+		Weight:    validator.Weight,
+		StartTime: start,
+		Duration:  selectedDuration,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !result.FullySigned {
+		if err := SaveNotFullySignedTx(
+			"Add Validator",
+			result.Tx,
+			subnetName,
+			result.SubnetAuthKeys,
+			result.RemainingSubnetAuthKeys,
+			"",
+			false,
+		); err != nil {
+			return "", err
+		}
+		return "partially signed add validator tx saved", nil
+	}
+	return "renewed until " + start.Add(selectedDuration).Format(constants.TimeParseLayout), nil
+}
+
+// waitForValidationEnd returns once [validator] is no longer a validator of
+// [subnetID]. It waits for validators whose period ends by [waitDeadline]
+func waitForValidationEnd(network models.Network, subnetID ids.ID, validator subnet.ExpiringValidator, waitDeadline time.Time) error {
+	if validator.Ended {
+		return nil
+	}
+	if validator.EndTime.After(waitDeadline) {
+		return errStillValidating
+	}
+	if wait := time.Until(validator.EndTime); wait > 0 {
+		ux.Logger.PrintToUser("Waiting %s for validator %s to end its period...", ux.FormatDuration(wait.Round(time.Second)), validator.NodeID)
+		time.Sleep(wait)
+	}
+	// the P-Chain removes the validator on the first block after its end time
+	removalDeadline := time.Now().Add(renewalRemovalTimeout)
+	for {
+		isValidator, err := subnet.IsSubnetValidator(subnetID, validator.NodeID, network)
+		if err != nil {
+			return err
+		}
+		if !isValidator {
+			return nil
+		}
+		if time.Now().After(removalDeadline) {
+			return errStillValidating
+		}
+		time.Sleep(renewalPollInterval)
+	}
+}
+
+func printValidatorRenewals(renewals []validatorRenewal) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"NodeID", "Previous End Time", "Result"})
+	table.SetRowLine(true)
+	for _, renewal := range renewals {
+		table.Append([]string{
+			renewal.nodeID.String(),
+			renewal.endTime.Format(constants.TimeParseLayout),
+			renewal.result,
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+// avalanche subnet vm-template
+func newVMTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vm-template",
+		Short: "Manage the VM templates available to subnet create",
+		Long: `The subnet vm-template command suite manages VM templates, which give VMs
+other than Subnet-EVM first class create, deploy and upgrade support. Use
+subnet create --vm-template <name> to create a subnet running one of them.
+
+Besides the templates compiled into the CLI, templates are loaded from the
+manifests (*.yaml files) of the vm-templates dir of the CLI base dir, and of
+every dir registered with subnet vm-template register. A manifest holds:
+  name, description
+  binary.github: org, repo, asset (release tar.gz name as a text/template
+    using {{.Version}}, {{.VersionNumber}}, {{.OS}} and {{.Arch}}) and
+    binary (path of the VM binary in the archive)
+  or binary.build: repo, branch and script, to build the VM from source,
+    and optionally image, a container image to run the script in
+  compatibilityURL: JSON file with a rpcChainVMProtocolVersion map, as the
+    Subnet-EVM compatibility.json. The binary is queried when missing
+  chainConfig: default chain config of new subnets
+  genesis.params and genesis.template: the genesis, as a text/template
+    rendered with the param values
+
+The manifest is stored in the sidecar of the subnets created from it, so they
+can be deployed on hosts that don't have it, as cluster nodes.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newVMTemplateListCmd())
+	cmd.AddCommand(newVMTemplateDescribeCmd())
+	cmd.AddCommand(newVMTemplateRegisterCmd())
+	cmd.AddCommand(newVMTemplateUnregisterCmd())
+	return cmd
+}
+
+// avalanche subnet vm-template list
+func newVMTemplateListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List the available VM templates",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			templates, err := vm.ListVMTemplates(app)
+			if err != nil {
+				return err
+			}
+			if len(templates) == 0 {
+				ux.Logger.PrintToUser("No VM template available. Add manifests to %s or register a dir with subnet vm-template register", app.GetVMTemplatesDir())
+				return nil
+			}
+			table := tablewriter.NewWriter(ux.Logger.Writer)
+			table.SetHeader([]string{"Name", "Source", "Description"})
+			table.SetRowLine(true)
+			table.SetAutoWrapText(false)
+			for _, t := range templates {
+				table.Append([]string{t.Name(), vmTemplateSource(t), t.Description()})
+			}
+			table.Render()
+			return nil
+		},
+	}
+}
+
+// avalanche subnet vm-template describe
+func newVMTemplateDescribeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "describe [name]",
+		Short:        "Show the binary source and genesis params of a VM template",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			t, err := vm.GetVMTemplate(app, args[0])
+			if err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("%s (%s)", t.Name(), vmTemplateSource(t))
+			ux.Logger.PrintToUser(t.Description())
+			ux.Logger.PrintToUser("")
+			if mt, ok := t.(*vm.ManifestVMTemplate); ok {
+				if release := mt.Manifest.Binary.GitHub; release != nil {
+					ux.Logger.PrintToUser("Binary: %s from the releases of github.com/%s/%s", release.Binary, release.Org, release.Repo)
+				}
+				if build := mt.Manifest.Binary.Build; build != nil {
+					ux.Logger.PrintToUser("Binary: built from %s with %s", build.Repo, build.Script)
+				}
+				ux.Logger.PrintToUser("")
+			}
+			if len(t.GenesisParams()) > 0 {
+				table := tablewriter.NewWriter(ux.Logger.Writer)
+				table.SetHeader([]string{"Param", "Type", "Default", "Description"})
+				table.SetAutoWrapText(false)
+				for _, param := range t.GenesisParams() {
+					paramType := param.Type
+					if paramType == "" {
+						paramType = "string"
+					}
+					defaultValue := "(required)"
+					if param.Default != nil {
+						defaultValue = *param.Default
+					}
+					table.Append([]string{param.Name, paramType, defaultValue, param.Description})
+				}
+				table.Render()
+			}
+			return nil
+		},
+	}
+}
+
+// avalanche subnet vm-template register
+func newVMTemplateRegisterCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "register [dir]",
+		Short:        "Register a dir of VM template manifests",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			dir, err := filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+			if info, err := os.Stat(dir); err != nil {
+				return err
+			} else if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			dirs := app.Conf.GetConfigStringSliceValue(constants.ConfigVMTemplateDirsKey)
+			if slices.Contains(dirs, dir) {
+				ux.Logger.PrintToUser("%s is already registered", dir)
+				return nil
+			}
+			if err := app.Conf.SetConfigValue(constants.ConfigVMTemplateDirsKey, append(dirs, dir)); err != nil {
+				return err
+			}
+			// fail early on broken or clashing manifests
+			if _, err := vm.ListVMTemplates(app); err != nil {
+				if restoreErr := app.Conf.SetConfigValue(constants.ConfigVMTemplateDirsKey, dirs); restoreErr != nil {
+					return restoreErr
+				}
+				return err
+			}
+			ux.Logger.PrintToUser("Registered VM template dir %s", dir)
+			return nil
+		},
+	}
+}
+
+// avalanche subnet vm-template unregister
+func newVMTemplateUnregisterCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "unregister [dir]",
+		Short:        "Stop loading VM template manifests from a registered dir",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			dir, err := filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+			dirs := app.Conf.GetConfigStringSliceValue(constants.ConfigVMTemplateDirsKey)
+			idx := slices.Index(dirs, dir)
+			if idx == -1 {
+				return fmt.Errorf("%s is not a registered VM template dir", dir)
+			}
+			if err := app.Conf.SetConfigValue(constants.ConfigVMTemplateDirsKey, slices.Delete(dirs, idx, idx+1)); err != nil {
+				return err
+			}
+			ux.Logger.PrintToUser("Unregistered VM template dir %s", dir)
+			ux.Logger.PrintToUser("Subnets already created from its templates keep working, as their sidecar holds the manifest")
+			return nil
+		},
+	}
+}
+
+func vmTemplateSource(t vm.VMTemplate) string {
+	if mt, ok := t.(*vm.ManifestVMTemplate); ok {
+		return mt.Source
+	}
+	return vm.BuiltinTemplateSource
+}
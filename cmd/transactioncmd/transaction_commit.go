@@ -7,10 +7,12 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
 	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/sdk"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/txutils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	"github.com/spf13/cobra"
 )
@@ -103,5 +105,11 @@ func commitTx(_ *cobra.Command, args []string) error {
 	}
 	ux.Logger.PrintToUser("Transaction successful, transaction ID: %s", txID)
 
+	switch unsignedTx := tx.Unsigned.(type) {
+	case *txs.AddSubnetValidatorTx:
+		return sdk.New(app).RecordValidatorTx(subnetName, network, unsignedTx.NodeID(), txID)
+	case *txs.RemoveSubnetValidatorTx:
+		return sdk.New(app).RecordValidatorTx(subnetName, network, unsignedTx.NodeID, ids.Empty)
+	}
 	return nil
 }
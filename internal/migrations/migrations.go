@@ -3,9 +3,16 @@
 package migrations
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/backup"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 )
 
@@ -14,27 +21,115 @@ type migrationFunc func(*application.Avalanche, *migrationRunner) error
 type migrationRunner struct {
 	showMsg    bool
 	running    bool
+	dryRun     bool
 	migrations map[int]migrationFunc
+	// index of the migration being run
+	current int
+	// changes detected by the migrations, applied unless dryRun is set
+	changes []Change
 }
 
+// Change describes a modification done (or to be done) by a migration
+type Change struct {
+	Migration   int
+	Description string
+}
+
+// SkipAnnotation marks the commands that must not apply the pending
+// migrations before running, as they handle the migrations themselves
+const SkipAnnotation = "skip-migrations"
+
 var (
 	runMessage       = "The tool needs to apply some internal updates first..."
 	endMessage       = "Update process successfully completed"
 	failedEndMessage = "Some updates succeeded - others failed. Check output for hints"
 )
 
-// poor-man's migrations: there are no rollbacks (for now)
+// add new migrations here in rising index order
+func getMigrations() map[int]migrationFunc {
+	return map[int]migrationFunc{
+		// next one is 4
+		0: migrateTopLevelFiles,
+		1: migrateSubnetEVMNames,
+		2: migrateSchemaVersions,
+		3: migrateSidecarChains,
+	}
+}
+
+// RunMigrations applies all pending migrations. Before applying anything,
+// a snapshot of the CLI state is taken, and it is restored if a migration fails.
 func RunMigrations(app *application.Avalanche) error {
+	return runMigrations(app, getMigrations())
+}
+
+// RunNewMigrations applies the pending migrations, unless the CLI state was
+// already checked against all of them. Meant to be run on every invocation,
+// it only reads the migrations record when there is nothing new to check.
+func RunNewMigrations(app *application.Avalanche) error {
+	record, err := LoadRecord(app)
+	if err != nil {
+		return err
+	}
+	if record.Checked[stateDir(app)] >= len(getMigrations()) {
+		return nil
+	}
+	return RunMigrations(app)
+}
+
+func runMigrations(app *application.Avalanche, migrations map[int]migrationFunc) error {
+	dryRunner := &migrationRunner{
+		dryRun:     true,
+		migrations: migrations,
+	}
+	if err := dryRunner.run(app); err != nil {
+		return err
+	}
+	if len(dryRunner.changes) == 0 {
+		return recordApplied(app, len(migrations), nil)
+	}
+	snapshotPath, err := takeSnapshot(app)
+	if err != nil {
+		return fmt.Errorf("failed taking a snapshot before migrating: %w", err)
+	}
 	runner := &migrationRunner{
-		showMsg: true,
-		migrations: map[int]migrationFunc{
-			// add new migrations here in rising index order
-			// next one is 2
-			0: migrateTopLevelFiles,
-			1: migrateSubnetEVMNames,
-		},
+		showMsg:    true,
+		migrations: migrations,
+	}
+	if err := runner.run(app); err != nil {
+		// files created or moved by the migrations are not in the snapshot,
+		// so they are pruned to get back the state as it was
+		if _, restoreErr := backup.Restore(snapshotPath, app.GetBaseDir(), backup.RestoreOptions{
+			Force:      true,
+			Prune:      true,
+			ProjectDir: app.GetProjectDir(),
+		}); restoreErr != nil {
+			return fmt.Errorf("%w. Rollback from snapshot %s also failed: %w", err, snapshotPath, restoreErr)
+		}
+		ux.Logger.PrintToUser("State was rolled back to snapshot %s", snapshotPath)
+		return err
+	}
+	return recordApplied(app, len(migrations), runner.changes)
+}
+
+// stateDir identifies the CLI state the migrations run on: the project dir
+// when one is in use, as it holds the subnets, or the base dir
+func stateDir(app *application.Avalanche) string {
+	if projectDir := app.GetProjectDir(); projectDir != "" {
+		return projectDir
 	}
-	return runner.run(app)
+	return app.GetBaseDir()
+}
+
+// PendingMigrations returns the changes that RunMigrations would apply, without applying them
+func PendingMigrations(app *application.Avalanche) ([]Change, error) {
+	runner := &migrationRunner{
+		dryRun:     true,
+		migrations: getMigrations(),
+	}
+	if err := runner.run(app); err != nil {
+		return nil, err
+	}
+	return runner.changes, nil
 }
 
 func (m *migrationRunner) run(app *application.Avalanche) error {
@@ -42,6 +137,7 @@ func (m *migrationRunner) run(app *application.Avalanche) error {
 	// with just an array it could easily happen that someone
 	// prepends a new migration at the front instead of the bottom
 	for i := 0; i < len(m.migrations); i++ {
+		m.current = i
 		err := m.migrations[i](app, m)
 		if err != nil {
 			if m.running {
@@ -66,3 +162,95 @@ func (m *migrationRunner) printMigrationMessage() {
 	m.showMsg = false
 	m.running = true
 }
+
+// apply registers a change the current migration needs to do.
+// The migration must only modify state if it returns true,
+// otherwise the runner is in dry-run mode.
+func (m *migrationRunner) apply(description string) bool {
+	m.changes = append(m.changes, Change{
+		Migration:   m.current,
+		Description: description,
+	})
+	if m.dryRun {
+		return false
+	}
+	m.printMigrationMessage()
+	return true
+}
+
+// AppliedMigration is an entry of the migrations record
+type AppliedMigration struct {
+	Migration   int
+	Description string
+	AppliedAt   time.Time
+}
+
+// MigrationsRecord keeps track of the migrations applied on the CLI state
+type MigrationsRecord struct {
+	Applied []AppliedMigration
+	// number of migrations each state dir was checked against. A state dir
+	// is only checked again once new migrations are added
+	Checked map[string]int `json:",omitempty"`
+}
+
+// LoadRecord returns the migrations applied so far
+func LoadRecord(app *application.Avalanche) (MigrationsRecord, error) {
+	record := MigrationsRecord{}
+	bs, err := os.ReadFile(app.GetMigrationsRecordPath())
+	if os.IsNotExist(err) {
+		return record, nil
+	}
+	if err != nil {
+		return record, err
+	}
+	err = json.Unmarshal(bs, &record)
+	return record, err
+}
+
+// recordApplied records [changes], and that the state was checked against
+// [checked] migrations
+func recordApplied(app *application.Avalanche, checked int, changes []Change) error {
+	record, err := LoadRecord(app)
+	if err != nil {
+		return err
+	}
+	if record.Checked == nil {
+		record.Checked = map[string]int{}
+	}
+	record.Checked[stateDir(app)] = checked
+	now := time.Now().UTC()
+	for _, change := range changes {
+		record.Applied = append(record.Applied, AppliedMigration{
+			Migration:   change.Migration,
+			Description: change.Description,
+			AppliedAt:   now,
+		})
+	}
+	bs, err := json.MarshalIndent(record, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(app.GetMigrationsRecordPath(), bs, constants.WriteReadReadPerms)
+}
+
+// takeSnapshot saves the CLI state before migrating, including the project
+// dir in use, keeping only the latest constants.MaxMigrationSnapshots snapshots
+func takeSnapshot(app *application.Avalanche) (string, error) {
+	snapshotsDir := app.GetMigrationSnapshotsDir()
+	snapshotPath := filepath.Join(snapshotsDir, backup.DefaultFileName())
+	if _, err := backup.Create(app.GetBaseDir(), snapshotPath, backup.CreateOptions{ProjectDir: app.GetProjectDir()}); err != nil {
+		return "", err
+	}
+	snapshots, err := filepath.Glob(filepath.Join(snapshotsDir, "*"))
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(snapshots)
+	for len(snapshots) > constants.MaxMigrationSnapshots {
+		if err := os.Remove(snapshots[0]); err != nil {
+			return "", err
+		}
+		snapshots = snapshots[1:]
+	}
+	return snapshotPath, nil
+}
@@ -5,10 +5,14 @@ package migrations
 import (
 	"bytes"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -139,3 +143,70 @@ func TestRunMigrations(t *testing.T) {
 		require.Equal(tt.expectedOutput, bufWriter.String())
 	}
 }
+
+func TestRollbackRemovesMigratedFiles(t *testing.T) {
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	require := require.New(t)
+	app := &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+	projectDir := t.TempDir()
+	app.SetProjectDir(projectDir)
+	topLevelSidecar := filepath.Join(app.GetBaseDir(), "test"+constants.SidecarSuffix)
+	require.NoError(os.WriteFile(topLevelSidecar, []byte("{}"), constants.WriteReadReadPerms))
+	projectGenesis := filepath.Join(app.GetSubnetDir(), "project", constants.GenesisFileName)
+	require.NoError(os.MkdirAll(filepath.Dir(projectGenesis), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(projectGenesis, []byte("{}"), constants.WriteReadReadPerms))
+
+	err := runMigrations(app, map[int]migrationFunc{
+		0: migrateTopLevelFiles,
+		1: func(app *application.Avalanche, r *migrationRunner) error {
+			if !r.apply("rewrite project genesis") {
+				return nil
+			}
+			if err := os.WriteFile(projectGenesis, []byte("bogus"), constants.WriteReadReadPerms); err != nil {
+				return err
+			}
+			return errors.New("bogus fail")
+		},
+	})
+	require.Error(err)
+	// the moved sidecar is back in place, and its new location is gone
+	require.FileExists(topLevelSidecar)
+	require.NoDirExists(filepath.Join(app.GetBaseDir(), constants.SubnetDir, "test"))
+	bs, err := os.ReadFile(projectGenesis)
+	require.NoError(err)
+	require.Equal("{}", string(bs))
+	record, err := LoadRecord(app)
+	require.NoError(err)
+	require.Empty(record.Checked)
+}
+
+func TestRunNewMigrations(t *testing.T) {
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	require := require.New(t)
+	app := &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+	require.NoError(os.MkdirAll(app.GetSubnetDir(), constants.DefaultPerms755))
+
+	require.NoError(RunNewMigrations(app))
+	record, err := LoadRecord(app)
+	require.NoError(err)
+	require.Equal(len(getMigrations()), record.Checked[app.GetBaseDir()])
+	require.Empty(record.Applied)
+
+	// once checked, the state is not looked at again
+	topLevelSidecar := filepath.Join(app.GetBaseDir(), "test"+constants.SidecarSuffix)
+	require.NoError(os.WriteFile(topLevelSidecar, []byte("{}"), constants.WriteReadReadPerms))
+	require.NoError(RunNewMigrations(app))
+	require.FileExists(topLevelSidecar)
+
+	// a project dir is checked on its own
+	app.SetProjectDir(t.TempDir())
+	require.NoError(os.MkdirAll(app.GetSubnetDir(), constants.DefaultPerms755))
+	require.NoError(RunNewMigrations(app))
+	require.NoFileExists(topLevelSidecar)
+	record, err = LoadRecord(app)
+	require.NoError(err)
+	require.Len(record.Applied, 1)
+	require.Equal(len(getMigrations()), record.Checked[app.GetProjectDir()])
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package migrations
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+// Node cloud configs and elastic subnet configs were stored without
+// a schema version. Stamp the current one on them so that future
+// changes to these models can be migrated.
+func migrateSchemaVersions(app *application.Avalanche, runner *migrationRunner) error {
+	nodes, err := os.ReadDir(app.GetNodesDir())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, node := range nodes {
+		if !node.IsDir() || !utils.FileExists(app.GetNodeConfigPath(node.Name())) {
+			continue
+		}
+		nodeConfig, err := app.LoadClusterNodeConfig(node.Name())
+		if err != nil {
+			return err
+		}
+		if nodeConfig.Version != "" {
+			continue
+		}
+		if !runner.apply(fmt.Sprintf("add schema version to node config %s", node.Name())) {
+			continue
+		}
+		if err := app.CreateNodeCloudConfigFile(node.Name(), &nodeConfig); err != nil {
+			return err
+		}
+	}
+	subnets, err := os.ReadDir(app.GetSubnetDir())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, subnet := range subnets {
+		if !subnet.IsDir() || !utils.FileExists(app.GetElasticSubnetConfigPath(subnet.Name())) {
+			continue
+		}
+		esc, err := app.LoadElasticSubnetConfig(subnet.Name())
+		if err != nil {
+			return err
+		}
+		if esc.Version != "" {
+			continue
+		}
+		if !runner.apply(fmt.Sprintf("add schema version to elastic subnet config of %s", subnet.Name())) {
+			continue
+		}
+		if err := app.CreateElasticSubnetConfig(subnet.Name(), &esc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package migrations
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaVersionsMigration(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	testDir := t.TempDir()
+	app := &application.Avalanche{}
+	app.Setup(testDir, logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+	require.NoError(os.MkdirAll(app.GetSubnetDir(), constants.DefaultPerms755))
+
+	// node config stored without schema version
+	nodeName := "i-123"
+	nodeConfigPath := app.GetNodeConfigPath(nodeName)
+	require.NoError(os.MkdirAll(filepath.Dir(nodeConfigPath), constants.DefaultPerms755))
+	bs, err := json.Marshal(models.NodeConfig{NodeID: nodeName, Region: "us-east-1"})
+	require.NoError(err)
+	require.NoError(os.WriteFile(nodeConfigPath, bs, constants.WriteReadReadPerms))
+
+	pending, err := PendingMigrations(app)
+	require.NoError(err)
+	require.Len(pending, 1)
+	require.Equal(2, pending[0].Migration)
+	// dry-run must not modify anything
+	nodeConfig, err := app.LoadClusterNodeConfig(nodeName)
+	require.NoError(err)
+	require.Empty(nodeConfig.Version)
+
+	require.NoError(RunMigrations(app))
+	nodeConfig, err = app.LoadClusterNodeConfig(nodeName)
+	require.NoError(err)
+	require.Equal(constants.NodeConfigVersion, nodeConfig.Version)
+	require.Equal("us-east-1", nodeConfig.Region)
+
+	record, err := LoadRecord(app)
+	require.NoError(err)
+	require.Len(record.Applied, 1)
+	snapshots, err := os.ReadDir(app.GetMigrationSnapshotsDir())
+	require.NoError(err)
+	require.Len(snapshots, 1)
+
+	pending, err = PendingMigrations(app)
+	require.NoError(err)
+	require.Empty(pending)
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package migrations
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// Sidecars used to describe a subnet with exactly one chain. Subnets now list
+// the chains deployed into them on each network, so record the chain each
+// deployed subnet was created with, and name the subnet of sidecars missing it.
+func migrateSidecarChains(app *application.Avalanche, runner *migrationRunner) error {
+	subnets, err := os.ReadDir(app.GetSubnetDir())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, subnet := range subnets {
+		if !subnet.IsDir() || !utils.FileExists(app.GetSidecarPath(subnet.Name())) {
+			continue
+		}
+		sc, err := app.LoadSidecar(subnet.Name())
+		if err != nil {
+			return err
+		}
+		changed := false
+		if sc.Subnet == "" {
+			sc.Subnet = sc.Name
+			changed = true
+		}
+		if !sc.IsAddedChain() {
+			for networkName, networkData := range sc.Networks {
+				if networkData.BlockchainID == ids.Empty || networkData.Chains != nil {
+					continue
+				}
+				networkData.Chains = map[string]ids.ID{sc.Name: networkData.BlockchainID}
+				sc.Networks[networkName] = networkData
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if !runner.apply(fmt.Sprintf("record the chains of subnet %s", sc.Name)) {
+			continue
+		}
+		if err := app.UpdateSidecar(&sc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
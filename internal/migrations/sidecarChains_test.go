@@ -0,0 +1,59 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package migrations
+
+import (
+	"io"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSidecarChainsMigration(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	app := &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+
+	blockchainID := ids.GenerateTestID()
+	require.NoError(app.CreateSidecar(&models.Sidecar{
+		Name: "deployed",
+		Networks: map[string]models.NetworkData{
+			models.Fuji.String(): {SubnetID: ids.GenerateTestID(), BlockchainID: blockchainID},
+			// subnet only deploy
+			models.Mainnet.String(): {SubnetID: ids.GenerateTestID()},
+		},
+	}))
+	require.NoError(app.CreateSidecar(&models.Sidecar{Name: "migrated", Subnet: "migrated"}))
+
+	runner := migrationRunner{
+		dryRun:     true,
+		migrations: map[int]migrationFunc{0: migrateSidecarChains},
+	}
+	require.NoError(runner.run(app))
+	require.Len(runner.changes, 1)
+	sc, err := app.LoadSidecar("deployed")
+	require.NoError(err)
+	require.Empty(sc.Subnet)
+
+	runner.dryRun = false
+	runner.changes = nil
+	require.NoError(runner.run(app))
+	require.Len(runner.changes, 1)
+	sc, err = app.LoadSidecar("deployed")
+	require.NoError(err)
+	require.Equal("deployed", sc.Subnet)
+	require.Equal(map[string]ids.ID{"deployed": blockchainID}, sc.Networks[models.Fuji.String()].Chains)
+	require.Nil(sc.Networks[models.Mainnet.String()].Chains)
+
+	runner.changes = nil
+	require.NoError(runner.run(app))
+	require.Empty(runner.changes)
+}
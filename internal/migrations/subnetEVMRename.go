@@ -4,6 +4,7 @@
 package migrations
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -40,7 +41,9 @@ func migrateSubnetEVMNames(app *application.Avalanche, runner *migrationRunner)
 		}
 
 		if string(sc.VM) == oldSubnetEVM {
-			runner.printMigrationMessage()
+			if !runner.apply(fmt.Sprintf("rename VM of subnet %s from %s to %s", sc.Name, oldSubnetEVM, models.SubnetEvm)) {
+				continue
+			}
 			sc.VM = models.SubnetEvm
 			if err = app.UpdateSidecar(&sc); err != nil {
 				return err
@@ -27,7 +27,9 @@ func migrateTopLevelFiles(app *application.Avalanche, runner *migrationRunner) e
 		return err
 	}
 	if len(sidecarMatches) > 0 || len(genesisMatches) > 0 {
-		runner.printMigrationMessage()
+		if !runner.apply("move top level sidecar and genesis files into " + filepath.Join(baseDir, constants.SubnetDir)) {
+			return nil
+		}
 	}
 
 	//nolint: gocritic
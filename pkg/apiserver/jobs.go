@@ -0,0 +1,251 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package apiserver
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+
+	// maxFinishedJobs is the number of finished jobs kept for inspection
+	maxFinishedJobs = 100
+)
+
+// JobFunc does the work of a job. Everything it prints through ux.Logger
+// is recorded as job progress, and the returned value is the job result.
+type JobFunc func() (interface{}, error)
+
+// Job is the state of an asynchronous operation as reported by the API.
+type Job struct {
+	ID         string      `json:"id"`
+	Kind       string      `json:"kind"`
+	Status     JobStatus   `json:"status"`
+	Progress   []string    `json:"progress"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	StartedAt  *time.Time  `json:"startedAt,omitempty"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+}
+
+func (j *Job) Finished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed
+}
+
+type job struct {
+	Job
+	fn JobFunc
+	// closed and replaced on every change, to wake up watchers
+	changed chan struct{}
+	// partial progress line not yet terminated by a newline
+	partial bytes.Buffer
+}
+
+// JobManager runs jobs one at a time, in submission order. Jobs share the
+// CLI state (sidecars, local network, the user logger), so they are not
+// run concurrently.
+type JobManager struct {
+	lock  sync.Mutex
+	jobs  map[string]*job
+	order []string
+	queue chan *job
+}
+
+func NewJobManager() *JobManager {
+	return &JobManager{
+		jobs:  map[string]*job{},
+		queue: make(chan *job, 1024),
+	}
+}
+
+// Run processes submitted jobs until done is closed.
+func (m *JobManager) Run(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case j := <-m.queue:
+			m.run(j)
+		}
+	}
+}
+
+// Submit queues a job and returns its initial state.
+func (m *JobManager) Submit(kind string, fn JobFunc) (Job, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	j := &job{
+		Job: Job{
+			ID:        newJobID(),
+			Kind:      kind,
+			Status:    JobPending,
+			Progress:  []string{},
+			CreatedAt: time.Now().UTC(),
+		},
+		fn:      fn,
+		changed: make(chan struct{}),
+	}
+	select {
+	case m.queue <- j:
+	default:
+		return Job{}, fmt.Errorf("too many pending jobs")
+	}
+	m.jobs[j.ID] = j
+	m.order = append(m.order, j.ID)
+	m.prune()
+	return j.snapshot(), nil
+}
+
+// Get returns the current state of the job with the given ID.
+func (m *JobManager) Get(id string) (Job, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return j.snapshot(), true
+}
+
+// List returns all known jobs, oldest first.
+func (m *JobManager) List() []Job {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	jobs := make([]Job, 0, len(m.order))
+	for _, id := range m.order {
+		jobs = append(jobs, m.jobs[id].snapshot())
+	}
+	return jobs
+}
+
+// Watch returns the current state of a job together with a channel that is
+// closed on its next change.
+func (m *JobManager) Watch(id string) (Job, <-chan struct{}, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return Job{}, nil, false
+	}
+	return j.snapshot(), j.changed, true
+}
+
+func (m *JobManager) run(j *job) {
+	m.update(j, func() {
+		now := time.Now().UTC()
+		j.Status = JobRunning
+		j.StartedAt = &now
+	})
+	// capture user output as progress, while keeping it on the server output
+	prevWriter := ux.Logger.Writer
+	ux.Logger.Writer = io.MultiWriter(prevWriter, &progressWriter{m: m, j: j})
+	var (
+		result interface{}
+		err    error
+	)
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("job panicked: %v", r)
+			}
+		}()
+		result, err = j.fn()
+	}()
+	ux.Logger.Writer = prevWriter
+	m.update(j, func() {
+		now := time.Now().UTC()
+		j.FinishedAt = &now
+		if j.partial.Len() > 0 {
+			j.Progress = append(j.Progress, j.partial.String())
+			j.partial.Reset()
+		}
+		if err != nil {
+			j.Status = JobFailed
+			j.Error = err.Error()
+			return
+		}
+		j.Status = JobSucceeded
+		j.Result = result
+	})
+}
+
+func (m *JobManager) update(j *job, f func()) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	f()
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// prune drops the oldest finished jobs over the retention limit
+func (m *JobManager) prune() {
+	finished := 0
+	for _, id := range m.order {
+		if m.jobs[id].Finished() {
+			finished++
+		}
+	}
+	kept := m.order[:0]
+	for _, id := range m.order {
+		if finished > maxFinishedJobs && m.jobs[id].Finished() {
+			delete(m.jobs, id)
+			finished--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	m.order = kept
+}
+
+func newJobID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func (j *job) snapshot() Job {
+	s := j.Job
+	s.Progress = append([]string{}, j.Progress...)
+	return s
+}
+
+type progressWriter struct {
+	m *JobManager
+	j *job
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.m.update(w.j, func() {
+		w.j.partial.Write(p)
+		for {
+			line, err := w.j.partial.ReadString('\n')
+			if err != nil {
+				// incomplete line, keep it for the next write
+				rest := line
+				w.j.partial.Reset()
+				w.j.partial.WriteString(rest)
+				return
+			}
+			w.j.Progress = append(w.j.Progress, line[:len(line)-1])
+		}
+	})
+	return len(p), nil
+}
@@ -0,0 +1,296 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package apiserver implements the HTTP API served by `avalanche serve`:
+// routing, token authentication and an asynchronous job model with
+// progress streaming. The endpoints themselves are registered by the
+// serve command.
+package apiserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	APIPrefix = "/v1"
+
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 10 * time.Second
+)
+
+var (
+	ErrUnauthorized = errors.New("missing or invalid API token")
+	ErrNotFound     = errors.New("not found")
+)
+
+// HTTPError is an error with the HTTP status code to answer with.
+type HTTPError struct {
+	Code int
+	Err  error
+}
+
+func (e *HTTPError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+func BadRequest(err error) error {
+	return &HTTPError{Code: http.StatusBadRequest, Err: err}
+}
+
+func NotFound(err error) error {
+	return &HTTPError{Code: http.StatusNotFound, Err: err}
+}
+
+// HandlerFunc answers a request with a value that is sent back as JSON.
+type HandlerFunc func(r *http.Request) (interface{}, error)
+
+// JobHandlerFunc validates a request and returns the job that serves it.
+type JobHandlerFunc func(r *http.Request) (JobFunc, error)
+
+type route struct {
+	method   string
+	segments []string
+	handler  http.HandlerFunc
+}
+
+type paramsKey struct{}
+
+type Server struct {
+	log    logging.Logger
+	token  string
+	jobs   *JobManager
+	routes []route
+}
+
+// NewServer creates a server that requires token as bearer token on all requests.
+func NewServer(log logging.Logger, token string) *Server {
+	s := &Server{
+		log:   log,
+		token: token,
+		jobs:  NewJobManager(),
+	}
+	s.Handle(http.MethodGet, "/jobs", func(*http.Request) (interface{}, error) {
+		return s.jobs.List(), nil
+	})
+	s.Handle(http.MethodGet, "/jobs/{id}", func(r *http.Request) (interface{}, error) {
+		j, ok := s.jobs.Get(PathParam(r, "id"))
+		if !ok {
+			return nil, NotFound(fmt.Errorf("job %s not found", PathParam(r, "id")))
+		}
+		return j, nil
+	})
+	s.route(http.MethodGet, "/jobs/{id}/events", s.streamJob)
+	return s
+}
+
+// Jobs returns the job manager of the server.
+func (s *Server) Jobs() *JobManager {
+	return s.jobs
+}
+
+// Handle registers a synchronous endpoint. path is relative to [APIPrefix]
+// and may contain {param} segments, accessible with [PathParam].
+func (s *Server) Handle(method string, path string, h HandlerFunc) {
+	s.route(method, path, func(w http.ResponseWriter, r *http.Request) {
+		v, err := h(r)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		s.writeJSON(w, http.StatusOK, v)
+	})
+}
+
+// HandleJob registers an asynchronous endpoint. The request is validated
+// synchronously and answered with 202 and the queued job.
+func (s *Server) HandleJob(method string, path string, kind string, h JobHandlerFunc) {
+	s.route(method, path, func(w http.ResponseWriter, r *http.Request) {
+		fn, err := h(r)
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		j, err := s.jobs.Submit(kind, fn)
+		if err != nil {
+			s.writeError(w, &HTTPError{Code: http.StatusServiceUnavailable, Err: err})
+			return
+		}
+		w.Header().Set("Location", APIPrefix+"/jobs/"+j.ID)
+		s.writeJSON(w, http.StatusAccepted, j)
+	})
+}
+
+// PathParam returns the value of a {name} path segment.
+func PathParam(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+	return params[name]
+}
+
+// DecodeBody decodes the JSON request body into v, rejecting unknown fields.
+func DecodeBody(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return BadRequest(fmt.Errorf("invalid request body: %w", err))
+	}
+	return nil
+}
+
+func (s *Server) route(method string, path string, h http.HandlerFunc) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(path, "/"), "/"),
+		handler:  h,
+	})
+}
+
+// ServeHTTP authenticates and dispatches a request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		s.writeError(w, &HTTPError{Code: http.StatusUnauthorized, Err: ErrUnauthorized})
+		return
+	}
+	path, ok := strings.CutPrefix(r.URL.Path, APIPrefix+"/")
+	if !ok {
+		s.writeError(w, NotFound(ErrNotFound))
+		return
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	methodMismatch := false
+	for _, rt := range s.routes {
+		params, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			methodMismatch = true
+			continue
+		}
+		rt.handler(w, r.WithContext(context.WithValue(r.Context(), paramsKey{}, params)))
+		return
+	}
+	if methodMismatch {
+		s.writeError(w, &HTTPError{Code: http.StatusMethodNotAllowed, Err: fmt.Errorf("method %s not allowed", r.Method)})
+		return
+	}
+	s.writeError(w, NotFound(ErrNotFound))
+}
+
+// ListenAndServe serves the API on addr and runs queued jobs until ctx is done.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	done := make(chan struct{})
+	defer close(done)
+	go s.jobs.Run(done)
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.ListenAndServe()
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	}
+}
+
+func (rt route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// streamJob streams job progress as server-sent events: one "progress"
+// event per output line, and a final "done" event with the finished job.
+func (s *Server) streamJob(w http.ResponseWriter, r *http.Request) {
+	id := PathParam(r, "id")
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, errors.New("streaming not supported"))
+		return
+	}
+	j, changed, ok := s.jobs.Watch(id)
+	if !ok {
+		s.writeError(w, NotFound(fmt.Errorf("job %s not found", id)))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	sent := 0
+	for {
+		for ; sent < len(j.Progress); sent++ {
+			fmt.Fprintf(w, "event: progress\ndata: %s\n\n", j.Progress[sent])
+		}
+		if j.Finished() {
+			jobBytes, err := json.Marshal(j)
+			if err != nil {
+				s.log.Warn("failed to marshal job", zap.Error(err))
+				return
+			}
+			fmt.Fprintf(w, "event: done\ndata: %s\n\n", jobBytes)
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		}
+		j, changed, _ = s.jobs.Watch(id)
+	}
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.log.Warn("failed to write response", zap.Error(err))
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		code = httpErr.Code
+	}
+	s.writeJSON(w, code, map[string]string{"error": err.Error()})
+}
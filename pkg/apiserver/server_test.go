@@ -0,0 +1,145 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "test-token"
+
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	s := NewServer(logging.NoLog{}, testToken)
+	s.Handle(http.MethodGet, "/subnets/{name}", func(r *http.Request) (interface{}, error) {
+		if PathParam(r, "name") == "missing" {
+			return nil, NotFound(errors.New("subnet missing not found"))
+		}
+		return map[string]string{"name": PathParam(r, "name")}, nil
+	})
+	s.HandleJob(http.MethodPost, "/echo", "echo", func(r *http.Request) (JobFunc, error) {
+		var req struct{ Msg string }
+		if err := DecodeBody(r, &req); err != nil {
+			return nil, err
+		}
+		return func() (interface{}, error) {
+			ux.Logger.PrintToUser("first %s", req.Msg)
+			ux.Logger.PrintToUser("second")
+			if req.Msg == "fail" {
+				return nil, errors.New("failed as requested")
+			}
+			return req.Msg, nil
+		}, nil
+	})
+	done := make(chan struct{})
+	go s.Jobs().Run(done)
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		close(done)
+	})
+	return s, ts
+}
+
+func doRequest(t *testing.T, method string, url string, token string, body string) (int, []byte) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, respBody
+}
+
+func TestAuthAndRouting(t *testing.T) {
+	require := require.New(t)
+	_, ts := newTestServer(t)
+
+	code, _ := doRequest(t, http.MethodGet, ts.URL+"/v1/subnets/foo", "", "")
+	require.Equal(http.StatusUnauthorized, code)
+	code, _ = doRequest(t, http.MethodGet, ts.URL+"/v1/subnets/foo", "wrong", "")
+	require.Equal(http.StatusUnauthorized, code)
+
+	code, body := doRequest(t, http.MethodGet, ts.URL+"/v1/subnets/foo", testToken, "")
+	require.Equal(http.StatusOK, code)
+	require.JSONEq(`{"name": "foo"}`, string(body))
+
+	code, body = doRequest(t, http.MethodGet, ts.URL+"/v1/subnets/missing", testToken, "")
+	require.Equal(http.StatusNotFound, code)
+	require.JSONEq(`{"error": "subnet missing not found"}`, string(body))
+
+	code, _ = doRequest(t, http.MethodDelete, ts.URL+"/v1/subnets/foo", testToken, "")
+	require.Equal(http.StatusMethodNotAllowed, code)
+	code, _ = doRequest(t, http.MethodGet, ts.URL+"/v1/unknown", testToken, "")
+	require.Equal(http.StatusNotFound, code)
+	code, _ = doRequest(t, http.MethodPost, ts.URL+"/v1/echo", testToken, `{"Unknown": 1}`)
+	require.Equal(http.StatusBadRequest, code)
+}
+
+func TestJobLifecycle(t *testing.T) {
+	require := require.New(t)
+	_, ts := newTestServer(t)
+
+	for _, msg := range []string{"hello", "fail"} {
+		code, body := doRequest(t, http.MethodPost, ts.URL+"/v1/echo", testToken, `{"Msg": "`+msg+`"}`)
+		require.Equal(http.StatusAccepted, code)
+		var j Job
+		require.NoError(json.Unmarshal(body, &j))
+		require.Equal("echo", j.Kind)
+
+		// the event stream ends once the job is finished
+		code, body = doRequest(t, http.MethodGet, ts.URL+"/v1/jobs/"+j.ID+"/events", testToken, "")
+		require.Equal(http.StatusOK, code)
+		events := string(body)
+		require.Contains(events, "event: progress\ndata: first "+msg+"\n\n")
+		require.Contains(events, "event: progress\ndata: second\n\n")
+		require.Contains(events, "event: done\n")
+
+		code, body = doRequest(t, http.MethodGet, ts.URL+"/v1/jobs/"+j.ID, testToken, "")
+		require.Equal(http.StatusOK, code)
+		require.NoError(json.Unmarshal(body, &j))
+		require.Equal([]string{"first " + msg, "second"}, j.Progress)
+		if msg == "fail" {
+			require.Equal(JobFailed, j.Status)
+			require.Equal("failed as requested", j.Error)
+		} else {
+			require.Equal(JobSucceeded, j.Status)
+			require.Equal("hello", j.Result)
+		}
+	}
+
+	code, body := doRequest(t, http.MethodGet, ts.URL+"/v1/jobs", testToken, "")
+	require.Equal(http.StatusOK, code)
+	var jobs []Job
+	require.NoError(json.Unmarshal(body, &jobs))
+	require.Len(jobs, 2)
+}
+
+func TestToken(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "api-token")
+	token, err := LoadOrCreateToken(path)
+	require.NoError(err)
+	require.Len(token, 2*tokenBytes)
+	again, err := LoadOrCreateToken(path)
+	require.NoError(err)
+	require.Equal(token, again)
+	rotated, err := CreateToken(path)
+	require.NoError(err)
+	require.NotEqual(token, rotated)
+}
@@ -25,6 +25,7 @@ import (
 type Avalanche struct {
 	Log        logging.Logger
 	baseDir    string
+	projectDir string
 	Conf       *config.Config
 	Prompt     prompts.Prompter
 	Apm        *apm.APM
@@ -56,7 +57,21 @@ func (app *Avalanche) GetBaseDir() string {
 	return app.baseDir
 }
 
+// SetProjectDir makes the CLI keep subnet state under the given project dir
+// instead of the global base dir. An empty dir restores the default.
+func (app *Avalanche) SetProjectDir(projectDir string) {
+	app.projectDir = projectDir
+}
+
+// GetProjectDir returns the active project dir, or "" if none is in use
+func (app *Avalanche) GetProjectDir() string {
+	return app.projectDir
+}
+
 func (app *Avalanche) GetSubnetDir() string {
+	if app.projectDir != "" {
+		return filepath.Join(app.projectDir, constants.SubnetDir)
+	}
 	return filepath.Join(app.baseDir, constants.SubnetDir)
 }
 
@@ -79,10 +94,20 @@ func (app *Avalanche) GetServicesDir(baseDir string) string {
 	return filepath.Join(baseDir, constants.ServicesDir)
 }
 
+// GetCustomVMDir returns the dir custom VM binaries are kept in. As these are
+// named after their subnet, they are kept in the project dir when one is in use
 func (app *Avalanche) GetCustomVMDir() string {
+	if app.projectDir != "" {
+		return filepath.Join(app.projectDir, constants.CustomVMDir)
+	}
 	return filepath.Join(app.baseDir, constants.CustomVMDir)
 }
 
+// GetGenesisTemplatesDir returns the dir always searched for user genesis templates
+func (app *Avalanche) GetGenesisTemplatesDir() string {
+	return filepath.Join(app.baseDir, constants.GenesisTemplatesDir)
+}
+
 func (app *Avalanche) GetPluginsDir() string {
 	return filepath.Join(app.baseDir, constants.PluginDir)
 }
@@ -154,6 +179,17 @@ func (app *Avalanche) GetExtraLocalNetworkSnapshotsDir() string {
 	return filepath.Join(app.GetSnapshotsDir(), constants.ExtraLocalNetworkDataSnapshotsDir)
 }
 
+// GetVMTemplatesDir returns the dir always searched for VM template manifests
+func (app *Avalanche) GetVMTemplatesDir() string {
+	return filepath.Join(app.baseDir, constants.VMTemplatesDir)
+}
+
+// GetVMTemplateBinDir returns the dir the releases of the VM template [name]
+// are installed into
+func (app *Avalanche) GetVMTemplateBinDir(name string) string {
+	return filepath.Join(app.baseDir, constants.AvalancheCliBinDir, name)
+}
+
 func (app *Avalanche) GetSubnetEVMBinDir() string {
 	return filepath.Join(app.baseDir, constants.AvalancheCliBinDir, constants.SubnetEVMInstallDir)
 }
@@ -252,6 +288,22 @@ func (app *Avalanche) GetElasticSubnetConfigPath(subnetName string) string {
 	return filepath.Join(app.GetSubnetDir(), subnetName, constants.ElasticSubnetConfigFileName)
 }
 
+func (app *Avalanche) GetDeployHooksPath(subnetName string) string {
+	return filepath.Join(app.GetSubnetDir(), subnetName, constants.DeployHooksFileName)
+}
+
+func (app *Avalanche) GetAPITokenPath() string {
+	return filepath.Join(app.baseDir, constants.APITokenFileName)
+}
+
+func (app *Avalanche) GetMigrationsRecordPath() string {
+	return filepath.Join(app.baseDir, constants.MigrationsRecordFileName)
+}
+
+func (app *Avalanche) GetMigrationSnapshotsDir() string {
+	return filepath.Join(app.baseDir, constants.MigrationSnapshotsDir)
+}
+
 func (app *Avalanche) GetKeyDir() string {
 	return filepath.Join(app.baseDir, constants.KeyDir)
 }
@@ -312,6 +364,35 @@ func (app *Avalanche) WriteLockUpgradeFile(subnetName string, bytes []byte) erro
 	return app.writeFile(upgradeBytesLockFilePath, bytes)
 }
 
+func (app *Avalanche) GetUpgradeRehearsalPath(subnetName string) string {
+	return filepath.Join(app.GetSubnetDir(), subnetName, constants.UpgradeRehearsalFileName)
+}
+
+func (app *Avalanche) WriteUpgradeRehearsal(subnetName string, rehearsal *models.UpgradeRehearsal) error {
+	rehearsalBytes, err := json.MarshalIndent(rehearsal, "", "    ")
+	if err != nil {
+		return err
+	}
+	return app.writeFile(app.GetUpgradeRehearsalPath(subnetName), rehearsalBytes)
+}
+
+// LoadUpgradeRehearsal returns the last upgrade rehearsal of [subnetName], or
+// nil if it was never rehearsed
+func (app *Avalanche) LoadUpgradeRehearsal(subnetName string) (*models.UpgradeRehearsal, error) {
+	rehearsalBytes, err := os.ReadFile(app.GetUpgradeRehearsalPath(subnetName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var rehearsal models.UpgradeRehearsal
+	if err := json.Unmarshal(rehearsalBytes, &rehearsal); err != nil {
+		return nil, err
+	}
+	return &rehearsal, nil
+}
+
 func (app *Avalanche) WriteGenesisFile(subnetName string, genesisBytes []byte) error {
 	genesisPath := app.GetGenesisPath(subnetName)
 
@@ -517,6 +598,17 @@ func (app *Avalanche) UpdateSidecarNetworks(
 	if sc.Networks == nil {
 		sc.Networks = make(map[string]models.NetworkData)
 	}
+	chains := sc.Networks[network.Name()].Chains
+	if !sc.IsAddedChain() && blockchainID != ids.Empty {
+		if chains == nil {
+			chains = map[string]ids.ID{}
+		}
+		chains[sc.Name] = blockchainID
+	}
+	var validatorTxs map[string]ids.ID
+	if sc.Networks[network.Name()].SubnetID == subnetID {
+		validatorTxs = sc.Networks[network.Name()].ValidatorTxs
+	}
 	sc.Networks[network.Name()] = models.NetworkData{
 		SubnetID:                    subnetID,
 		TransferSubnetOwnershipTxID: transferSubnetOwnershipTxID,
@@ -524,13 +616,94 @@ func (app *Avalanche) UpdateSidecarNetworks(
 		RPCVersion:                  sc.RPCVersion,
 		TeleporterMessengerAddress:  teleporterMessengerAddress,
 		TeleporterRegistryAddress:   teleporterRegistryAddress,
+		Chains:                      chains,
+		ValidatorTxs:                validatorTxs,
 	}
 	if err := app.UpdateSidecar(sc); err != nil {
 		return fmt.Errorf("creation of chains and subnet was successful, but failed to update sidecar: %w", err)
 	}
+	if sc.IsAddedChain() && blockchainID != ids.Empty {
+		if err := app.addSubnetChain(sc.GetSubnetName(), network, sc.Name, blockchainID); err != nil {
+			return fmt.Errorf("creation of chain was successful, but failed to update the sidecar of subnet %s: %w", sc.GetSubnetName(), err)
+		}
+	}
 	return nil
 }
 
+// UpdateSidecarDeployStep checkpoints step [step] of a deploy of [sc] into
+// [network], which issued [txID]. The network data stays pending until
+// [UpdateSidecarNetworks] records the deploy result
+func (app *Avalanche) UpdateSidecarDeployStep(
+	sc *models.Sidecar,
+	network models.Network,
+	subnetID ids.ID,
+	transferSubnetOwnershipTxID ids.ID,
+	step string,
+	txID ids.ID,
+) error {
+	if sc.Networks == nil {
+		sc.Networks = make(map[string]models.NetworkData)
+	}
+	networkData := sc.Networks[network.Name()]
+	networkData.SubnetID = subnetID
+	networkData.TransferSubnetOwnershipTxID = transferSubnetOwnershipTxID
+	networkData.BlockchainID = ids.Empty
+	networkData.Pending = true
+	if networkData.DeployTxs == nil {
+		networkData.DeployTxs = make(map[string]ids.ID)
+	}
+	networkData.DeployTxs[step] = txID
+	sc.Networks[network.Name()] = networkData
+	return app.UpdateSidecar(sc)
+}
+
+// UpdateSidecarDeployHookResults records [results] as the outcome of the
+// deploy hooks of the last deploy of [sc] into [network]
+func (app *Avalanche) UpdateSidecarDeployHookResults(
+	sc *models.Sidecar,
+	network models.Network,
+	results []models.DeployHookResult,
+) error {
+	if sc.DeployHookResults == nil {
+		sc.DeployHookResults = make(map[string][]models.DeployHookResult)
+	}
+	sc.DeployHookResults[network.Name()] = results
+	return app.UpdateSidecar(sc)
+}
+
+// addSubnetChain records chain [chainName] in the network data of subnet [subnetName]
+func (app *Avalanche) addSubnetChain(subnetName string, network models.Network, chainName string, blockchainID ids.ID) error {
+	subnetSC, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	networkData, ok := subnetSC.Networks[network.Name()]
+	if !ok {
+		return fmt.Errorf("subnet %s is not deployed on %s", subnetName, network.Name())
+	}
+	if networkData.Chains == nil {
+		networkData.Chains = map[string]ids.ID{}
+	}
+	networkData.Chains[chainName] = blockchainID
+	subnetSC.Networks[network.Name()] = networkData
+	return app.UpdateSidecar(&subnetSC)
+}
+
+// GetSubnetNetworkData returns the network data of the subnet the chain of
+// [sc] is deployed into on [network]
+func (app *Avalanche) GetSubnetNetworkData(sc *models.Sidecar, network models.Network) (models.NetworkData, bool, error) {
+	subnetSC := *sc
+	if sc.IsAddedChain() {
+		var err error
+		subnetSC, err = app.LoadSidecar(sc.GetSubnetName())
+		if err != nil {
+			return models.NetworkData{}, false, fmt.Errorf("failed to load the sidecar of subnet %s: %w", sc.GetSubnetName(), err)
+		}
+	}
+	networkData, ok := subnetSC.Networks[network.Name()]
+	return networkData, ok && networkData.SubnetID != ids.Empty, nil
+}
+
 func (app *Avalanche) UpdateSidecarElasticSubnet(
 	sc *models.Sidecar,
 	network models.Network,
@@ -576,6 +749,27 @@ func (app *Avalanche) UpdateSidecarPermissionlessValidator(
 	return nil
 }
 
+// UpdateSidecarValidatorTx records [txID] as the last AddSubnetValidatorTx
+// of [nodeID] on [network], or forgets the validator if [txID] is empty
+func (app *Avalanche) UpdateSidecarValidatorTx(
+	sc *models.Sidecar,
+	network models.Network,
+	nodeID ids.NodeID,
+	txID ids.ID,
+) error {
+	networkData := sc.Networks[network.Name()]
+	if networkData.ValidatorTxs == nil {
+		networkData.ValidatorTxs = make(map[string]ids.ID)
+	}
+	if txID == ids.Empty {
+		delete(networkData.ValidatorTxs, nodeID.String())
+	} else {
+		networkData.ValidatorTxs[nodeID.String()] = txID
+	}
+	sc.Networks[network.Name()] = networkData
+	return app.UpdateSidecar(sc)
+}
+
 func (app *Avalanche) UpdateSidecarElasticSubnetPartialTx(
 	sc *models.Sidecar,
 	network models.Network,
@@ -645,6 +839,7 @@ func (app *Avalanche) CreateNodeCloudConfigFile(nodeName string, nodeConfig *mod
 		return err
 	}
 
+	nodeConfig.Version = constants.NodeConfigVersion
 	esBytes, err := json.MarshalIndent(nodeConfig, "", "    ")
 	if err != nil {
 		return err
@@ -659,6 +854,7 @@ func (app *Avalanche) CreateElasticSubnetConfig(subnetName string, es *models.El
 		return err
 	}
 
+	es.Version = constants.ElasticSubnetConfigVersion
 	esBytes, err := json.MarshalIndent(es, "", "    ")
 	if err != nil {
 		return err
@@ -884,3 +1080,20 @@ func (app *Avalanche) ListClusterNames() ([]string, error) {
 	}
 	return maps.Keys(clustersConfig.Clusters), nil
 }
+
+// LoadDeployHooks loads the deploy hooks configured for [subnetName]. It
+// returns empty hooks if none were configured
+func (app *Avalanche) LoadDeployHooks(subnetName string) (models.DeployHooks, error) {
+	var hooks models.DeployHooks
+	jsonBytes, err := os.ReadFile(app.GetDeployHooksPath(subnetName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hooks, nil
+		}
+		return hooks, err
+	}
+	if err := json.Unmarshal(jsonBytes, &hooks); err != nil {
+		return hooks, fmt.Errorf("invalid deploy hooks for %s: %w", subnetName, err)
+	}
+	return hooks, hooks.Validate()
+}
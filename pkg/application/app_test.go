@@ -275,6 +275,52 @@ func Test_genesisExists(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestProjectDir(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+	require.Equal(filepath.Join(ap.GetBaseDir(), constants.SubnetDir), ap.GetSubnetDir())
+
+	projectDir := filepath.Join(t.TempDir(), constants.ProjectDirName)
+	ap.SetProjectDir(projectDir)
+	require.Equal(filepath.Join(projectDir, constants.SubnetDir), ap.GetSubnetDir())
+	require.Equal(filepath.Join(projectDir, constants.SubnetDir, subnetName1, constants.SidecarFileName), ap.GetSidecarPath(subnetName1))
+	// projects with subnets of the same name don't share their custom VM binary
+	require.Equal(filepath.Join(projectDir, constants.CustomVMDir, subnetName1), ap.GetCustomVMPath(subnetName1))
+
+	ap.SetProjectDir("")
+	require.Equal(filepath.Join(ap.GetBaseDir(), constants.SubnetDir), ap.GetSubnetDir())
+	require.Equal(filepath.Join(ap.GetBaseDir(), constants.CustomVMDir, subnetName1), ap.GetCustomVMPath(subnetName1))
+}
+
+func TestUpdateSidecarNetworksChains(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+	network := models.NewFujiNetwork()
+	subnetSC := &models.Sidecar{Name: subnetName1, Subnet: subnetName1}
+	chainSC := &models.Sidecar{Name: subnetName2, Subnet: subnetName1}
+	require.NoError(ap.CreateSidecar(subnetSC))
+	require.NoError(ap.CreateSidecar(chainSC))
+
+	_, deployed, err := ap.GetSubnetNetworkData(chainSC, network)
+	require.NoError(err)
+	require.False(deployed)
+
+	subnetID := ids.GenerateTestID()
+	subnetChainID := ids.GenerateTestID()
+	require.NoError(ap.UpdateSidecarNetworks(subnetSC, network, subnetID, ids.Empty, subnetChainID, "", ""))
+	subnetData, deployed, err := ap.GetSubnetNetworkData(chainSC, network)
+	require.NoError(err)
+	require.True(deployed)
+	require.Equal(subnetID, subnetData.SubnetID)
+
+	addedChainID := ids.GenerateTestID()
+	require.NoError(ap.UpdateSidecarNetworks(chainSC, network, subnetID, ids.Empty, addedChainID, "", ""))
+	require.Nil(chainSC.Networks[network.Name()].Chains)
+	control, err := ap.LoadSidecar(subnetName1)
+	require.NoError(err)
+	require.Equal(map[string]ids.ID{subnetName1: subnetChainID, subnetName2: addedChainID}, control.Networks[network.Name()].Chains)
+}
+
 func newTestApp(t *testing.T) *Avalanche {
 	tempDir := t.TempDir()
 	return &Avalanche{
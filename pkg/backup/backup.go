@@ -0,0 +1,610 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// FormatVersion is the version of the archive layout. Bump it on
+	// incompatible changes to the manifest or to the archive structure.
+	FormatVersion = 1
+
+	ManifestFileName = "backup-manifest.json"
+
+	// archive dir holding the files of the project dir
+	projectArchiveDir = "project"
+)
+
+var (
+	// contents of the base dir that make up the CLI state, together with its
+	// top level files. Custom VM binaries are included, as imported ones can't
+	// be recreated, so each custom VM adds the size of its binary to the
+	// archive. Avalanchego and plugin binaries, logs, snapshots and local
+	// network runs are not included as they can be recreated.
+	includedPaths = []string{
+		constants.SubnetDir,
+		constants.KeyDir,
+		constants.NodesDir,
+		constants.ServicesDir,
+		constants.CustomVMDir,
+		filepath.Join(constants.RunDir, constants.AWMRelayerConfigFilename),
+	}
+	// contents of the project dir, archived under projectArchiveDir
+	includedProjectPaths = []string{
+		constants.SubnetDir,
+		constants.CustomVMDir,
+	}
+	// file names holding private key material
+	secretFileNames = []string{
+		constants.StakerKeyFileName,
+		constants.BLSKeyFileName,
+		constants.APITokenFileName,
+	}
+	secretFileSuffixes = []string{
+		constants.KeySuffix,
+	}
+
+	ErrNewerBackup = errors.New("backup was created by a newer version of Avalanche-CLI")
+	ErrNewerState  = errors.New("existing state is newer than the backup")
+)
+
+type FileEntry struct {
+	Path   string
+	Size   int64
+	SHA256 string
+	Secret bool
+}
+
+type Manifest struct {
+	FormatVersion         int
+	CLIVersion            string
+	SidecarVersion        string
+	ClustersConfigVersion string
+	CreatedAt             time.Time
+	Encrypted             bool
+	ExcludesSecrets       bool
+	// project dir the files under projectArchiveDir were taken from
+	ProjectDir string `json:",omitempty"`
+	Files      []FileEntry
+}
+
+type CreateOptions struct {
+	CLIVersion     string
+	ExcludeSecrets bool
+	// if not empty, the archive is encrypted with a key derived from it
+	Passphrase string
+	// if not empty, the subnets and custom VMs of this project dir are
+	// also archived
+	ProjectDir string
+}
+
+type RestoreOptions struct {
+	Passphrase string
+	// overwrite existing files even if they were modified after the backup
+	Force bool
+	// where the project files of the backup are restored. Defaults to the
+	// project dir they were taken from
+	ProjectDir string
+	// remove the state files that are not in the backup, so that the state
+	// matches it exactly. Secrets are kept if the backup excludes them
+	Prune bool
+}
+
+// IsSecret tells if the file at [relPath] holds private key material
+func IsSecret(relPath string) bool {
+	name := filepath.Base(relPath)
+	for _, secretName := range secretFileNames {
+		if name == secretName {
+			return true
+		}
+	}
+	if strings.HasPrefix(filepath.ToSlash(relPath), constants.KeyDir+"/") {
+		for _, suffix := range secretFileSuffixes {
+			if strings.HasSuffix(name, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasProjectFiles tells if the backup holds files of a project dir
+func (m *Manifest) HasProjectFiles() bool {
+	for _, entry := range m.Files {
+		if strings.HasPrefix(entry.Path, projectArchiveDir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveSource is a file to be archived under [archivePath]
+type archiveSource struct {
+	path        string
+	archivePath string
+	secret      bool
+}
+
+// listSources returns the files of [paths] under [root], to be archived
+// under [archiveDir]
+func listSources(root string, archiveDir string, paths []string, excludeSecrets bool) ([]archiveSource, error) {
+	sources := []archiveSource{}
+	addFile := func(path string) error {
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		secret := IsSecret(relPath)
+		if secret && excludeSecrets {
+			return nil
+		}
+		archivePath := filepath.ToSlash(relPath)
+		if archiveDir != "" {
+			archivePath = archiveDir + "/" + archivePath
+		}
+		sources = append(sources, archiveSource{path: path, archivePath: archivePath, secret: secret})
+		return nil
+	}
+	for _, included := range paths {
+		dir := filepath.Join(root, included)
+		if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			return addFile(path)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed reading %s: %w", dir, err)
+		}
+	}
+	return sources, nil
+}
+
+// listState returns the files that make up the CLI state at [baseDir] and at
+// [projectDir], if not empty
+func listState(baseDir string, projectDir string, excludeSecrets bool) ([]archiveSource, error) {
+	// top level files such as the config file
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	topLevelFiles := []string{}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			topLevelFiles = append(topLevelFiles, entry.Name())
+		}
+	}
+	sources, err := listSources(baseDir, "", append(topLevelFiles, includedPaths...), excludeSecrets)
+	if err != nil {
+		return nil, err
+	}
+	if projectDir != "" {
+		projectSources, err := listSources(projectDir, projectArchiveDir, includedProjectPaths, excludeSecrets)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, projectSources...)
+	}
+	return sources, nil
+}
+
+// Create archives the CLI state found at [baseDir] into [outputPath]. Files are
+// streamed into the archive, which is only moved to [outputPath] once complete
+func Create(baseDir string, outputPath string, opts CreateOptions) (*Manifest, error) {
+	manifest := &Manifest{
+		FormatVersion:         FormatVersion,
+		CLIVersion:            opts.CLIVersion,
+		SidecarVersion:        constants.SidecarVersion,
+		ClustersConfigVersion: constants.ClustersConfigVersion,
+		CreatedAt:             time.Now().UTC(),
+		Encrypted:             opts.Passphrase != "",
+		ExcludesSecrets:       opts.ExcludeSecrets,
+		ProjectDir:            opts.ProjectDir,
+	}
+	sources, err := listState(baseDir, opts.ProjectDir, opts.ExcludeSecrets)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].archivePath < sources[j].archivePath })
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), constants.DefaultPerms755); err != nil {
+		return nil, err
+	}
+	// the archive may contain keys, so keep it private to the user.
+	// CreateTemp creates it with user only perms
+	outputFile, err := os.CreateTemp(filepath.Dir(outputPath), "."+filepath.Base(outputPath)+".*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(outputFile.Name())
+	defer outputFile.Close()
+	if err := writeArchive(outputFile, manifest, sources, opts.Passphrase); err != nil {
+		return nil, err
+	}
+	if err := outputFile.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(outputFile.Name(), outputPath); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// ReadManifest returns the manifest of the archive at [archivePath], after
+// verifying the checksums of its files
+func ReadManifest(archivePath string, passphrase string) (*Manifest, error) {
+	return verifyArchive(archivePath, passphrase)
+}
+
+// Restore extracts the archive at [archivePath] into [baseDir], and its project
+// files into the project dir.
+// It fails if the archive was produced by a newer CLI, or if any file it
+// would overwrite is newer than the backup, unless [opts.Force] is set.
+func Restore(archivePath string, baseDir string, opts RestoreOptions) (*Manifest, error) {
+	manifest, err := verifyArchive(archivePath, opts.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckCompatible(manifest); err != nil {
+		return nil, err
+	}
+	projectDir := opts.ProjectDir
+	if projectDir == "" {
+		projectDir = manifest.ProjectDir
+	}
+	targets := map[string]string{}
+	entries := map[string]FileEntry{}
+	for _, entry := range manifest.Files {
+		target, err := targetPath(baseDir, projectDir, entry.Path)
+		if err != nil {
+			return nil, err
+		}
+		targets[entry.Path] = target
+		entries[entry.Path] = entry
+	}
+	if !opts.Force {
+		if err := checkNotNewer(manifest, targets); err != nil {
+			return nil, err
+		}
+	}
+	err = readArchive(archivePath, opts.Passphrase, func(name string, r io.Reader) error {
+		entry, ok := entries[name]
+		if !ok {
+			return nil
+		}
+		return restoreFile(targets[name], entry, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opts.Prune {
+		if manifest.ProjectDir == "" {
+			projectDir = ""
+		}
+		if err := prune(manifest, baseDir, projectDir); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
+// prune removes the files of the state at [baseDir] and [projectDir] that are
+// not in [manifest], together with the dirs left empty
+func prune(manifest *Manifest, baseDir string, projectDir string) error {
+	sources, err := listState(baseDir, projectDir, manifest.ExcludesSecrets)
+	if err != nil {
+		return err
+	}
+	inBackup := map[string]bool{}
+	for _, entry := range manifest.Files {
+		inBackup[entry.Path] = true
+	}
+	for _, source := range sources {
+		if inBackup[source.archivePath] {
+			continue
+		}
+		if err := os.Remove(source.path); err != nil {
+			return err
+		}
+		root := baseDir
+		if strings.HasPrefix(source.archivePath, projectArchiveDir+"/") {
+			root = projectDir
+		}
+		// the top level dirs of the state are kept even if empty
+		for dir := filepath.Dir(source.path); filepath.Dir(dir) != filepath.Clean(root) && dir != filepath.Clean(root); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// restoreFile writes the contents of [entry] read from [r] into [target]. The
+// file is replaced only once its checksum is verified
+func restoreFile(target string, entry FileEntry, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	perms := os.FileMode(constants.WriteReadReadPerms)
+	if entry.Secret {
+		perms = constants.WriteReadUserOnlyPerms
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmpFile, hash), r); err != nil {
+		return err
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != entry.SHA256 {
+		return fmt.Errorf("checksum mismatch for %s in backup", entry.Path)
+	}
+	if err := tmpFile.Chmod(perms); err != nil {
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), target)
+}
+
+// targetPath returns where the archived file [relPath] is restored
+func targetPath(baseDir string, projectDir string, relPath string) (string, error) {
+	if projectPath, ok := strings.CutPrefix(relPath, projectArchiveDir+"/"); ok {
+		if projectDir == "" {
+			return "", fmt.Errorf("no project dir to restore %s into", relPath)
+		}
+		return sanitizePath(projectDir, projectPath)
+	}
+	return sanitizePath(baseDir, relPath)
+}
+
+// CheckCompatible verifies that the current CLI is able to handle
+// the state contained in a backup with the given manifest
+func CheckCompatible(manifest *Manifest) error {
+	if manifest.FormatVersion > FormatVersion {
+		return fmt.Errorf("%w: unsupported backup format version %d", ErrNewerBackup, manifest.FormatVersion)
+	}
+	if compareVersions(manifest.SidecarVersion, constants.SidecarVersion) > 0 {
+		return fmt.Errorf("%w: sidecar version %s, supported up to %s", ErrNewerBackup, manifest.SidecarVersion, constants.SidecarVersion)
+	}
+	if compareVersions(manifest.ClustersConfigVersion, constants.ClustersConfigVersion) > 0 {
+		return fmt.Errorf("%w: clusters config version %s, supported up to %s", ErrNewerBackup, manifest.ClustersConfigVersion, constants.ClustersConfigVersion)
+	}
+	return nil
+}
+
+// checkNotNewer fails if a file the backup would overwrite at [targets]
+// was modified after the backup was taken
+func checkNotNewer(manifest *Manifest, targets map[string]string) error {
+	for _, entry := range manifest.Files {
+		target := targets[entry.Path]
+		info, err := os.Stat(target)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(manifest.CreatedAt) {
+			sum, err := fileSHA256(target)
+			if err != nil {
+				return err
+			}
+			if sum != entry.SHA256 {
+				return fmt.Errorf("%w: %s was modified at %s", ErrNewerState, target, info.ModTime().Format(constants.TimeParseLayout))
+			}
+		}
+	}
+	return nil
+}
+
+// writeArchive streams [sources] into [w], followed by [manifest], which gets
+// their sizes and checksums
+func writeArchive(w io.Writer, manifest *Manifest, sources []archiveSource, passphrase string) error {
+	var encryptWriter io.WriteCloser
+	if passphrase != "" {
+		var err error
+		encryptWriter, err = newEncryptWriter(w, passphrase)
+		if err != nil {
+			return err
+		}
+		w = encryptWriter
+	}
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, source := range sources {
+		entry, err := writeFileEntry(tarWriter, manifest.CreatedAt, source)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{
+		Name:    ManifestFileName,
+		Mode:    constants.WriteReadReadPerms,
+		Size:    int64(len(manifestBytes)),
+		ModTime: manifest.CreatedAt,
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := tarWriter.Write(manifestBytes); err != nil {
+		return err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+	if encryptWriter != nil {
+		return encryptWriter.Close()
+	}
+	return nil
+}
+
+func writeFileEntry(tarWriter *tar.Writer, modTime time.Time, source archiveSource) (FileEntry, error) {
+	f, err := os.Open(source.path)
+	if err != nil {
+		return FileEntry{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return FileEntry{}, err
+	}
+	header := &tar.Header{
+		Name:    source.archivePath,
+		Mode:    constants.WriteReadReadPerms,
+		Size:    info.Size(),
+		ModTime: modTime,
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return FileEntry{}, err
+	}
+	hash := sha256.New()
+	if _, err := io.CopyN(tarWriter, io.TeeReader(f, hash), info.Size()); err != nil {
+		return FileEntry{}, fmt.Errorf("failed archiving %s, was it modified during the backup?: %w", source.path, err)
+	}
+	return FileEntry{
+		Path:   source.archivePath,
+		Size:   info.Size(),
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+		Secret: source.secret,
+	}, nil
+}
+
+// readArchive calls [f] with the name and contents of each entry of the
+// archive at [archivePath]
+func readArchive(archivePath string, passphrase string, f func(name string, r io.Reader) error) error {
+	archiveFile, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer archiveFile.Close()
+	r, err := decryptingReader(bufio.NewReader(archiveFile), passphrase)
+	if err != nil {
+		return fmt.Errorf("failed reading backup %s: %w", archivePath, err)
+	}
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed reading backup %s: %w", archivePath, err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed reading backup entry: %w", err)
+		}
+		if err := f(header.Name, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// verifyArchive returns the manifest of the archive at [archivePath], after
+// checking that the archive holds every file listed in it, unmodified
+func verifyArchive(archivePath string, passphrase string) (*Manifest, error) {
+	var manifest *Manifest
+	checksums := map[string]string{}
+	err := readArchive(archivePath, passphrase, func(name string, r io.Reader) error {
+		if name == ManifestFileName {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(r).Decode(manifest); err != nil {
+				return fmt.Errorf("invalid backup manifest: %w", err)
+			}
+			return nil
+		}
+		hash := sha256.New()
+		if _, err := io.Copy(hash, r); err != nil {
+			return err
+		}
+		checksums[name] = hex.EncodeToString(hash.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("backup %s has no manifest", archivePath)
+	}
+	for _, entry := range manifest.Files {
+		checksum, ok := checksums[entry.Path]
+		if !ok {
+			return nil, fmt.Errorf("backup is missing file %s listed in manifest", entry.Path)
+		}
+		if checksum != entry.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s in backup", entry.Path)
+		}
+	}
+	return manifest, nil
+}
+
+func sanitizePath(baseDir string, relPath string) (string, error) {
+	target := filepath.Join(baseDir, filepath.FromSlash(relPath))
+	if !strings.HasPrefix(target, filepath.Clean(baseDir)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid path in backup: %s", relPath)
+	}
+	return target, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// compareVersions compares dotted version strings such as sidecar
+// versions ("1.4.0") or clusters config versions ("1")
+func compareVersions(v1 string, v2 string) int {
+	return semver.Compare("v"+v1, "v"+v2)
+}
+
+// DefaultFileName returns a timestamped name for a new backup
+func DefaultFileName() string {
+	return fmt.Sprintf("avalanche-cli-backup-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+}
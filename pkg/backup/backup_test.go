@@ -0,0 +1,203 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backup
+
+import (
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestState(require *require.Assertions, baseDir string) {
+	files := map[string]string{
+		filepath.Join(constants.SubnetDir, "test", constants.SidecarFileName):   "{}",
+		filepath.Join(constants.KeyDir, "ewoq"+constants.KeySuffix):             "key",
+		filepath.Join(constants.NodesDir, "node1", constants.StakerKeyFileName): "staker",
+		filepath.Join(constants.NodesDir, constants.ClustersConfigFileName):     "{}",
+		filepath.Join(constants.LogDir, "avalanche.log"):                        "logs are not backed up",
+	}
+	for path, content := range files {
+		path = filepath.Join(baseDir, path)
+		require.NoError(os.MkdirAll(filepath.Dir(path), constants.DefaultPerms755))
+		require.NoError(os.WriteFile(path, []byte(content), constants.WriteReadReadPerms))
+	}
+}
+
+func TestCreateRestore(t *testing.T) {
+	require := require.New(t)
+	srcDir := t.TempDir()
+	writeTestState(require, srcDir)
+	archivePath := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	manifest, err := Create(srcDir, archivePath, CreateOptions{CLIVersion: "v1.0.0"})
+	require.NoError(err)
+	require.Len(manifest.Files, 4)
+
+	dstDir := t.TempDir()
+	restored, err := Restore(archivePath, dstDir, RestoreOptions{})
+	require.NoError(err)
+	require.Equal(manifest.Files, restored.Files)
+	bs, err := os.ReadFile(filepath.Join(dstDir, constants.KeyDir, "ewoq"+constants.KeySuffix))
+	require.NoError(err)
+	require.Equal("key", string(bs))
+	require.NoFileExists(filepath.Join(dstDir, constants.LogDir, "avalanche.log"))
+}
+
+func TestExcludeSecrets(t *testing.T) {
+	require := require.New(t)
+	srcDir := t.TempDir()
+	writeTestState(require, srcDir)
+	archivePath := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	manifest, err := Create(srcDir, archivePath, CreateOptions{ExcludeSecrets: true})
+	require.NoError(err)
+	require.Len(manifest.Files, 2)
+	for _, entry := range manifest.Files {
+		require.False(entry.Secret)
+	}
+}
+
+func TestEncryptedBackup(t *testing.T) {
+	require := require.New(t)
+	srcDir := t.TempDir()
+	writeTestState(require, srcDir)
+	archivePath := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	_, err := Create(srcDir, archivePath, CreateOptions{Passphrase: "secret"})
+	require.NoError(err)
+
+	_, err = ReadManifest(archivePath, "")
+	require.Error(err)
+	_, err = ReadManifest(archivePath, "wrong")
+	require.ErrorIs(err, ErrWrongPassphrase)
+	manifest, err := ReadManifest(archivePath, "secret")
+	require.NoError(err)
+	require.True(manifest.Encrypted)
+}
+
+func TestEncryptedBackupChunks(t *testing.T) {
+	require := require.New(t)
+	// sizes around the chunk boundaries, so that the last chunk is full,
+	// empty or partial once compressed
+	for _, size := range []int{0, chunkSize - 1, chunkSize, 3*chunkSize + 17} {
+		srcDir := t.TempDir()
+		content := make([]byte, size)
+		_, err := rand.Read(content)
+		require.NoError(err)
+		vmPath := filepath.Join(srcDir, constants.CustomVMDir, "vm")
+		require.NoError(os.MkdirAll(filepath.Dir(vmPath), constants.DefaultPerms755))
+		require.NoError(os.WriteFile(vmPath, content, constants.WriteReadReadPerms))
+		archivePath := filepath.Join(t.TempDir(), "backup.tar.gz")
+		_, err = Create(srcDir, archivePath, CreateOptions{Passphrase: "secret"})
+		require.NoError(err)
+
+		dstDir := t.TempDir()
+		_, err = Restore(archivePath, dstDir, RestoreOptions{Passphrase: "secret"})
+		require.NoError(err)
+		bs, err := os.ReadFile(filepath.Join(dstDir, constants.CustomVMDir, "vm"))
+		require.NoError(err)
+		require.Equal(content, bs)
+
+		// dropping the last chunk must not go unnoticed
+		archive, err := os.ReadFile(archivePath)
+		require.NoError(err)
+		headerLen := len(encryptedMagic) + saltLen + 7
+		lastChunkLen := (len(archive) - headerLen) % (chunkSize + 16)
+		truncatedPath := filepath.Join(t.TempDir(), "truncated.tar.gz")
+		require.NoError(os.WriteFile(truncatedPath, archive[:len(archive)-lastChunkLen], constants.WriteReadReadPerms))
+		_, err = ReadManifest(truncatedPath, "secret")
+		require.ErrorIs(err, errTruncated)
+	}
+}
+
+func TestProjectBackup(t *testing.T) {
+	require := require.New(t)
+	srcDir := t.TempDir()
+	writeTestState(require, srcDir)
+	projectDir := t.TempDir()
+	projectSidecar := filepath.Join(constants.SubnetDir, "project", constants.SidecarFileName)
+	require.NoError(os.MkdirAll(filepath.Join(projectDir, filepath.Dir(projectSidecar)), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(filepath.Join(projectDir, projectSidecar), []byte("{}"), constants.WriteReadReadPerms))
+	archivePath := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	manifest, err := Create(srcDir, archivePath, CreateOptions{ProjectDir: projectDir})
+	require.NoError(err)
+	require.Len(manifest.Files, 5)
+	require.True(manifest.HasProjectFiles())
+	require.Equal(projectDir, manifest.ProjectDir)
+
+	dstDir, dstProjectDir := t.TempDir(), t.TempDir()
+	_, err = Restore(archivePath, dstDir, RestoreOptions{ProjectDir: dstProjectDir})
+	require.NoError(err)
+	require.FileExists(filepath.Join(dstProjectDir, projectSidecar))
+	require.NoFileExists(filepath.Join(dstDir, projectSidecar))
+	require.NoFileExists(filepath.Join(dstDir, projectArchiveDir, projectSidecar))
+}
+
+func TestRefuseNewerState(t *testing.T) {
+	require := require.New(t)
+	srcDir := t.TempDir()
+	writeTestState(require, srcDir)
+	archivePath := filepath.Join(t.TempDir(), "backup.tar.gz")
+	_, err := Create(srcDir, archivePath, CreateOptions{})
+	require.NoError(err)
+
+	sidecarPath := filepath.Join(srcDir, constants.SubnetDir, "test", constants.SidecarFileName)
+	require.NoError(os.WriteFile(sidecarPath, []byte(`{"Name": "test"}`), constants.WriteReadReadPerms))
+	future := time.Now().Add(time.Hour)
+	require.NoError(os.Chtimes(sidecarPath, future, future))
+
+	_, err = Restore(archivePath, srcDir, RestoreOptions{})
+	require.ErrorIs(err, ErrNewerState)
+	_, err = Restore(archivePath, srcDir, RestoreOptions{Force: true})
+	require.NoError(err)
+}
+
+func TestRestorePrune(t *testing.T) {
+	require := require.New(t)
+	baseDir := t.TempDir()
+	writeTestState(require, baseDir)
+	archivePath := filepath.Join(t.TempDir(), "backup.tar.gz")
+	_, err := Create(baseDir, archivePath, CreateOptions{ExcludeSecrets: true})
+	require.NoError(err)
+
+	addedSidecar := filepath.Join(baseDir, constants.SubnetDir, "added", constants.SidecarFileName)
+	require.NoError(os.MkdirAll(filepath.Dir(addedSidecar), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(addedSidecar, []byte("{}"), constants.WriteReadReadPerms))
+	topLevelFile := filepath.Join(baseDir, "added.json")
+	require.NoError(os.WriteFile(topLevelFile, []byte("{}"), constants.WriteReadReadPerms))
+
+	_, err = Restore(archivePath, baseDir, RestoreOptions{})
+	require.NoError(err)
+	require.FileExists(addedSidecar)
+	_, err = Restore(archivePath, baseDir, RestoreOptions{Prune: true})
+	require.NoError(err)
+	require.NoFileExists(addedSidecar)
+	require.NoDirExists(filepath.Dir(addedSidecar))
+	require.NoFileExists(topLevelFile)
+	require.FileExists(filepath.Join(baseDir, constants.SubnetDir, "test", constants.SidecarFileName))
+	// secrets left out of the backup are not pruned
+	require.FileExists(filepath.Join(baseDir, constants.KeyDir, "ewoq"+constants.KeySuffix))
+	// nor is what is not part of the state
+	require.FileExists(filepath.Join(baseDir, constants.LogDir, "avalanche.log"))
+}
+
+func TestCheckCompatible(t *testing.T) {
+	require := require.New(t)
+	require.NoError(CheckCompatible(&Manifest{
+		FormatVersion:         FormatVersion,
+		SidecarVersion:        "1.0.0",
+		ClustersConfigVersion: constants.ClustersConfigVersion,
+	}))
+	require.ErrorIs(CheckCompatible(&Manifest{
+		FormatVersion:         FormatVersion,
+		SidecarVersion:        "99.0.0",
+		ClustersConfigVersion: constants.ClustersConfigVersion,
+	}), ErrNewerBackup)
+	require.ErrorIs(CheckCompatible(&Manifest{FormatVersion: FormatVersion + 1}), ErrNewerBackup)
+}
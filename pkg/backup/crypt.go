@@ -0,0 +1,234 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	saltLen = 16
+	keyLen  = 32
+	// scrypt parameters recommended for interactive logins
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	// size of the plaintext sealed in each chunk of an encrypted archive
+	chunkSize = 64 * 1024
+	// the nonce of each chunk is a random prefix, followed by the chunk
+	// index and by a flag set on the last chunk
+	nonceCounterLen = 4
+	nonceFlagLen    = 1
+)
+
+var (
+	// encrypted archives start with this header, followed by salt, nonce prefix
+	// and the sealed chunks
+	encryptedMagic = []byte("AVACLI-BACKUP-ENC1")
+
+	ErrWrongPassphrase = errors.New("failed decrypting backup: wrong passphrase or corrupted file")
+	errTruncated       = errors.New("encrypted backup is truncated")
+)
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkCipher seals and opens the chunks of an encrypted archive. As the
+// nonce of a chunk commits to its index and to being the last one, reordered
+// or truncated archives fail to decrypt
+type chunkCipher struct {
+	gcm         cipher.AEAD
+	noncePrefix []byte
+	counter     uint32
+}
+
+func (c *chunkCipher) nextNonce(last bool) ([]byte, error) {
+	if c.counter == math.MaxUint32 {
+		return nil, errors.New("backup is too large to be encrypted")
+	}
+	nonce := make([]byte, 0, c.gcm.NonceSize())
+	nonce = append(nonce, c.noncePrefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, c.counter)
+	if last {
+		nonce = append(nonce, 1)
+	} else {
+		nonce = append(nonce, 0)
+	}
+	c.counter++
+	return nonce, nil
+}
+
+type encryptWriter struct {
+	w      io.Writer
+	cipher *chunkCipher
+	buf    []byte
+}
+
+// newEncryptWriter returns a writer that encrypts everything written to it
+// into [w]. It must be closed to seal the last chunk
+func newEncryptWriter(w io.Writer, passphrase string) (io.WriteCloser, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	noncePrefix := make([]byte, gcm.NonceSize()-nonceCounterLen-nonceFlagLen)
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return nil, err
+	}
+	header := append([]byte{}, encryptedMagic...)
+	header = append(header, salt...)
+	header = append(header, noncePrefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{
+		w:      w,
+		cipher: &chunkCipher{gcm: gcm, noncePrefix: noncePrefix},
+		buf:    make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(chunkSize-len(e.buf), len(p))
+		e.buf = append(e.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		// full chunks are sealed right away, so the last chunk is always
+		// shorter than the others
+		if len(e.buf) == chunkSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(last bool) error {
+	nonce, err := e.cipher.nextNonce(last)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(e.cipher.gcm.Seal(nil, nonce, e.buf, encryptedMagic)); err != nil {
+		return err
+	}
+	e.buf = e.buf[:0]
+	return nil
+}
+
+type decryptReader struct {
+	r      io.Reader
+	cipher *chunkCipher
+	chunk  []byte
+	buf    []byte
+	done   bool
+}
+
+// newDecryptReader returns a reader of the plaintext of the encrypted archive
+// read from [r], past its magic header
+func newDecryptReader(r io.Reader, passphrase string) (io.Reader, error) {
+	salt := make([]byte, saltLen)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return nil, errTruncated
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	noncePrefix := make([]byte, gcm.NonceSize()-nonceCounterLen-nonceFlagLen)
+	if _, err := io.ReadFull(r, noncePrefix); err != nil {
+		return nil, errTruncated
+	}
+	return &decryptReader{
+		r:      r,
+		cipher: &chunkCipher{gcm: gcm, noncePrefix: noncePrefix},
+		chunk:  make([]byte, chunkSize+gcm.Overhead()),
+	}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.r, d.chunk)
+	last := false
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case errors.Is(err, io.EOF):
+		// the last chunk is missing
+		return errTruncated
+	case err != nil:
+		return err
+	}
+	nonce, err := d.cipher.nextNonce(last)
+	if err != nil {
+		return err
+	}
+	plaintext, err := d.cipher.gcm.Open(d.chunk[:0], nonce, d.chunk[:n], encryptedMagic)
+	if err != nil {
+		return ErrWrongPassphrase
+	}
+	d.buf = plaintext
+	d.done = last
+	return nil
+}
+
+// decryptingReader returns a reader of the plaintext of the archive read from
+// [r], decrypting it with [passphrase] if it is encrypted
+func decryptingReader(r *bufio.Reader, passphrase string) (io.Reader, error) {
+	header, err := r.Peek(len(encryptedMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if !bytes.Equal(header, encryptedMagic) {
+		return r, nil
+	}
+	if passphrase == "" {
+		return nil, errors.New("backup is encrypted, a passphrase is required")
+	}
+	if _, err := r.Discard(len(encryptedMagic)); err != nil {
+		return nil, err
+	}
+	return newDecryptReader(r, passphrase)
+}
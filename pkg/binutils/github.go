@@ -4,7 +4,10 @@
 package binutils
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
+	"text/template"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 )
@@ -23,13 +26,19 @@ type GithubDownloader interface {
 }
 
 type (
-	subnetEVMDownloader   struct{}
-	avalancheGoDownloader struct{}
+	subnetEVMDownloader     struct{}
+	avalancheGoDownloader   struct{}
+	githubReleaseDownloader struct {
+		org   string
+		repo  string
+		asset string
+	}
 )
 
 var (
 	_ GithubDownloader = (*subnetEVMDownloader)(nil)
 	_ GithubDownloader = (*avalancheGoDownloader)(nil)
+	_ GithubDownloader = (*githubReleaseDownloader)(nil)
 )
 
 func GetGithubLatestReleaseURL(org, repo string) string {
@@ -122,3 +131,41 @@ func (subnetEVMDownloader) GetDownloadURL(version string, installer Installer) (
 
 	return subnetEVMURL, ext, nil
 }
+
+// GithubReleaseAsset holds the values a release asset name template can use
+type GithubReleaseAsset struct {
+	Version string
+	// VersionNumber is the version without the v prefix
+	VersionNumber string
+	OS            string
+	Arch          string
+}
+
+// NewGithubReleaseDownloader downloads the tar.gz release asset of org/repo
+// named by the [asset] text/template, which is rendered with a
+// [GithubReleaseAsset]
+func NewGithubReleaseDownloader(org, repo, asset string) GithubDownloader {
+	return &githubReleaseDownloader{org: org, repo: repo, asset: asset}
+}
+
+func (d githubReleaseDownloader) GetDownloadURL(version string, installer Installer) (string, string, error) {
+	goarch, goos := installer.GetArch()
+	if goos != linux && goos != darwin {
+		return "", "", fmt.Errorf("OS not supported: %s", goos)
+	}
+	tmpl, err := template.New("asset").Option("missingkey=error").Parse(d.asset)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid release asset %q: %w", d.asset, err)
+	}
+	var asset bytes.Buffer
+	if err := tmpl.Execute(&asset, GithubReleaseAsset{
+		Version:       version,
+		VersionNumber: strings.TrimPrefix(version, "v"),
+		OS:            goos,
+		Arch:          goarch,
+	}); err != nil {
+		return "", "", fmt.Errorf("invalid release asset %q: %w", d.asset, err)
+	}
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", d.org, d.repo, version, asset.String())
+	return url, tarExtension, nil
+}
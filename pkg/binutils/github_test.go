@@ -119,3 +119,45 @@ func TestGetDownloadURL_SubnetEVM(t *testing.T) {
 		require.Equal(tt.expectedErr, err)
 	}
 }
+
+func TestGetDownloadURL_GithubRelease(t *testing.T) {
+	tests := []urlTest{
+		{
+			version:     "v0.0.15",
+			goarch:      "amd64",
+			goos:        "linux",
+			expectedURL: "https://github.com/ava-labs/hypersdk/releases/download/v0.0.15/morpheusvm_0.0.15_linux_amd64.tar.gz",
+			expectedExt: tarExtension,
+			expectedErr: nil,
+		},
+		{
+			version:     "v0.0.16",
+			goarch:      "arm64",
+			goos:        "darwin",
+			expectedURL: "https://github.com/ava-labs/hypersdk/releases/download/v0.0.16/morpheusvm_0.0.16_darwin_arm64.tar.gz",
+			expectedExt: tarExtension,
+			expectedErr: nil,
+		},
+		{
+			version:     "v1.2.3",
+			goarch:      "riscv",
+			goos:        "solaris",
+			expectedURL: "",
+			expectedExt: "",
+			expectedErr: errors.New("OS not supported: solaris"),
+		},
+	}
+
+	for _, tt := range tests {
+		require := require.New(t)
+		mockInstaller := &mocks.Installer{}
+		mockInstaller.On("GetArch").Return(tt.goarch, tt.goos)
+
+		downloader := NewGithubReleaseDownloader("ava-labs", "hypersdk", "morpheusvm_{{.VersionNumber}}_{{.OS}}_{{.Arch}}.tar.gz")
+
+		url, ext, err := downloader.GetDownloadURL(tt.version, mockInstaller)
+		require.Equal(tt.expectedURL, url)
+		require.Equal(tt.expectedExt, ext)
+		require.Equal(tt.expectedErr, err)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package binutils
+
+import (
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+)
+
+// SetupGithubReleaseVM installs [version] of the VM template [name] from the
+// GitHub releases of org/repo, if not installed yet, and returns the version
+// and the path of [binary] inside the release archive
+func SetupGithubReleaseVM(
+	app *application.Avalanche,
+	name string,
+	org string,
+	repo string,
+	asset string,
+	binary string,
+	version string,
+) (string, string, error) {
+	binDir := app.GetVMTemplateBinDir(name)
+	binPrefix := name + "-"
+	subDir := filepath.Join(binDir, binPrefix+version)
+
+	installer := NewInstaller()
+	downloader := NewGithubReleaseDownloader(org, repo, asset)
+	version, vmDir, err := InstallBinary(
+		app,
+		version,
+		binDir,
+		subDir,
+		binPrefix,
+		org,
+		repo,
+		downloader,
+		installer,
+	)
+	return version, filepath.Join(vmDir, binary), err
+}
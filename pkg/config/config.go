@@ -72,6 +72,10 @@ func (*Config) GetConfigStringValue(key string) string {
 	return viper.GetString(key)
 }
 
+func (*Config) GetConfigStringSliceValue(key string) []string {
+	return viper.GetStringSlice(key)
+}
+
 func (*Config) LoadNodeConfig() (string, error) {
 	globalConfigs := viper.GetStringMap(constants.ConfigNodeConfigKey)
 	if len(globalConfigs) == 0 {
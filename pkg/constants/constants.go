@@ -16,6 +16,10 @@ const (
 	BaseDirName = ".avalanche-cli"
 	LogDir      = "logs"
 
+	// a project dir holds subnet state local to a repository, and is
+	// discovered by walking up from the working directory
+	ProjectDirName = ".avalanche"
+
 	ServerRunFile      = "gRPCserver.run"
 	AvalancheCliBinDir = "bin"
 	RunDir             = "runs"
@@ -25,6 +29,7 @@ const (
 	SidecarFileName              = "sidecar.json"
 	GenesisFileName              = "genesis.json"
 	ElasticSubnetConfigFileName  = "elastic_subnet_config.json"
+	DeployHooksFileName          = "deploy_hooks.json"
 	SidecarSuffix                = SuffixSeparator + SidecarFileName
 	GenesisSuffix                = SuffixSeparator + GenesisFileName
 	NodeFileName                 = "node.json"
@@ -40,7 +45,14 @@ const (
 	StakerCertFileName           = "staker.crt"
 	StakerKeyFileName            = "staker.key"
 	BLSKeyFileName               = "signer.key"
-	SidecarVersion               = "1.4.0"
+	SidecarVersion               = "1.5.0"
+	NodeConfigVersion            = "1"
+	ElasticSubnetConfigVersion   = "1"
+	MigrationsRecordFileName     = "migrations.json"
+	MigrationSnapshotsDir        = "migration-snapshots"
+	MaxMigrationSnapshots        = 5
+	APITokenFileName             = "api-token"
+	DefaultAPIServerAddress      = "127.0.0.1:8181"
 
 	MaxLogFileSize   = 4
 	MaxNumOfLogFiles = 5
@@ -183,6 +195,10 @@ const (
 	ConfigMetricsEnabledKey       = "MetricsEnabled"
 	ConfigAuthorizeCloudAccessKey = "AuthorizeCloudAccess"
 	ConfigSingleNodeEnabledKey    = "SingleNodeEnabled"
+	ConfigGenesisTemplateDirsKey  = "GenesisTemplateDirs"
+	GenesisTemplatesDir           = "genesis-templates"
+	ConfigVMTemplateDirsKey       = "VMTemplateDirs"
+	VMTemplatesDir                = "vm-templates"
 	OldConfigFileName             = ".avalanche-cli.json"
 	OldMetricsConfigFileName      = ".avalanche-cli/config"
 	DefaultConfigFileName         = ".avalanche-cli/config.json"
@@ -209,6 +225,7 @@ const (
 	CloudNodeSubnetEvmBinaryPath  = "/home/ubuntu/.avalanchego/plugins/%s"
 	CloudNodeStakingPath          = "/home/ubuntu/.avalanchego/staking/"
 	CloudNodeConfigPath           = "/home/ubuntu/.avalanchego/configs/"
+	CloudNodeChainConfigPath      = "/home/ubuntu/.avalanchego/configs/chains/"
 	CloudNodePrometheusConfigPath = "/etc/prometheus/prometheus.yml"
 	CloudNodeCLIConfigBasePath    = "/home/ubuntu/.avalanche-cli/"
 	AvalanchegoMonitoringPort     = 9090
@@ -268,6 +285,7 @@ const (
 
 	UpgradeBytesFileName      = "upgrade.json"
 	UpgradeBytesLockExtension = ".lock"
+	UpgradeRehearsalFileName  = "upgrade_rehearsal.json"
 	NotAvailableLabel         = "Not available"
 	BackendCmd                = "avalanche-cli-backend"
 
@@ -0,0 +1,214 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package deployhooks
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	ewoqKeyName = "ewoq"
+	// maxOutputLen bounds the hook output recorded in the sidecar
+	maxOutputLen = 4096
+)
+
+// DeployInfo describes the deploy the hooks run for. It is given to
+// executable hooks both as env vars and as JSON in their stdin
+type DeployInfo struct {
+	SubnetName                 string `json:"subnetName"`
+	Network                    string `json:"network"`
+	Stage                      string `json:"stage"`
+	SubnetID                   string `json:"subnetID,omitempty"`
+	BlockchainID               string `json:"blockchainID,omitempty"`
+	RPCURL                     string `json:"rpcURL,omitempty"`
+	TeleporterMessengerAddress string `json:"teleporterMessengerAddress,omitempty"`
+	TeleporterRegistryAddress  string `json:"teleporterRegistryAddress,omitempty"`
+}
+
+// Env returns [info] as AVALANCHE_* env vars
+func (info DeployInfo) Env() []string {
+	return []string{
+		"AVALANCHE_SUBNET_NAME=" + info.SubnetName,
+		"AVALANCHE_NETWORK=" + info.Network,
+		"AVALANCHE_DEPLOY_STAGE=" + info.Stage,
+		"AVALANCHE_SUBNET_ID=" + info.SubnetID,
+		"AVALANCHE_BLOCKCHAIN_ID=" + info.BlockchainID,
+		"AVALANCHE_RPC_URL=" + info.RPCURL,
+		"AVALANCHE_TELEPORTER_MESSENGER_ADDRESS=" + info.TeleporterMessengerAddress,
+		"AVALANCHE_TELEPORTER_REGISTRY_ADDRESS=" + info.TeleporterRegistryAddress,
+	}
+}
+
+// Run runs the hooks of [info.Stage] that apply to [network], in order, and
+// returns their results. It stops at the first failing hook not marked
+// ContinueOnError, returning the results so far together with the error
+func Run(
+	app *application.Avalanche,
+	network models.Network,
+	hooks []models.DeployHook,
+	info DeployInfo,
+) ([]models.DeployHookResult, error) {
+	results := []models.DeployHookResult{}
+	for _, hook := range hooks {
+		if !appliesTo(hook, network) {
+			continue
+		}
+		ux.Logger.PrintToUser("Running %s hook %s", info.Stage, hook.Name)
+		result := models.DeployHookResult{
+			Name:  hook.Name,
+			Stage: info.Stage,
+			Time:  time.Now().UTC(),
+		}
+		var err error
+		switch {
+		case hook.Command != "":
+			err = runCommand(hook, info, &result)
+		case hook.DeployContract != nil:
+			err = deployContract(app, network, hook.DeployContract, info, &result)
+		case hook.Fund != nil:
+			err = fund(app, network, hook.Fund, info)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+		if err != nil {
+			if !hook.ContinueOnError {
+				return results, fmt.Errorf("%s hook %s failed: %w", info.Stage, hook.Name, err)
+			}
+			ux.Logger.PrintToUser("%s hook %s failed, continuing: %s", info.Stage, hook.Name, err)
+		}
+	}
+	return results, nil
+}
+
+// appliesTo tells if [hook] runs on [network], given either by name or by
+// kind (eg. Devnet matches all devnets)
+func appliesTo(hook models.DeployHook, network models.Network) bool {
+	return len(hook.Networks) == 0 ||
+		slices.Contains(hook.Networks, network.Name()) ||
+		slices.Contains(hook.Networks, network.Kind.String())
+}
+
+func runCommand(hook models.DeployHook, info DeployInfo, result *models.DeployHookResult) error {
+	infoBytes, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	var stdout bytes.Buffer
+	cmd := exec.Command(hook.Command, hook.Args...) //nolint:gosec
+	cmd.Env = append(os.Environ(), info.Env()...)
+	cmd.Stdin = bytes.NewReader(infoBytes)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	result.Output = strings.TrimSpace(stdout.String())
+	if len(result.Output) > maxOutputLen {
+		result.Output = result.Output[:maxOutputLen] + "..."
+	}
+	if result.Output != "" {
+		ux.Logger.PrintToUser(result.Output)
+	}
+	return err
+}
+
+func deployContract(
+	app *application.Avalanche,
+	network models.Network,
+	action *models.DeployContractAction,
+	info DeployInfo,
+	result *models.DeployHookResult,
+) error {
+	bytecode := action.Bytecode
+	if action.BytecodePath != "" {
+		bytecodeBytes, err := os.ReadFile(action.BytecodePath)
+		if err != nil {
+			return err
+		}
+		bytecode = string(bytecodeBytes)
+	}
+	bytecode = strings.TrimSpace(bytecode)
+	if len(bytecode) == 0 {
+		return fmt.Errorf("empty contract bytecode")
+	}
+	privateKey, err := loadPrivateKey(app, network, action.Key)
+	if err != nil {
+		return err
+	}
+	client, err := evm.GetClient(info.RPCURL)
+	if err != nil {
+		return err
+	}
+	address, txHash, err := evm.DeployContract(client, privateKey, common.FromHex(bytecode))
+	if txHash != (common.Hash{}) {
+		result.TxHash = txHash.Hex()
+	}
+	if err != nil {
+		return err
+	}
+	result.ContractAddress = address.Hex()
+	ux.Logger.PrintToUser("Contract deployed at %s", result.ContractAddress)
+	return nil
+}
+
+func fund(
+	app *application.Avalanche,
+	network models.Network,
+	action *models.FundAction,
+	info DeployInfo,
+) error {
+	amount, ok := new(big.Int).SetString(action.Amount, 10)
+	if !ok {
+		return fmt.Errorf("invalid amount %q: expected an integer amount of wei", action.Amount)
+	}
+	privateKey, err := loadPrivateKey(app, network, action.Key)
+	if err != nil {
+		return err
+	}
+	client, err := evm.GetClient(info.RPCURL)
+	if err != nil {
+		return err
+	}
+	for _, address := range action.Addresses {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid address %q", address)
+		}
+		if err := evm.FundAddress(client, privateKey, address, amount); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Funded %s with %s wei", address, amount)
+	}
+	return nil
+}
+
+func loadPrivateKey(app *application.Avalanche, network models.Network, keyName string) (string, error) {
+	var (
+		k   *key.SoftKey
+		err error
+	)
+	if keyName == ewoqKeyName {
+		k, err = key.LoadEwoq(network.ID)
+	} else {
+		k, err = key.LoadSoft(network.ID, app.GetKeyPath(keyName))
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(k.Raw()), nil
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package deployhooks
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommandHooks(t *testing.T) {
+	require := require.New(t)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+	app := &application.Avalanche{}
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+
+	stdinPath := filepath.Join(t.TempDir(), "stdin.json")
+	hooks := []models.DeployHook{
+		{
+			Name:    "env",
+			Command: "sh",
+			Args:    []string{"-c", `cat > "$0"; echo "$AVALANCHE_BLOCKCHAIN_ID $AVALANCHE_RPC_URL"`, stdinPath},
+		},
+		{
+			Name:     "fuji only",
+			Command:  "sh",
+			Args:     []string{"-c", "echo fuji"},
+			Networks: []string{models.Fuji.String()},
+		},
+		{
+			Name:            "failing",
+			Command:         "sh",
+			Args:            []string{"-c", "exit 1"},
+			ContinueOnError: true,
+		},
+		{
+			Name:     "local only",
+			Command:  "sh",
+			Args:     []string{"-c", "echo local"},
+			Networks: []string{models.Local.String()},
+		},
+	}
+	info := DeployInfo{
+		SubnetName:   "test",
+		Network:      models.NewLocalNetwork().Name(),
+		Stage:        models.PostDeployStage,
+		BlockchainID: "blockchain",
+		RPCURL:       "http://127.0.0.1:9650/ext/bc/blockchain/rpc",
+	}
+	results, err := Run(app, models.NewLocalNetwork(), hooks, info)
+	require.NoError(err)
+	require.Len(results, 3)
+	require.Equal("env", results[0].Name)
+	require.Equal(models.PostDeployStage, results[0].Stage)
+	require.Equal("blockchain http://127.0.0.1:9650/ext/bc/blockchain/rpc", results[0].Output)
+	require.Empty(results[0].Error)
+	require.Equal("failing", results[1].Name)
+	require.NotEmpty(results[1].Error)
+	require.Equal("local", results[2].Output)
+
+	stdinBytes, err := os.ReadFile(stdinPath)
+	require.NoError(err)
+	var stdinInfo DeployInfo
+	require.NoError(json.Unmarshal(stdinBytes, &stdinInfo))
+	require.Equal(info, stdinInfo)
+
+	// without continueOnError the failure stops the remaining hooks
+	hooks[2].ContinueOnError = false
+	results, err = Run(app, models.NewLocalNetwork(), hooks, info)
+	require.ErrorContains(err, "failing")
+	require.Len(results, 2)
+}
@@ -0,0 +1,204 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package diff compares JSON documents semantically: key order, number
+// formatting, hex casing and the order of lists of plain values are ignored.
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change is a single difference between two documents. Old is nil for
+// additions and New is nil for removals
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Old  interface{}
+	New  interface{}
+}
+
+// Decode parses a JSON document keeping numbers as json.Number, so that big
+// values are not rounded
+func Decode(data []byte) (interface{}, error) {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// JSON returns the changes needed to go from document [a] to document [b]
+func JSON(a, b []byte) ([]Change, error) {
+	va, err := Decode(a)
+	if err != nil {
+		return nil, err
+	}
+	vb, err := Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	return Values(va, vb), nil
+}
+
+// Values returns the changes needed to go from [a] to [b], both being
+// decoded JSON values
+func Values(a, b interface{}) []Change {
+	changes := []Change{}
+	compare("", a, b, &changes)
+	return changes
+}
+
+func compare(path string, a, b interface{}, changes *[]Change) {
+	switch va := a.(type) {
+	case map[string]interface{}:
+		if vb, ok := b.(map[string]interface{}); ok {
+			compareMaps(path, va, vb, changes)
+			return
+		}
+	case []interface{}:
+		if vb, ok := b.([]interface{}); ok {
+			compareLists(path, va, vb, changes)
+			return
+		}
+	}
+	if !scalarEqual(a, b) {
+		*changes = append(*changes, Change{Path: path, Kind: Changed, Old: a, New: b})
+	}
+}
+
+func compareMaps(path string, a, b map[string]interface{}, changes *[]Change) {
+	keys := map[string]struct{}{}
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+	for _, k := range sortedKeys {
+		childPath := k
+		if path != "" {
+			childPath = path + "." + k
+		}
+		va, inA := a[k]
+		vb, inB := b[k]
+		switch {
+		case inA && !inB:
+			*changes = append(*changes, Change{Path: childPath, Kind: Removed, Old: va})
+		case !inA && inB:
+			*changes = append(*changes, Change{Path: childPath, Kind: Added, New: vb})
+		default:
+			compare(childPath, va, vb, changes)
+		}
+	}
+}
+
+// compareLists treats lists of plain values (eg. allow list addresses) as
+// sets, and compares other lists element by element
+func compareLists(path string, a, b []interface{}, changes *[]Change) {
+	if allScalars(a) && allScalars(b) {
+		for _, va := range a {
+			if !containsScalar(b, va) {
+				*changes = append(*changes, Change{Path: path + "[]", Kind: Removed, Old: va})
+			}
+		}
+		for _, vb := range b {
+			if !containsScalar(a, vb) {
+				*changes = append(*changes, Change{Path: path + "[]", Kind: Added, New: vb})
+			}
+		}
+		return
+	}
+	for i := 0; i < len(a) || i < len(b); i++ {
+		childPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(b):
+			*changes = append(*changes, Change{Path: childPath, Kind: Removed, Old: a[i]})
+		case i >= len(a):
+			*changes = append(*changes, Change{Path: childPath, Kind: Added, New: b[i]})
+		default:
+			compare(childPath, a[i], b[i], changes)
+		}
+	}
+}
+
+func allScalars(list []interface{}) bool {
+	for _, v := range list {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
+
+func containsScalar(list []interface{}, v interface{}) bool {
+	for _, e := range list {
+		if scalarEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func scalarEqual(a, b interface{}) bool {
+	if na, ok := a.(json.Number); ok {
+		if nb, ok := b.(json.Number); ok {
+			ra, okA := new(big.Rat).SetString(na.String())
+			rb, okB := new(big.Rat).SetString(nb.String())
+			if okA && okB {
+				return ra.Cmp(rb) == 0
+			}
+		}
+	}
+	if sa, ok := a.(string); ok {
+		if sb, ok := b.(string); ok {
+			if isHex(sa) && isHex(sb) {
+				return strings.EqualFold(sa, sb)
+			}
+			return sa == sb
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func isHex(s string) bool {
+	return strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")
+}
+
+// FormatValue renders a decoded value as compact JSON. Strings are shown
+// without quotes
+func FormatValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
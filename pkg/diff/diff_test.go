@@ -0,0 +1,94 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected []Change
+	}{
+		{
+			name:     "identical",
+			a:        `{"a":1,"b":{"c":"x"}}`,
+			b:        `{"b":{"c":"x"},"a":1}`,
+			expected: []Change{},
+		},
+		{
+			name:     "number formatting is ignored",
+			a:        `{"gasLimit":8000000,"fee":1.50}`,
+			b:        `{"gasLimit":8e6,"fee":1.5}`,
+			expected: []Change{},
+		},
+		{
+			name:     "hex casing is ignored",
+			a:        `{"admin":"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"}`,
+			b:        `{"admin":"0x8db97c7cece249c2b98bdc0226cc4c2a57bf52fc"}`,
+			expected: []Change{},
+		},
+		{
+			name: "nested change, addition and removal",
+			a:    `{"config":{"feeConfig":{"gasLimit":8000000,"minBaseFee":25000000000},"warpConfig":{}}}`,
+			b:    `{"config":{"feeConfig":{"gasLimit":15000000,"minBaseFee":25000000000},"txAllowListConfig":{}}}`,
+			expected: []Change{
+				{Path: "config.feeConfig.gasLimit", Kind: Changed, Old: json.Number("8000000"), New: json.Number("15000000")},
+				{Path: "config.txAllowListConfig", Kind: Added, New: map[string]interface{}{}},
+				{Path: "config.warpConfig", Kind: Removed, Old: map[string]interface{}{}},
+			},
+		},
+		{
+			name: "lists of values are sets",
+			a:    `{"adminAddresses":["0x1","0x2"]}`,
+			b:    `{"adminAddresses":["0x3","0x1"]}`,
+			expected: []Change{
+				{Path: "adminAddresses[]", Kind: Removed, Old: "0x2"},
+				{Path: "adminAddresses[]", Kind: Added, New: "0x3"},
+			},
+		},
+		{
+			name: "lists of objects are compared by index",
+			a:    `{"precompileUpgrades":[{"blockTimestamp":1}]}`,
+			b:    `{"precompileUpgrades":[{"blockTimestamp":2},{"blockTimestamp":3}]}`,
+			expected: []Change{
+				{Path: "precompileUpgrades[0].blockTimestamp", Kind: Changed, Old: json.Number("1"), New: json.Number("2")},
+				{Path: "precompileUpgrades[1]", Kind: Added, New: map[string]interface{}{"blockTimestamp": json.Number("3")}},
+			},
+		},
+		{
+			name: "type change",
+			a:    `{"a":"1"}`,
+			b:    `{"a":1}`,
+			expected: []Change{
+				{Path: "a", Kind: Changed, Old: "1", New: json.Number("1")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := JSON([]byte(tt.a), []byte(tt.b))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, changes)
+		})
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	_, err := JSON([]byte(`{`), []byte(`{}`))
+	require.Error(t, err)
+}
+
+func TestFormatValue(t *testing.T) {
+	require.Equal(t, "", FormatValue(nil))
+	require.Equal(t, "0xabc", FormatValue("0xabc"))
+	require.Equal(t, "12", FormatValue(json.Number("12")))
+	require.Equal(t, `{"a":[true]}`, FormatValue(map[string]interface{}{"a": []interface{}{true}}))
+}
@@ -0,0 +1,175 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package elasticsubnet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the elastic subnet config given with subnet elastic --config.
+// Rates, fees and uptime are percentages, and durations use the d and w units
+// on top of the go ones. Omitted parameters take the mainnet values
+type ConfigFile struct {
+	TokenName                string         `yaml:"tokenName"`
+	TokenSymbol              string         `yaml:"tokenSymbol"`
+	Denomination             *int           `yaml:"denomination"`
+	InitialSupply            uint64         `yaml:"initialSupply"`
+	MaxSupply                uint64         `yaml:"maxSupply"`
+	MinConsumptionRate       float64        `yaml:"minConsumptionRate"`
+	MaxConsumptionRate       float64        `yaml:"maxConsumptionRate"`
+	MinValidatorStake        uint64         `yaml:"minValidatorStake"`
+	MaxValidatorStake        uint64         `yaml:"maxValidatorStake"`
+	MinStakeDuration         string         `yaml:"minStakeDuration"`
+	MaxStakeDuration         string         `yaml:"maxStakeDuration"`
+	MinDelegationFee         float64        `yaml:"minDelegationFee"`
+	MinDelegatorStake        uint64         `yaml:"minDelegatorStake"`
+	MaxValidatorWeightFactor uint64         `yaml:"maxValidatorWeightFactor"`
+	UptimeRequirement        float64        `yaml:"uptimeRequirement"`
+	Preview                  []StakePreview `yaml:"preview"`
+}
+
+// StakePreview is a validator, and its delegators, to project the rewards of
+type StakePreview struct {
+	Stake    uint64 `yaml:"stake"`
+	Duration string `yaml:"duration"`
+	// DelegationFee is a percentage. Defaults to the min delegation fee
+	DelegationFee *float64           `yaml:"delegationFee"`
+	Delegators    []DelegatorPreview `yaml:"delegators"`
+}
+
+// DelegatorPreview is a delegator to project the rewards of
+type DelegatorPreview struct {
+	Stake    uint64 `yaml:"stake"`
+	Duration string `yaml:"duration"`
+}
+
+// LoadConfigFile reads the elastic subnet config file at [path]
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	configBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &ConfigFile{
+		InitialSupply:            defaultInitialSupply,
+		MaxSupply:                defaultMaximumSupply,
+		MinConsumptionRate:       defaultMinConsumptionRate * 100,
+		MaxConsumptionRate:       defaultMaxConsumptionRate * 100,
+		MinValidatorStake:        defaultMinValidatorStake,
+		MaxValidatorStake:        defaultMaxValidatorStake,
+		MinStakeDuration:         defaultMinStakeDuration.String(),
+		MaxStakeDuration:         defaultMaxStakeDuration.String(),
+		MinDelegationFee:         float64(defaultMinDelegationFee) * 100 / reward.PercentDenominator,
+		MinDelegatorStake:        defaultMinDelegatorStake,
+		MaxValidatorWeightFactor: defaultMaxValidatorWeightFactor,
+		UptimeRequirement:        defaultUptimeRequirement * 100,
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(configBytes))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("invalid elastic subnet config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ElasticSubnetConfig converts the file parameters into the units of the
+// TransformSubnetTx, and validates them against the P-Chain constraints
+func (c *ConfigFile) ElasticSubnetConfig() (models.ElasticSubnetConfig, error) {
+	minConsumptionRate, err := percentToDenominator("minConsumptionRate", c.MinConsumptionRate)
+	if err != nil {
+		return models.ElasticSubnetConfig{}, err
+	}
+	maxConsumptionRate, err := percentToDenominator("maxConsumptionRate", c.MaxConsumptionRate)
+	if err != nil {
+		return models.ElasticSubnetConfig{}, err
+	}
+	minDelegationFee, err := percentToDenominator("minDelegationFee", c.MinDelegationFee)
+	if err != nil {
+		return models.ElasticSubnetConfig{}, err
+	}
+	uptimeRequirement, err := percentToDenominator("uptimeRequirement", c.UptimeRequirement)
+	if err != nil {
+		return models.ElasticSubnetConfig{}, err
+	}
+	minStakeDuration, err := utils.ParseDuration(c.MinStakeDuration)
+	if err != nil {
+		return models.ElasticSubnetConfig{}, fmt.Errorf("invalid minStakeDuration: %w", err)
+	}
+	maxStakeDuration, err := utils.ParseDuration(c.MaxStakeDuration)
+	if err != nil {
+		return models.ElasticSubnetConfig{}, fmt.Errorf("invalid maxStakeDuration: %w", err)
+	}
+	if c.MaxValidatorWeightFactor > math.MaxUint8 {
+		return models.ElasticSubnetConfig{}, fmt.Errorf("maxValidatorWeightFactor must be less than or equal to %d", math.MaxUint8)
+	}
+	config := models.ElasticSubnetConfig{
+		InitialSupply:            c.InitialSupply,
+		MaxSupply:                c.MaxSupply,
+		MinConsumptionRate:       minConsumptionRate,
+		MaxConsumptionRate:       maxConsumptionRate,
+		MinValidatorStake:        c.MinValidatorStake,
+		MaxValidatorStake:        c.MaxValidatorStake,
+		MinStakeDuration:         minStakeDuration,
+		MaxStakeDuration:         maxStakeDuration,
+		MinDelegationFee:         uint32(minDelegationFee),
+		MinDelegatorStake:        c.MinDelegatorStake,
+		MaxValidatorWeightFactor: byte(c.MaxValidatorWeightFactor),
+		UptimeRequirement:        uint32(uptimeRequirement),
+	}
+	return config, ValidateConfig(config)
+}
+
+func percentToDenominator(name string, percent float64) (uint64, error) {
+	if percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("%s must be a percentage between 0 and 100", name)
+	}
+	return uint64(math.Round(percent * reward.PercentDenominator / 100)), nil
+}
+
+// ValidateConfig checks [config] against the constraints the P-Chain
+// enforces on TransformSubnetTx
+func ValidateConfig(config models.ElasticSubnetConfig) error {
+	switch {
+	case config.InitialSupply == 0:
+		return errors.New("initial supply must be non-0")
+	case config.InitialSupply > config.MaxSupply:
+		return errors.New("initial supply can't be greater than maximum supply")
+	case config.MinConsumptionRate > config.MaxConsumptionRate:
+		return errors.New("min consumption rate must be less than or equal to max consumption rate")
+	case config.MaxConsumptionRate > reward.PercentDenominator:
+		return errors.New("max consumption rate must be less than or equal to 100%")
+	case config.MinValidatorStake == 0:
+		return errors.New("min validator stake must be non-0")
+	case config.MinValidatorStake > config.InitialSupply:
+		return errors.New("min validator stake must be less than or equal to initial supply")
+	case config.MinValidatorStake > config.MaxValidatorStake:
+		return errors.New("min validator stake must be less than or equal to max validator stake")
+	case config.MaxValidatorStake > config.MaxSupply:
+		return errors.New("max validator stake must be less than or equal to max supply")
+	case config.MinStakeDuration < time.Second:
+		return errors.New("min stake duration must be at least one second")
+	case config.MinStakeDuration > config.MaxStakeDuration:
+		return errors.New("min stake duration must be less than or equal to max stake duration")
+	case config.MaxStakeDuration > defaultMaxStakeDuration:
+		return fmt.Errorf("max stake duration must be less than or equal to the global max stake duration of %s", defaultMaxStakeDuration)
+	case config.MinDelegationFee > reward.PercentDenominator:
+		return errors.New("min delegation fee must be less than or equal to 100%")
+	case config.MinDelegatorStake == 0:
+		return errors.New("min delegator stake must be non-0")
+	case config.MaxValidatorWeightFactor == 0:
+		return errors.New("max validator weight factor must be non-0")
+	case config.UptimeRequirement > reward.PercentDenominator:
+		return errors.New("uptime requirement must be less than or equal to 100%")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package elasticsubnet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "elastic.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	require := require.New(t)
+	configFile, err := LoadConfigFile(writeConfigFile(t, `
+tokenSymbol: MTK
+denomination: 9
+minConsumptionRate: 5
+maxConsumptionRate: 12.5
+minStakeDuration: 1w
+minDelegationFee: 3
+preview:
+  - stake: 10000
+    duration: 30d
+    delegators:
+      - stake: 500
+        duration: 2w
+`))
+	require.NoError(err)
+	require.Equal("MTK", configFile.TokenSymbol)
+	require.Equal(9, *configFile.Denomination)
+	require.Len(configFile.Preview, 1)
+
+	config, err := configFile.ElasticSubnetConfig()
+	require.NoError(err)
+	require.Equal(uint64(5*reward.PercentDenominator/100), config.MinConsumptionRate)
+	require.Equal(uint64(12.5*reward.PercentDenominator/100), config.MaxConsumptionRate)
+	require.Equal(7*24*time.Hour, config.MinStakeDuration)
+	require.Equal(uint32(3*reward.PercentDenominator/100), config.MinDelegationFee)
+	// omitted parameters take the mainnet values
+	require.Equal(uint64(defaultInitialSupply), config.InitialSupply)
+	require.Equal(defaultMaxStakeDuration, config.MaxStakeDuration)
+	require.Equal(uint32(defaultUptimeRequirement*reward.PercentDenominator), config.UptimeRequirement)
+
+	_, err = LoadConfigFile(writeConfigFile(t, "maxSupply: 1000\nsupplyCap: 1000\n"))
+	require.ErrorContains(err, "supplyCap")
+
+	for name, content := range map[string]string{
+		"supply above max":        "initialSupply: 1000\nmaxSupply: 999\nminValidatorStake: 1\nmaxValidatorStake: 2",
+		"min rate above max":      "minConsumptionRate: 13",
+		"rate above 100":          "maxConsumptionRate: 101",
+		"min stake above supply":  "initialSupply: 1000\nminValidatorStake: 1001",
+		"max stake above supply":  "maxSupply: 240000000\nmaxValidatorStake: 240000001",
+		"min duration above max":  "minStakeDuration: 30d\nmaxStakeDuration: 2w",
+		"max duration above 365d": "maxStakeDuration: 366d",
+		"zero delegator stake":    "minDelegatorStake: 0",
+		"zero weight factor":      "maxValidatorWeightFactor: 0",
+		"weight factor above 255": "maxValidatorWeightFactor: 256",
+		"invalid duration":        "minStakeDuration: two weeks",
+	} {
+		configFile, err := LoadConfigFile(writeConfigFile(t, content))
+		require.NoError(err, name)
+		_, err = configFile.ElasticSubnetConfig()
+		require.Error(err, name)
+	}
+}
+
+func TestProjectRewards(t *testing.T) {
+	require := require.New(t)
+	configFile, err := LoadConfigFile(writeConfigFile(t, "minDelegationFee: 10\n"))
+	require.NoError(err)
+	config, err := configFile.ElasticSubnetConfig()
+	require.NoError(err)
+
+	fee := 20.0
+	projections, supply, err := ProjectRewards(config, []StakePreview{
+		{Stake: 2_000, Duration: "365d"},
+		{Stake: 2_000, Duration: "365d", DelegationFee: &fee, Delegators: []DelegatorPreview{{Stake: 2_000, Duration: "365d"}}},
+	})
+	require.NoError(err)
+	require.Len(projections, 2)
+	// a year long stake gets the max consumption rate of the remaining supply
+	// proportionally to the stake share of the current supply
+	expected := uint64(2_000 * (defaultMaximumSupply - defaultInitialSupply) / defaultInitialSupply * defaultMaxConsumptionRate)
+	require.InDelta(expected, projections[0].Reward, 1)
+	require.Equal(uint32(config.MinDelegationFee), projections[0].DelegationFee)
+	// later stakers see a larger supply, so slightly smaller rewards
+	require.Less(projections[1].Reward, projections[0].Reward)
+	require.Len(projections[1].Delegators, 1)
+	delegatorGross := projections[1].DelegationFeeReward + projections[1].Delegators[0].Reward
+	require.InDelta(float64(delegatorGross)*0.2, projections[1].DelegationFeeReward, 1)
+	require.Equal(config.InitialSupply+projections[0].Reward+projections[1].Reward+delegatorGross, supply)
+
+	for name, preview := range map[string][]StakePreview{
+		"stake below min":         {{Stake: 1_000, Duration: "30d"}},
+		"duration below min":      {{Stake: 2_000, Duration: "1d"}},
+		"fee below min":           {{Stake: 2_000, Duration: "30d", DelegationFee: new(float64)}},
+		"delegator below min":     {{Stake: 2_000, Duration: "30d", Delegators: []DelegatorPreview{{Stake: 1, Duration: "30d"}}}},
+		"delegator longer":        {{Stake: 2_000, Duration: "30d", Delegators: []DelegatorPreview{{Stake: 25, Duration: "31d"}}}},
+		"delegation above weight": {{Stake: 2_000, Duration: "30d", Delegators: []DelegatorPreview{{Stake: 8_001, Duration: "30d"}}}},
+	} {
+		_, _, err := ProjectRewards(config, preview)
+		require.Error(err, name)
+	}
+
+	defaultProjections, _, err := ProjectRewards(config, DefaultStakePreview(config))
+	require.NoError(err)
+	require.Len(defaultProjections, 4)
+	// the max stake validators are at their max weight and can't be delegated to
+	require.Len(defaultProjections[0].Delegators, 1)
+	require.Empty(defaultProjections[3].Delegators)
+}
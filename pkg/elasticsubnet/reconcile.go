@@ -0,0 +1,136 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package elasticsubnet
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+const (
+	ValidatorInSync      = "in sync"
+	ValidatorNotOnChain  = "not validating on-chain"
+	ValidatorNotRecorded = "not in local records"
+	ValidatorTxMismatch  = "different tx on-chain"
+)
+
+// ConfigFromTransformTx returns the elastic subnet config set by [tx]
+func ConfigFromTransformTx(tx *txs.TransformSubnetTx) models.ElasticSubnetConfig {
+	return models.ElasticSubnetConfig{
+		SubnetID:                 tx.Subnet,
+		AssetID:                  tx.AssetID,
+		InitialSupply:            tx.InitialSupply,
+		MaxSupply:                tx.MaximumSupply,
+		MinConsumptionRate:       tx.MinConsumptionRate,
+		MaxConsumptionRate:       tx.MaxConsumptionRate,
+		MinValidatorStake:        tx.MinValidatorStake,
+		MaxValidatorStake:        tx.MaxValidatorStake,
+		MinStakeDuration:         time.Duration(tx.MinStakeDuration) * time.Second,
+		MaxStakeDuration:         time.Duration(tx.MaxStakeDuration) * time.Second,
+		MinDelegationFee:         tx.MinDelegationFee,
+		MinDelegatorStake:        tx.MinDelegatorStake,
+		MaxValidatorWeightFactor: tx.MaxValidatorWeightFactor,
+		UptimeRequirement:        tx.UptimeRequirement,
+	}
+}
+
+// DiffConfigs describes the staking parameters that differ between the
+// [local] elastic subnet config and the one on [chain]
+func DiffConfigs(local models.ElasticSubnetConfig, chain models.ElasticSubnetConfig) []string {
+	diffs := []string{}
+	add := func(name string, localValue, chainValue interface{}) {
+		if localValue != chainValue {
+			diffs = append(diffs, fmt.Sprintf("%s is %v locally and %v on-chain", name, localValue, chainValue))
+		}
+	}
+	add("asset ID", local.AssetID, chain.AssetID)
+	add("initial supply", local.InitialSupply, chain.InitialSupply)
+	add("max supply", local.MaxSupply, chain.MaxSupply)
+	add("min consumption rate", local.MinConsumptionRate, chain.MinConsumptionRate)
+	add("max consumption rate", local.MaxConsumptionRate, chain.MaxConsumptionRate)
+	add("min validator stake", local.MinValidatorStake, chain.MinValidatorStake)
+	add("max validator stake", local.MaxValidatorStake, chain.MaxValidatorStake)
+	add("min stake duration", local.MinStakeDuration, chain.MinStakeDuration)
+	add("max stake duration", local.MaxStakeDuration, chain.MaxStakeDuration)
+	add("min delegation fee", local.MinDelegationFee, chain.MinDelegationFee)
+	add("min delegator stake", local.MinDelegatorStake, chain.MinDelegatorStake)
+	add("max validator weight factor", local.MaxValidatorWeightFactor, chain.MaxValidatorWeightFactor)
+	add("uptime requirement", local.UptimeRequirement, chain.UptimeRequirement)
+	return diffs
+}
+
+// ValidatorReconciliation compares the local record of a permissionless
+// validator with the chain state
+type ValidatorReconciliation struct {
+	NodeID    string
+	LocalTxID ids.ID
+	ChainTxID ids.ID
+	Status    string
+}
+
+// IsPermissionless tells apart the permissionless validators of an elastic
+// subnet from the permissioned ones added before the transformation
+func IsPermissionless(validator platformvm.ClientPermissionlessValidator) bool {
+	return validator.ValidationRewardOwner != nil
+}
+
+// ReconcileValidators compares the permissionless validators recorded in the
+// sidecar with the current [validators] of the subnet, sorted by node ID
+func ReconcileValidators(
+	records map[string]models.PermissionlessValidators,
+	validators []platformvm.ClientPermissionlessValidator,
+) []ValidatorReconciliation {
+	onChain := map[string]ids.ID{}
+	for _, validator := range validators {
+		if IsPermissionless(validator) {
+			onChain[validator.NodeID.String()] = validator.TxID
+		}
+	}
+	reconciliation := []ValidatorReconciliation{}
+	for nodeID, record := range records {
+		r := ValidatorReconciliation{NodeID: nodeID, LocalTxID: record.TxID}
+		chainTxID, ok := onChain[nodeID]
+		switch {
+		case !ok:
+			r.Status = ValidatorNotOnChain
+		case chainTxID != record.TxID:
+			r.ChainTxID = chainTxID
+			r.Status = ValidatorTxMismatch
+		default:
+			r.ChainTxID = chainTxID
+			r.Status = ValidatorInSync
+		}
+		reconciliation = append(reconciliation, r)
+	}
+	for nodeID, chainTxID := range onChain {
+		if _, ok := records[nodeID]; !ok {
+			reconciliation = append(reconciliation, ValidatorReconciliation{
+				NodeID:    nodeID,
+				ChainTxID: chainTxID,
+				Status:    ValidatorNotRecorded,
+			})
+		}
+	}
+	sort.Slice(reconciliation, func(i, j int) bool {
+		return reconciliation[i].NodeID < reconciliation[j].NodeID
+	})
+	return reconciliation
+}
+
+// ReconciledValidators returns the validator records matching [validators]
+func ReconciledValidators(validators []platformvm.ClientPermissionlessValidator) map[string]models.PermissionlessValidators {
+	records := map[string]models.PermissionlessValidators{}
+	for _, validator := range validators {
+		if IsPermissionless(validator) {
+			records[validator.NodeID.String()] = models.PermissionlessValidators{TxID: validator.TxID}
+		}
+	}
+	return records
+}
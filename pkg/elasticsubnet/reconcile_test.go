@@ -0,0 +1,102 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package elasticsubnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFromTransformTx(t *testing.T) {
+	require := require.New(t)
+	local := models.ElasticSubnetConfig{
+		SubnetID:                 ids.GenerateTestID(),
+		AssetID:                  ids.GenerateTestID(),
+		InitialSupply:            defaultInitialSupply,
+		MaxSupply:                defaultMaximumSupply,
+		MinConsumptionRate:       100_000,
+		MaxConsumptionRate:       120_000,
+		MinValidatorStake:        defaultMinValidatorStake,
+		MaxValidatorStake:        defaultMaxValidatorStake,
+		MinStakeDuration:         defaultMinStakeDuration,
+		MaxStakeDuration:         defaultMaxStakeDuration,
+		MinDelegationFee:         defaultMinDelegationFee,
+		MinDelegatorStake:        defaultMinDelegatorStake,
+		MaxValidatorWeightFactor: defaultMaxValidatorWeightFactor,
+		UptimeRequirement:        800_000,
+	}
+	tx := &txs.TransformSubnetTx{
+		Subnet:                   local.SubnetID,
+		AssetID:                  local.AssetID,
+		InitialSupply:            local.InitialSupply,
+		MaximumSupply:            local.MaxSupply,
+		MinConsumptionRate:       local.MinConsumptionRate,
+		MaxConsumptionRate:       local.MaxConsumptionRate,
+		MinValidatorStake:        local.MinValidatorStake,
+		MaxValidatorStake:        local.MaxValidatorStake,
+		MinStakeDuration:         uint32(local.MinStakeDuration / time.Second),
+		MaxStakeDuration:         uint32(local.MaxStakeDuration / time.Second),
+		MinDelegationFee:         local.MinDelegationFee,
+		MinDelegatorStake:        local.MinDelegatorStake,
+		MaxValidatorWeightFactor: local.MaxValidatorWeightFactor,
+		UptimeRequirement:        local.UptimeRequirement,
+	}
+	chain := ConfigFromTransformTx(tx)
+	require.Equal(local, chain)
+	require.Empty(DiffConfigs(local, chain))
+
+	tx.MinDelegatorStake++
+	tx.MaxStakeDuration--
+	diffs := DiffConfigs(local, ConfigFromTransformTx(tx))
+	require.Len(diffs, 2)
+	require.Contains(diffs[0], "max stake duration")
+	require.Contains(diffs[1], "min delegator stake")
+}
+
+func TestReconcileValidators(t *testing.T) {
+	require := require.New(t)
+	owner := &platformvm.ClientOwner{}
+	inSync, mismatch, notRecorded, notOnChain, permissioned := ids.GenerateTestNodeID(),
+		ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	inSyncTxID, localTxID, chainTxID, newTxID, goneTxID := ids.GenerateTestID(),
+		ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+
+	validators := []platformvm.ClientPermissionlessValidator{
+		{ClientStaker: platformvm.ClientStaker{NodeID: inSync, TxID: inSyncTxID}, ValidationRewardOwner: owner},
+		{ClientStaker: platformvm.ClientStaker{NodeID: mismatch, TxID: chainTxID}, ValidationRewardOwner: owner},
+		{ClientStaker: platformvm.ClientStaker{NodeID: notRecorded, TxID: newTxID}, ValidationRewardOwner: owner},
+		{ClientStaker: platformvm.ClientStaker{NodeID: permissioned, TxID: ids.GenerateTestID()}},
+	}
+	records := map[string]models.PermissionlessValidators{
+		inSync.String():     {TxID: inSyncTxID},
+		mismatch.String():   {TxID: localTxID},
+		notOnChain.String(): {TxID: goneTxID},
+	}
+
+	statuses := map[string]ValidatorReconciliation{}
+	reconciliation := ReconcileValidators(records, validators)
+	for i, r := range reconciliation {
+		if i > 0 {
+			require.Less(reconciliation[i-1].NodeID, r.NodeID)
+		}
+		statuses[r.NodeID] = r
+	}
+	require.Len(statuses, 4)
+	require.Equal(ValidatorReconciliation{inSync.String(), inSyncTxID, inSyncTxID, ValidatorInSync}, statuses[inSync.String()])
+	require.Equal(ValidatorReconciliation{mismatch.String(), localTxID, chainTxID, ValidatorTxMismatch}, statuses[mismatch.String()])
+	require.Equal(ValidatorReconciliation{notRecorded.String(), ids.Empty, newTxID, ValidatorNotRecorded}, statuses[notRecorded.String()])
+	require.Equal(ValidatorReconciliation{notOnChain.String(), goneTxID, ids.Empty, ValidatorNotOnChain}, statuses[notOnChain.String()])
+
+	require.Equal(map[string]models.PermissionlessValidators{
+		inSync.String():      {TxID: inSyncTxID},
+		mismatch.String():    {TxID: chainTxID},
+		notRecorded.String(): {TxID: newTxID},
+	}, ReconciledValidators(validators))
+}
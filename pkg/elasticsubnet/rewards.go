@@ -0,0 +1,145 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package elasticsubnet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+)
+
+// ValidatorRewards is the projected reward of a validator, and of its delegators
+type ValidatorRewards struct {
+	Stake         uint64
+	Duration      time.Duration
+	DelegationFee uint32
+	// Reward is the reward for the validator own stake
+	Reward uint64
+	// DelegationFeeReward is the share of the delegator rewards the validator gets
+	DelegationFeeReward uint64
+	Delegators          []DelegatorRewards
+}
+
+// DelegatorRewards is the projected reward of a delegator, net of the delegation fee
+type DelegatorRewards struct {
+	Stake    uint64
+	Duration time.Duration
+	Reward   uint64
+}
+
+// DefaultStakePreview returns validators staking the min and max validator
+// stakes for the min and max stake durations, each with a min stake delegator
+func DefaultStakePreview(config models.ElasticSubnetConfig) []StakePreview {
+	preview := []StakePreview{}
+	for _, stake := range []uint64{config.MinValidatorStake, config.MaxValidatorStake} {
+		for _, duration := range []time.Duration{config.MinStakeDuration, config.MaxStakeDuration} {
+			delegators := []DelegatorPreview{}
+			// delegators can only be added while the validator is below its max weight
+			if stake+config.MinDelegatorStake <= maxValidatorWeight(config, stake) {
+				delegators = append(delegators, DelegatorPreview{Stake: config.MinDelegatorStake, Duration: duration.String()})
+			}
+			preview = append(preview, StakePreview{Stake: stake, Duration: duration.String(), Delegators: delegators})
+		}
+	}
+	return preview
+}
+
+// maxValidatorWeight is the max weight the validator with [stake], plus its
+// delegators, can reach
+func maxValidatorWeight(config models.ElasticSubnetConfig, stake uint64) uint64 {
+	maxWeight, err := math.Mul64(uint64(config.MaxValidatorWeightFactor), stake)
+	if err != nil {
+		maxWeight = config.MaxValidatorStake
+	}
+	if maxWeight > config.MaxValidatorStake {
+		maxWeight = config.MaxValidatorStake
+	}
+	return maxWeight
+}
+
+// ProjectRewards computes the rewards the P-Chain would assign to the
+// validators and delegators of [preview], if they were added in order right
+// after the transformation. As on the P-Chain, the reward of each staker
+// depends on the supply left after the rewards of the previous ones
+func ProjectRewards(config models.ElasticSubnetConfig, preview []StakePreview) ([]ValidatorRewards, uint64, error) {
+	calculator := reward.NewCalculator(reward.Config{
+		MaxConsumptionRate: config.MaxConsumptionRate,
+		MinConsumptionRate: config.MinConsumptionRate,
+		MintingPeriod:      genesis.MainnetParams.RewardConfig.MintingPeriod,
+		SupplyCap:          config.MaxSupply,
+	})
+	supply := config.InitialSupply
+	projections := []ValidatorRewards{}
+	for i, validator := range preview {
+		duration, err := parseStakeDuration(config, validator.Duration)
+		if err != nil {
+			return nil, 0, fmt.Errorf("validator %d: %w", i+1, err)
+		}
+		if validator.Stake < config.MinValidatorStake || validator.Stake > config.MaxValidatorStake {
+			return nil, 0, fmt.Errorf("validator %d: stake must be between %d and %d", i+1, config.MinValidatorStake, config.MaxValidatorStake)
+		}
+		delegationFee := config.MinDelegationFee
+		if validator.DelegationFee != nil {
+			fee, err := percentToDenominator("delegationFee", *validator.DelegationFee)
+			if err != nil {
+				return nil, 0, fmt.Errorf("validator %d: %w", i+1, err)
+			}
+			if fee < uint64(config.MinDelegationFee) {
+				return nil, 0, fmt.Errorf("validator %d: delegation fee must be at least the min delegation fee", i+1)
+			}
+			delegationFee = uint32(fee)
+		}
+		projection := ValidatorRewards{
+			Stake:         validator.Stake,
+			Duration:      duration,
+			DelegationFee: delegationFee,
+			Reward:        calculator.Calculate(duration, validator.Stake, supply),
+		}
+		supply += projection.Reward
+		weight := validator.Stake
+		for j, delegator := range validator.Delegators {
+			delegatorDuration, err := parseStakeDuration(config, delegator.Duration)
+			if err != nil {
+				return nil, 0, fmt.Errorf("validator %d, delegator %d: %w", i+1, j+1, err)
+			}
+			if delegatorDuration > duration {
+				return nil, 0, fmt.Errorf("validator %d, delegator %d: can't delegate for longer than the validator validates", i+1, j+1)
+			}
+			if delegator.Stake < config.MinDelegatorStake {
+				return nil, 0, fmt.Errorf("validator %d, delegator %d: stake must be at least %d", i+1, j+1, config.MinDelegatorStake)
+			}
+			weight += delegator.Stake
+			if weight > maxValidatorWeight(config, validator.Stake) {
+				return nil, 0, fmt.Errorf("validator %d, delegator %d: the validator weight would exceed %d", i+1, j+1, maxValidatorWeight(config, validator.Stake))
+			}
+			delegatorReward := calculator.Calculate(delegatorDuration, delegator.Stake, supply)
+			supply += delegatorReward
+			feeReward, netReward := reward.Split(delegatorReward, delegationFee)
+			projection.DelegationFeeReward += feeReward
+			projection.Delegators = append(projection.Delegators, DelegatorRewards{
+				Stake:    delegator.Stake,
+				Duration: delegatorDuration,
+				Reward:   netReward,
+			})
+		}
+		projections = append(projections, projection)
+	}
+	return projections, supply, nil
+}
+
+func parseStakeDuration(config models.ElasticSubnetConfig, durationStr string) (time.Duration, error) {
+	duration, err := utils.ParseDuration(durationStr)
+	if err != nil {
+		return 0, err
+	}
+	if duration < config.MinStakeDuration || duration > config.MaxStakeDuration {
+		return 0, fmt.Errorf("duration must be between %s and %s", config.MinStakeDuration, config.MaxStakeDuration)
+	}
+	return duration, nil
+}
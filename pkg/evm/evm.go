@@ -9,6 +9,8 @@ import (
 	"github.com/ava-labs/subnet-evm/accounts/abi/bind"
 	"github.com/ava-labs/subnet-evm/core/types"
 	"github.com/ava-labs/subnet-evm/ethclient"
+	"github.com/ava-labs/subnet-evm/interfaces"
+	"github.com/ava-labs/subnet-evm/params"
 	"github.com/ava-labs/subnet-evm/rpc"
 	subnetEvmUtils "github.com/ava-labs/subnet-evm/tests/utils"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -126,6 +128,63 @@ func FundAddress(
 	return nil
 }
 
+// DeployContract deploys the creation bytecode [bytecode], paid by the
+// account of [privateKeyStr], and returns the contract address together
+// with the deploy tx hash
+func DeployContract(
+	client ethclient.Client,
+	privateKeyStr string,
+	bytecode []byte,
+) (common.Address, common.Hash, error) {
+	privateKey, err := crypto.HexToECDSA(privateKeyStr)
+	if err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	gasFeeCap, gasTipCap, nonce, err := CalculateTxParams(client, address.Hex())
+	if err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+	gas, err := client.EstimateGas(ctx, interfaces.CallMsg{
+		From:      address,
+		GasFeeCap: gasFeeCap,
+		GasTipCap: gasTipCap,
+		Data:      bytecode,
+	})
+	if err != nil {
+		return common.Address{}, common.Hash{}, fmt.Errorf("failure estimating contract deploy gas: %w", err)
+	}
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		Gas:       gas,
+		GasFeeCap: gasFeeCap,
+		GasTipCap: gasTipCap,
+		Data:      bytecode,
+	})
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), privateKey)
+	if err != nil {
+		return common.Address{}, common.Hash{}, err
+	}
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		return common.Address{}, signedTx.Hash(), err
+	}
+	receipt, success, err := WaitForTransaction(client, signedTx)
+	if err != nil {
+		return common.Address{}, signedTx.Hash(), err
+	}
+	if !success {
+		return common.Address{}, signedTx.Hash(), fmt.Errorf("failure deploying contract from %s", address.Hex())
+	}
+	return receipt.ContractAddress, signedTx.Hash(), nil
+}
+
 func ActivateProposerVM(
 	client ethclient.Client,
 	privateKeyStr string,
@@ -227,6 +286,40 @@ func GetTrace(rpcURL string, txID string) (map[string]interface{}, error) {
 	return result, err
 }
 
+// GetChainConfig returns the chain config of the chain at [rpcURL], including
+// the upgrades its node has loaded
+func GetChainConfig(rpcURL string) (*params.ChainConfigWithUpgradesJSON, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	client, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	var result params.ChainConfigWithUpgradesJSON
+	if err := client.CallContext(ctx, &result, "eth_getChainConfig"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetActivePrecompiles returns the precompile configs active at [timestamp] on
+// the chain at [rpcURL], or at the last accepted block if [timestamp] is nil
+func GetActivePrecompiles(rpcURL string, timestamp *uint64) (params.Precompiles, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	client, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	result := params.Precompiles{}
+	if err := client.CallContext(ctx, &result, "eth_getActivePrecompilesAt", timestamp); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func SetupProposerVM(
 	endpoint string,
 	privKeyStr string,
@@ -0,0 +1,121 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	PreDeployStage  = "pre-deploy"
+	PostDeployStage = "post-deploy"
+)
+
+// DeployHooks declares the actions run before and after a subnet deploy
+type DeployHooks struct {
+	PreDeploy  []DeployHook `json:"preDeploy,omitempty"`
+	PostDeploy []DeployHook `json:"postDeploy,omitempty"`
+}
+
+// DeployHook is either an executable or a built-in action. Exactly one of
+// Command, DeployContract and Fund is set
+type DeployHook struct {
+	Name string `json:"name"`
+	// Command runs an executable, which receives the deploy info both as
+	// AVALANCHE_* env vars and as JSON in its stdin
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+	// DeployContract deploys a contract into the deployed chain
+	DeployContract *DeployContractAction `json:"deployContract,omitempty"`
+	// Fund sends native tokens of the deployed chain to some addresses
+	Fund *FundAction `json:"fund,omitempty"`
+	// Networks restricts the hook to these networks, given by name or kind
+	// (eg. Local Network, Fuji, Devnet). The hook runs on all networks if empty
+	Networks []string `json:"networks,omitempty"`
+	// ContinueOnError keeps going with the remaining hooks, and with the
+	// deploy itself for pre-deploy hooks, if the hook fails
+	ContinueOnError bool `json:"continueOnError,omitempty"`
+}
+
+// DeployContractAction deploys a contract paid by a stored key
+type DeployContractAction struct {
+	// Bytecode is the hex encoded creation bytecode, including any ABI
+	// encoded constructor args
+	Bytecode string `json:"bytecode,omitempty"`
+	// BytecodePath reads the hex encoded bytecode from a file instead
+	BytecodePath string `json:"bytecodePath,omitempty"`
+	// Key is the name of the stored key paying for the deploy
+	Key string `json:"key"`
+}
+
+// FundAction sends [Amount] wei to each address, paid by a stored key
+type FundAction struct {
+	Addresses []string `json:"addresses"`
+	Amount    string   `json:"amount"`
+	Key       string   `json:"key"`
+}
+
+// DeployHookResult records the outcome of a hook run
+type DeployHookResult struct {
+	Name  string
+	Stage string
+	Time  time.Time
+	Error string `json:",omitempty"`
+	// Output is the stdout of an executable hook, truncated if too long
+	Output          string `json:",omitempty"`
+	ContractAddress string `json:",omitempty"`
+	TxHash          string `json:",omitempty"`
+}
+
+// Validate checks that each hook is well formed. Built-in actions act on the
+// deployed chain, so they can't be pre-deploy hooks
+func (h *DeployHooks) Validate() error {
+	for _, hook := range h.PreDeploy {
+		if err := hook.validate(); err != nil {
+			return err
+		}
+		if hook.Command == "" {
+			return fmt.Errorf("pre-deploy hook %q: only executables can run before the chain is deployed", hook.Name)
+		}
+	}
+	for _, hook := range h.PostDeploy {
+		if err := hook.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h DeployHook) validate() error {
+	if h.Name == "" {
+		return errors.New("hooks must have a name")
+	}
+	actions := 0
+	if h.Command != "" {
+		actions++
+	}
+	if h.DeployContract != nil {
+		actions++
+		if (h.DeployContract.Bytecode == "") == (h.DeployContract.BytecodePath == "") {
+			return fmt.Errorf("hook %q: exactly one of bytecode and bytecodePath must be given", h.Name)
+		}
+		if h.DeployContract.Key == "" {
+			return fmt.Errorf("hook %q: a key must pay for the contract deploy", h.Name)
+		}
+	}
+	if h.Fund != nil {
+		actions++
+		if len(h.Fund.Addresses) == 0 || h.Fund.Amount == "" {
+			return fmt.Errorf("hook %q: addresses and amount are required to fund", h.Name)
+		}
+		if h.Fund.Key == "" {
+			return fmt.Errorf("hook %q: a key must pay for the funding", h.Name)
+		}
+	}
+	if actions != 1 {
+		return fmt.Errorf("hook %q must have exactly one of command, deployContract and fund", h.Name)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDeployHooks(t *testing.T) {
+	require := require.New(t)
+	valid := DeployHooks{
+		PreDeploy: []DeployHook{{Name: "pre", Command: "true"}},
+		PostDeploy: []DeployHook{
+			{Name: "deploy", DeployContract: &DeployContractAction{Bytecode: "0x00", Key: "key"}},
+			{Name: "fund", Fund: &FundAction{Addresses: []string{"0x00"}, Amount: "1", Key: "key"}},
+		},
+	}
+	require.NoError(valid.Validate())
+
+	for name, hooks := range map[string]DeployHooks{
+		"unnamed":   {PostDeploy: []DeployHook{{Command: "true"}}},
+		"no action": {PostDeploy: []DeployHook{{Name: "none"}}},
+		"two actions": {PostDeploy: []DeployHook{{
+			Name:    "two",
+			Command: "true",
+			Fund:    &FundAction{Addresses: []string{"0x00"}, Amount: "1", Key: "key"},
+		}}},
+		"built-in pre-deploy": {PreDeploy: []DeployHook{{
+			Name: "fund",
+			Fund: &FundAction{Addresses: []string{"0x00"}, Amount: "1", Key: "key"},
+		}}},
+		"two bytecodes": {PostDeploy: []DeployHook{{
+			Name:           "deploy",
+			DeployContract: &DeployContractAction{Bytecode: "0x00", BytecodePath: "code.hex", Key: "key"},
+		}}},
+		"no key": {PostDeploy: []DeployHook{{
+			Name: "fund",
+			Fund: &FundAction{Addresses: []string{"0x00"}, Amount: "1"},
+		}}},
+	} {
+		require.Error(hooks.Validate(), name)
+	}
+}
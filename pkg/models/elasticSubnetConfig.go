@@ -9,6 +9,7 @@ import (
 )
 
 type ElasticSubnetConfig struct {
+	Version                  string
 	SubnetID                 ids.ID
 	AssetID                  ids.ID
 	InitialSupply            uint64
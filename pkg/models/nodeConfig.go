@@ -3,6 +3,7 @@
 package models
 
 type NodeConfig struct {
+	Version       string // schema version, set on write
 	NodeID        string // instance id on cloud server
 	Region        string // region where cloud server instance is deployed
 	AMI           string // image id for cloud server dependent on its os (e.g. ubuntu )and region deployed (e.g. us-east-1)
@@ -14,8 +14,26 @@ type NetworkData struct {
 	RPCVersion                  int
 	TeleporterMessengerAddress  string
 	TeleporterRegistryAddress   string
+	// Chains maps the name of each chain config deployed into the subnet to
+	// its blockchain ID. Only kept in the sidecar of the subnet itself
+	Chains map[string]ids.ID `json:",omitempty"`
+	// Pending is set while a deploy into the network is in progress. DeployTxs
+	// checkpoints the txs issued by it, by deploy step, so that an interrupted
+	// deploy can be resumed instead of paying for a new subnet
+	Pending   bool              `json:",omitempty"`
+	DeployTxs map[string]ids.ID `json:",omitempty"`
+	// ValidatorTxs maps the node ID of each validator added to the subnet to
+	// its last AddSubnetValidatorTx, so that it can be found, and renewed,
+	// after its period ends and it is no longer listed by the P-Chain
+	ValidatorTxs map[string]ids.ID `json:",omitempty"`
 }
 
+// deploy steps checkpointed in [NetworkData.DeployTxs]
+const (
+	CreateSubnetTxStep = "CreateSubnetTx"
+	CreateChainTxStep  = "CreateChainTx"
+)
+
 type PermissionlessValidators struct {
 	TxID ids.ID
 }
@@ -51,6 +69,44 @@ type Sidecar struct {
 	TeleporterVersion string
 	// SubnetEVM based VM's only
 	SubnetEVMMainnetChainID uint
+	// name@version of the genesis template the subnet was created from, if any
+	GenesisTemplate string `json:",omitempty"`
+	// manifest of the VM template the subnet was created from, if any
+	VMTemplate *VMTemplateManifest `json:",omitempty"`
+	// record of the last source build of the VM binary, if any
+	CustomVMBuild *CustomVMBuild `json:",omitempty"`
+	// results of the deploy hooks run by the last deploy into each network
+	DeployHookResults map[string][]DeployHookResult `json:",omitempty"`
+}
+
+// CustomVMBuild pins a VM built from source, so that every later build can be
+// checked to produce the very same binary
+type CustomVMBuild struct {
+	// Commit is the commit hash CustomVMBranch resolved to
+	Commit string
+	// BinarySHA256 is the hex encoded SHA-256 of the built binary
+	BinarySHA256 string
+	// VMID the binary is installed as
+	VMID string
+	// BuildImage is the container image the build script is run in. The build
+	// runs on the host when empty
+	BuildImage string `json:",omitempty"`
+}
+
+// GetSubnetName returns the name of the subnet the chain of [sc] is deployed
+// into: its own name, unless it was created as an additional chain of another
+// subnet
+func (sc Sidecar) GetSubnetName() string {
+	if sc.Subnet == "" {
+		return sc.Name
+	}
+	return sc.Subnet
+}
+
+// IsAddedChain returns true if [sc] is a chain added to the subnet of
+// another sidecar, instead of the chain a subnet was created with
+func (sc Sidecar) IsAddedChain() bool {
+	return sc.GetSubnetName() != sc.Name
 }
 
 func (sc Sidecar) GetVMID() (string, error) {
@@ -0,0 +1,21 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import "time"
+
+// UpgradeRehearsal records the outcome of rehearsing the upgrade bytes of a
+// subnet on a local network
+type UpgradeRehearsal struct {
+	// UpgradeSHA256 identifies the rehearsed upgrade file
+	UpgradeSHA256 string
+	Time          time.Time
+	Passed        bool
+	Checks        []UpgradeRehearsalCheck
+}
+
+type UpgradeRehearsalCheck struct {
+	Name    string
+	Passed  bool
+	Details string `json:",omitempty"`
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+// TemplateParam is a value a template can be customized with
+type TemplateParam struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description"`
+	// Type is one of string (default), address or uint
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Default is used when no value is given. Params without a default are
+	// required
+	Default *string `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// VMTemplateManifest describes a third party VM so that the CLI can create,
+// deploy and upgrade subnets running it. Manifests are YAML files, and are
+// also stored in the sidecar of the subnets created from them
+type VMTemplateManifest struct {
+	// Name is used as the VM type of the subnets created from the manifest
+	Name        string           `json:"name" yaml:"name"`
+	Description string           `json:"description,omitempty" yaml:"description,omitempty"`
+	Binary      VMTemplateBinary `json:"binary" yaml:"binary"`
+	// CompatibilityURL points to a JSON file with a rpcChainVMProtocolVersion
+	// map from VM version to RPC protocol version, as the Subnet-EVM
+	// compatibility.json. When empty, the installed binary is queried
+	CompatibilityURL string `json:"compatibilityURL,omitempty" yaml:"compatibilityURL,omitempty"`
+	// ChainConfig is written as the chain config of new subnets
+	ChainConfig map[string]interface{} `json:"chainConfig,omitempty" yaml:"chainConfig,omitempty"`
+	Genesis     VMTemplateGenesis      `json:"genesis" yaml:"genesis"`
+}
+
+// VMTemplateBinary tells where the VM binary comes from. Exactly one of GitHub
+// and Build is set
+type VMTemplateBinary struct {
+	GitHub *VMTemplateGitHubRelease `json:"github,omitempty" yaml:"github,omitempty"`
+	Build  *VMTemplateBuild         `json:"build,omitempty" yaml:"build,omitempty"`
+}
+
+// VMTemplateGitHubRelease downloads the VM from the releases of a GitHub repo
+type VMTemplateGitHubRelease struct {
+	Org  string `json:"org" yaml:"org"`
+	Repo string `json:"repo" yaml:"repo"`
+	// Asset is the name of the release tar.gz archive, as a text/template
+	// using {{.Version}}, {{.VersionNumber}} (without the v), {{.OS}} and
+	// {{.Arch}}
+	Asset string `json:"asset" yaml:"asset"`
+	// Binary is the path of the VM binary inside the archive
+	Binary string `json:"binary" yaml:"binary"`
+}
+
+// VMTemplateBuild builds the VM from source, as custom VMs are. The VM version
+// is the branch or commit to build
+type VMTemplateBuild struct {
+	Repo string `json:"repo" yaml:"repo"`
+	// Branch is the default branch or commit
+	Branch string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	// Script is run from the repo root with the output binary path as argument
+	Script string `json:"script" yaml:"script"`
+	// Image is the container image the script is run in, if any
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+}
+
+// VMTemplateGenesis builds the genesis of new subnets
+type VMTemplateGenesis struct {
+	Params []TemplateParam `json:"params,omitempty" yaml:"params,omitempty"`
+	// Template is a text/template rendered with the param values
+	Template string `json:"template" yaml:"template"`
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/ava-labs/avalanche-network-runner/utils"
 )
 
@@ -58,8 +59,18 @@ func CreatePlugin(app *application.Avalanche, subnetName string, pluginDir strin
 			}
 		case models.CustomVM:
 			vmSourcePath = binutils.SetupCustomBin(app, subnetName)
+			if err := vm.VerifyCustomVMBinary(&sc, vmSourcePath); err != nil {
+				return "", err
+			}
 		default:
-			return "", fmt.Errorf("unknown vm: %s", sc.VM)
+			vmTemplate, err := vm.GetVMTemplateForSidecar(app, &sc)
+			if err != nil {
+				return "", fmt.Errorf("unknown vm %s: %w", sc.VM, err)
+			}
+			vmSourcePath, err = vmTemplate.SetupBinary(app, &sc, sc.VMVersion)
+			if err != nil {
+				return "", err
+			}
 		}
 		vmDestPath = filepath.Join(pluginDir, chainVMID.String())
 	}
@@ -71,7 +82,7 @@ func CreatePlugin(app *application.Avalanche, subnetName string, pluginDir strin
 func CreatePluginFromVersion(
 	app *application.Avalanche,
 	subnetName string,
-	vm models.VMType,
+	vmType models.VMType,
 	version string,
 	vmid string,
 	pluginDir string,
@@ -80,7 +91,7 @@ func CreatePluginFromVersion(
 	var vmDestPath string
 	var err error
 
-	switch vm {
+	switch vmType {
 	case models.SubnetEvm:
 		_, vmSourcePath, err = binutils.SetupSubnetEVM(app, version)
 		if err != nil {
@@ -89,7 +100,18 @@ func CreatePluginFromVersion(
 	case models.CustomVM:
 		vmSourcePath = binutils.SetupCustomBin(app, subnetName)
 	default:
-		return "", fmt.Errorf("unknown vm: %s", vm)
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return "", fmt.Errorf("failed to load sidecar: %w", err)
+		}
+		vmTemplate, err := vm.GetVMTemplateForSidecar(app, &sc)
+		if err != nil {
+			return "", fmt.Errorf("unknown vm %s: %w", vmType, err)
+		}
+		vmSourcePath, err = vmTemplate.SetupBinary(app, &sc, version)
+		if err != nil {
+			return "", err
+		}
 	}
 	vmDestPath = filepath.Join(pluginDir, vmid)
 
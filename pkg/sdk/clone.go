@@ -0,0 +1,170 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+)
+
+var (
+	ErrCloneSourceNotFound = errors.New("source subnet configuration not found")
+	ErrCloneExists         = errors.New("destination subnet configuration already exists")
+)
+
+// CloneOptions configures [Client.CloneSubnet].
+type CloneOptions struct {
+	Source      string
+	Destination string
+	// ChainID overrides the Subnet-EVM chain ID of the clone when not 0
+	ChainID uint64
+	// TokenName overrides the token name of the clone when not empty
+	TokenName string
+	// Force replaces an existing destination configuration
+	Force bool
+}
+
+// CloneResult describes the subnet configuration created by
+// [Client.CloneSubnet].
+type CloneResult struct {
+	Sidecar *models.Sidecar
+	VMID    string
+	// CopiedFiles are the paths of the files written for the clone, sidecar
+	// and genesis included
+	CopiedFiles []string
+}
+
+// CloneSubnet copies the configuration of an existing subnet into a new one
+// that can be deployed from scratch. Genesis, chain, per node chain, subnet
+// and node configs, upgrade bytes and custom VM binaries are copied, together
+// with the build record of the binary, moved to the VMID of the clone.
+// Deployment data is not: the sidecar networks and elastic subnet info, the
+// upgrade lock file and the elastic subnet config are dropped.
+func (c *Client) CloneSubnet(opts CloneOptions) (*CloneResult, error) {
+	if err := ValidateSubnetName(opts.Destination); err != nil {
+		return nil, fmt.Errorf("subnet name %q is invalid: %w", opts.Destination, err)
+	}
+	if opts.Source == opts.Destination {
+		return nil, errors.New("source and destination subnets must differ")
+	}
+	if !c.app.SidecarExists(opts.Source) {
+		return nil, fmt.Errorf("%w: %s", ErrCloneSourceNotFound, opts.Source)
+	}
+	if c.app.SidecarExists(opts.Destination) || c.app.GenesisExists(opts.Destination) {
+		if !opts.Force {
+			return nil, fmt.Errorf("%w: %s", ErrCloneExists, opts.Destination)
+		}
+		if err := os.RemoveAll(filepath.Join(c.app.GetSubnetDir(), opts.Destination)); err != nil {
+			return nil, err
+		}
+	}
+	sc, err := c.app.LoadSidecar(opts.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if opts.ChainID != 0 && sc.VM != models.SubnetEvm {
+		return nil, fmt.Errorf("the chain ID can only be overridden for %s subnets", models.SubnetEvm)
+	}
+
+	sc.Name = opts.Destination
+	sc.Subnet = opts.Destination
+	sc.Networks = map[string]models.NetworkData{}
+	sc.ElasticSubnet = map[string]models.ElasticSubnet{}
+	if opts.TokenName != "" {
+		sc.TokenName = opts.TokenName
+	}
+
+	result := &CloneResult{Sidecar: &sc}
+
+	genesisBytes, err := c.app.LoadRawGenesis(opts.Source)
+	if err != nil {
+		return nil, err
+	}
+	if opts.ChainID != 0 {
+		genesisBytes, err = setGenesisChainID(genesisBytes, opts.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		sc.ChainID = fmt.Sprint(opts.ChainID)
+	}
+	if err := c.app.WriteGenesisFile(opts.Destination, genesisBytes); err != nil {
+		return nil, err
+	}
+	result.CopiedFiles = append(result.CopiedFiles, c.app.GetGenesisPath(opts.Destination))
+
+	srcDir := filepath.Join(c.app.GetSubnetDir(), opts.Source)
+	dstDir := filepath.Join(c.app.GetSubnetDir(), opts.Destination)
+	for _, fileName := range []string{
+		constants.ChainConfigFileName,
+		constants.PerNodeChainConfigFileName,
+		constants.SubnetConfigFileName,
+		constants.NodeConfigFileName,
+		constants.UpgradeBytesFileName,
+	} {
+		src := filepath.Join(srcDir, fileName)
+		if !utils.FileExists(src) {
+			continue
+		}
+		dst := filepath.Join(dstDir, fileName)
+		if err := utils.FileCopy(src, dst); err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", src, err)
+		}
+		result.CopiedFiles = append(result.CopiedFiles, dst)
+	}
+
+	if sc.VM == models.CustomVM && !sc.ImportedFromAPM {
+		src := c.app.GetCustomVMPath(opts.Source)
+		if utils.FileExists(src) {
+			dst := c.app.GetCustomVMPath(opts.Destination)
+			if err := binutils.CopyFile(src, dst); err != nil {
+				return nil, fmt.Errorf("failed to copy custom VM binary: %w", err)
+			}
+			result.CopiedFiles = append(result.CopiedFiles, dst)
+		}
+	}
+
+	// the VMID is derived from the subnet name, unless imported from APM
+	result.VMID, err = sc.GetVMID()
+	if err != nil {
+		return nil, err
+	}
+	// the copied binary is the one pinned by the build record, but it is
+	// installed under the VMID of the clone
+	if sc.CustomVMBuild != nil {
+		build := *sc.CustomVMBuild
+		build.VMID = result.VMID
+		sc.CustomVMBuild = &build
+	}
+	if err := c.app.CreateSidecar(&sc); err != nil {
+		return nil, err
+	}
+	result.CopiedFiles = append(result.CopiedFiles, c.app.GetSidecarPath(opts.Destination))
+	return result, nil
+}
+
+// setGenesisChainID rewrites config.chainId of a Subnet-EVM genesis, leaving
+// every other field untouched
+func setGenesisChainID(genesisBytes []byte, chainID uint64) ([]byte, error) {
+	var genesis map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(genesisBytes))
+	// keep big numbers (eg. balances) as they are
+	decoder.UseNumber()
+	if err := decoder.Decode(&genesis); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis: %w", err)
+	}
+	config, ok := genesis["config"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("genesis has no config section")
+	}
+	config["chainId"] = chainID
+	return json.MarshalIndent(genesis, "", "    ")
+}
@@ -0,0 +1,211 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/teleporter"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"golang.org/x/mod/semver"
+)
+
+// CreateSubnetOptions configures [Client.CreateSubnet].
+type CreateSubnetOptions struct {
+	// Name of the subnet configuration to create
+	Name string
+	// VM is either models.SubnetEvm, models.CustomVM or the name of a VM
+	// template
+	VM models.VMType
+	// Genesis holds the full genesis file contents
+	Genesis []byte
+	// VMVersion is the Subnet-EVM version to use (SubnetEvm), or the VM
+	// template version, the default one when empty (VM templates)
+	VMVersion string
+	// RPCVersion of the VM. If zero, it is looked up for the given VMVersion
+	// (SubnetEvm) or queried from the binary (CustomVM)
+	RPCVersion int
+	// CustomVMPath is the path to the VM binary (CustomVM only)
+	CustomVMPath string
+	// TokenName is informational and stored in the sidecar
+	TokenName string
+	// TeleporterReady prefunds a stored teleporter key in a Subnet-EVM genesis
+	TeleporterReady bool
+	// TeleporterVersion defaults to the latest released version
+	TeleporterVersion string
+	// Force overwrites an existing configuration with the same name
+	Force bool
+}
+
+// CreateSubnet writes the genesis and sidecar for a new subnet configuration.
+func (c *Client) CreateSubnet(opts CreateSubnetOptions) (*models.Sidecar, error) {
+	if err := ValidateSubnetName(opts.Name); err != nil {
+		return nil, fmt.Errorf("subnet name %q is invalid: %w", opts.Name, err)
+	}
+	if c.app.GenesisExists(opts.Name) && !opts.Force {
+		return nil, fmt.Errorf("configuration for %s already exists", opts.Name)
+	}
+	if len(opts.Genesis) == 0 {
+		return nil, errors.New("a genesis is required")
+	}
+	sc := &models.Sidecar{
+		Name:              opts.Name,
+		VM:                opts.VM,
+		Subnet:            opts.Name,
+		TokenName:         opts.TokenName,
+		RPCVersion:        opts.RPCVersion,
+		TeleporterVersion: opts.TeleporterVersion,
+	}
+	switch opts.VM {
+	case models.SubnetEvm:
+		if !semver.IsValid(opts.VMVersion) {
+			return nil, fmt.Errorf("invalid version string, should be semantic version (ex: v1.1.1): %q", opts.VMVersion)
+		}
+		sc.VMVersion = opts.VMVersion
+		if sc.RPCVersion == 0 {
+			rpcVersion, err := vm.GetRPCProtocolVersion(c.app, models.SubnetEvm, opts.VMVersion)
+			if err != nil {
+				return nil, err
+			}
+			sc.RPCVersion = rpcVersion
+		}
+	case models.CustomVM:
+		if opts.CustomVMPath == "" {
+			return nil, errors.New("a VM binary path is required for custom VMs")
+		}
+		if err := c.app.CopyVMBinary(opts.CustomVMPath, opts.Name); err != nil {
+			return nil, err
+		}
+		if sc.RPCVersion == 0 {
+			rpcVersion, err := vm.GetVMBinaryProtocolVersion(c.app.GetCustomVMPath(opts.Name))
+			if err != nil {
+				return nil, fmt.Errorf("unable to get RPC version: %w", err)
+			}
+			sc.RPCVersion = rpcVersion
+		}
+	default:
+		vmTemplate, err := vm.GetVMTemplate(c.app, string(opts.VM))
+		if err != nil {
+			return nil, fmt.Errorf("unsupported vm: %q: %w", opts.VM, err)
+		}
+		if err := vm.SetupVMTemplateSidecar(c.app, sc, vmTemplate, opts.VMVersion); err != nil {
+			return nil, err
+		}
+		chainConfig, err := vmTemplate.DefaultChainConfig()
+		if err != nil {
+			return nil, err
+		}
+		if chainConfig != nil {
+			if err := c.app.WriteChainConfigFile(opts.Name, chainConfig); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := c.SaveSubnet(sc, opts.Genesis, opts.TeleporterReady); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// SaveSubnet persists an already assembled sidecar and genesis. If
+// teleporterReady is set and the genesis is a Subnet-EVM one, the stored
+// teleporter key is created if needed and prefunded in the genesis.
+func (c *Client) SaveSubnet(sc *models.Sidecar, genesis []byte, teleporterReady bool) error {
+	var err error
+	if teleporterReady && IsSubnetEVMGenesis(genesis) {
+		genesis, err = c.setupTeleporter(sc, genesis)
+		if err != nil {
+			return err
+		}
+	}
+	if err := c.app.WriteGenesisFile(sc.Name, genesis); err != nil {
+		return err
+	}
+	sc.ImportedFromAPM = false
+	return c.app.CreateSidecar(sc)
+}
+
+func (c *Client) setupTeleporter(sc *models.Sidecar, genesis []byte) ([]byte, error) {
+	keyPath := c.app.GetKeyPath(constants.TeleporterKeyName)
+	var (
+		k   *key.SoftKey
+		err error
+	)
+	if utils.FileExists(keyPath) {
+		ux.Logger.PrintToUser("loading stored key %q for teleporter deploys", constants.TeleporterKeyName)
+		k, err = key.LoadSoft(models.NewLocalNetwork().ID, keyPath)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ux.Logger.PrintToUser("generating stored key %q for teleporter deploys", constants.TeleporterKeyName)
+		k, err = key.NewSoft(0)
+		if err != nil {
+			return nil, err
+		}
+		if err := k.Save(keyPath); err != nil {
+			return nil, err
+		}
+	}
+	ux.Logger.PrintToUser("  (evm address, genesis balance) = (%s, %v)", k.C(), teleporter.TeleporterPrefundedAddressBalance)
+	genesis, err = addGenesisPrefundedAddress(genesis, k.C(), teleporter.TeleporterPrefundedAddressBalance.String())
+	if err != nil {
+		return nil, err
+	}
+	if sc.TeleporterVersion == "" {
+		// let's use latest versions for teleporter contract
+		sc.TeleporterVersion, err = c.app.Downloader.GetLatestReleaseVersion(binutils.GetGithubLatestReleaseURL(constants.AvaLabsOrg, constants.TeleporterRepoName))
+		if err != nil {
+			return nil, err
+		}
+		ux.Logger.PrintToUser("using latest teleporter version (%s)", sc.TeleporterVersion)
+	}
+	sc.TeleporterReady = true
+	sc.TeleporterKey = constants.TeleporterKeyName
+	return genesis, nil
+}
+
+// IsSubnetEVMGenesis reports whether genesis is a valid Subnet-EVM genesis.
+func IsSubnetEVMGenesis(genesis []byte) bool {
+	var gen core.Genesis
+	if err := json.Unmarshal(genesis, &gen); err != nil || gen.Config == nil {
+		return false
+	}
+	gen.Config.AvalancheContext = params.AvalancheContext{
+		SnowCtx: &snow.Context{},
+	}
+	return gen.Verify() == nil
+}
+
+func addGenesisPrefundedAddress(genesisBytes []byte, address string, balance string) ([]byte, error) {
+	var genesisMap map[string]interface{}
+	if err := json.Unmarshal(genesisBytes, &genesisMap); err != nil {
+		return nil, err
+	}
+	allocI, ok := genesisMap["alloc"]
+	if !ok {
+		return nil, fmt.Errorf("alloc field not found on genesis")
+	}
+	alloc, ok := allocI.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected genesis alloc field to be map[string]interface, found %T", allocI)
+	}
+	trimmedAddress := strings.TrimPrefix(address, "0x")
+	alloc[trimmedAddress] = map[string]interface{}{
+		"balance": balance,
+	}
+	genesisMap["alloc"] = alloc
+	return json.MarshalIndent(genesisMap, "", "  ")
+}
@@ -0,0 +1,560 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"go.uber.org/zap"
+	"golang.org/x/exp/slices"
+)
+
+// ErrDeployBlockchain is wrapped by [Client.Deploy] when the subnet was
+// created (and recorded in the sidecar) but the blockchain creation failed.
+var ErrDeployBlockchain = errors.New("error deploying blockchain")
+
+// ErrInvalidGenesis is wrapped by [Client.Deploy] and [Client.DeployLocal]
+// when the validation of a Subnet-EVM genesis finds errors.
+var ErrInvalidGenesis = errors.New("genesis validation failed")
+
+// publicDeployer issues the txs of [Client.Deploy]
+type publicDeployer interface {
+	DeploySubnet(controlKeys []string, threshold uint32) (ids.ID, error)
+	DeployBlockchain(
+		controlKeys []string,
+		subnetAuthKeys []string,
+		subnetID ids.ID,
+		transferSubnetOwnershipTxID ids.ID,
+		chain string,
+		genesis []byte,
+	) (bool, ids.ID, *txs.Tx, []string, error)
+}
+
+// newPublicDeployer returns the deployer used by [Client.Deploy]. Tests
+// replace it to avoid issuing txs
+var newPublicDeployer = func(app *application.Avalanche, kc *keychain.Keychain, network models.Network) publicDeployer {
+	return subnet.NewPublicDeployer(app, kc, network)
+}
+
+// DeployOptions configures [Client.Deploy] on a public network (Fuji,
+// Mainnet, Devnet or a cluster of any of those).
+type DeployOptions struct {
+	SubnetName string
+	Network    models.Network
+	// Keychain pays the fees and signs the txs
+	Keychain *keychain.Keychain
+	// Genesis overrides the stored genesis of the subnet (eg. to set the
+	// Mainnet chain ID). If nil, the stored genesis is used
+	Genesis []byte
+	// SubnetID deploys the blockchain into this existing subnet. If empty,
+	// chains added to a subnet are deployed into it, and otherwise a subnet
+	// previously created on this network without a blockchain is reused, or
+	// else a new subnet is created
+	SubnetID ids.ID
+	// ControlKeys and Threshold define the owners of a new subnet. For an
+	// existing subnet they are queried from the P-Chain when empty
+	ControlKeys []string
+	Threshold   uint32
+	// SubnetAuthKeys sign the blockchain creation. When nil, they are
+	// selected automatically if this can be done without ambiguity
+	SubnetAuthKeys []string
+	// SubnetOnly creates the subnet but not the blockchain
+	SubnetOnly bool
+	// SkipGenesisValidation deploys even if the genesis validation finds
+	// errors
+	SkipGenesisValidation bool
+}
+
+// DeployResult describes the outcome of [Client.Deploy].
+type DeployResult struct {
+	SubnetID                    ids.ID
+	TransferSubnetOwnershipTxID ids.ID
+	BlockchainID                ids.ID
+	// CreatedSubnet is set if a new subnet was created
+	CreatedSubnet bool
+	// BlockchainTx is nil if only the subnet was created
+	BlockchainTx *TxResult
+}
+
+// PendingDeploy describes the steps of an interrupted deploy that made it
+// into the P-Chain.
+type PendingDeploy struct {
+	// SubnetID is the subnet the deploy was made into, or ids.Empty if the
+	// subnet it created was not accepted
+	SubnetID                    ids.ID
+	TransferSubnetOwnershipTxID ids.ID
+	// BlockchainID is set if the blockchain creation was accepted, in which
+	// case the deploy has been completed
+	BlockchainID ids.ID
+}
+
+// CheckPendingDeploy looks on the P-Chain for the txs checkpointed by an
+// interrupted deploy of [subnetName] into [network]. Steps whose txs were not
+// accepted are dropped from the sidecar so that a new deploy issues them
+// again, and the deploy is recorded as completed if its blockchain was
+// created. Returns nil if there is no deploy pending.
+func (c *Client) CheckPendingDeploy(subnetName string, network models.Network) (*PendingDeploy, error) {
+	pending, _, err := c.checkPendingDeploy(subnetName, network, false)
+	return pending, err
+}
+
+// PreviewPendingDeploy is a read-only [Client.CheckPendingDeploy], for dry
+// runs. It returns what a deploy would resume, together with the sidecar as
+// CheckPendingDeploy would leave it, without writing it nor the cluster config.
+func (c *Client) PreviewPendingDeploy(subnetName string, network models.Network) (*PendingDeploy, *models.Sidecar, error) {
+	return c.checkPendingDeploy(subnetName, network, true)
+}
+
+func (c *Client) checkPendingDeploy(subnetName string, network models.Network, dryRun bool) (*PendingDeploy, *models.Sidecar, error) {
+	sc, err := c.app.LoadSidecar(subnetName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	networkData, ok := sc.Networks[network.Name()]
+	if !ok || !networkData.Pending {
+		return nil, &sc, nil
+	}
+	if txID, ok := networkData.DeployTxs[models.CreateSubnetTxStep]; ok {
+		accepted, err := isTxAccepted(network, txID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !accepted {
+			delete(networkData.DeployTxs, models.CreateSubnetTxStep)
+			networkData.SubnetID = ids.Empty
+		}
+	}
+	pending := &PendingDeploy{
+		SubnetID:                    networkData.SubnetID,
+		TransferSubnetOwnershipTxID: networkData.TransferSubnetOwnershipTxID,
+	}
+	if txID, ok := networkData.DeployTxs[models.CreateChainTxStep]; ok {
+		accepted, err := isTxAccepted(network, txID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if accepted {
+			pending.BlockchainID = txID
+			if dryRun {
+				return pending, &sc, nil
+			}
+			if network.ClusterName != "" {
+				if err := c.addSubnetToCluster(network.ClusterName, subnetName); err != nil {
+					return nil, nil, err
+				}
+			}
+			if err := c.app.UpdateSidecarNetworks(
+				&sc,
+				network,
+				pending.SubnetID,
+				pending.TransferSubnetOwnershipTxID,
+				pending.BlockchainID,
+				"",
+				"",
+			); err != nil {
+				return nil, nil, err
+			}
+			return pending, &sc, nil
+		}
+		delete(networkData.DeployTxs, models.CreateChainTxStep)
+	}
+	if networkData.SubnetID == ids.Empty {
+		// nothing made it into the P-Chain
+		delete(sc.Networks, network.Name())
+	} else {
+		sc.Networks[network.Name()] = networkData
+	}
+	if dryRun {
+		return pending, &sc, nil
+	}
+	if err := c.app.UpdateSidecar(&sc); err != nil {
+		return nil, nil, err
+	}
+	return pending, &sc, nil
+}
+
+// isTxAccepted checks if the P-Chain accepted tx [txID]. Txs still being
+// processed can't be told apart yet, and give an error
+func isTxAccepted(network models.Network, txID ids.ID) (bool, error) {
+	txStatus, err := txutils.GetTxStatus(network, txID)
+	if err != nil {
+		return false, err
+	}
+	switch txStatus {
+	case status.Committed:
+		return true, nil
+	case status.Processing:
+		return false, fmt.Errorf("tx %s of the interrupted deploy is still being processed, try again later", txID)
+	default:
+		return false, nil
+	}
+}
+
+// Deploy creates the subnet and its blockchain on a public network and
+// records the result in the sidecar. Each step is checkpointed in the
+// sidecar, and a deploy interrupted after creating the subnet is resumed
+// from the step that failed.
+func (c *Client) Deploy(opts DeployOptions) (*DeployResult, error) {
+	if opts.Keychain == nil {
+		return nil, ErrMissingKeychain
+	}
+	if opts.Network.Kind == models.Local {
+		return nil, errors.New("use DeployLocal for local network deploys")
+	}
+	if opts.SubnetOnly && opts.SubnetID != ids.Empty {
+		return nil, errors.New("subnet only deploys can't be made into an existing subnet")
+	}
+	pending, err := c.CheckPendingDeploy(opts.SubnetName, opts.Network)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil && pending.BlockchainID != ids.Empty {
+		return &DeployResult{
+			SubnetID:                    pending.SubnetID,
+			TransferSubnetOwnershipTxID: pending.TransferSubnetOwnershipTxID,
+			BlockchainID:                pending.BlockchainID,
+		}, nil
+	}
+	sc, err := c.app.LoadSidecar(opts.SubnetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if sc.ImportedFromAPM {
+		return nil, errors.New("unable to deploy subnets imported from a repo")
+	}
+	genesis := opts.Genesis
+	if genesis == nil {
+		genesis, err = c.app.LoadRawGenesis(opts.SubnetName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.SubnetOnly && sc.IsAddedChain() {
+		return nil, fmt.Errorf("%s is a chain added to subnet %s: it can't be deployed as a subnet", sc.Name, sc.GetSubnetName())
+	}
+	if !opts.SubnetOnly && !opts.SkipGenesisValidation {
+		if err := lintGenesis(&sc, genesis); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &DeployResult{SubnetID: opts.SubnetID, CreatedSubnet: true}
+	networkData, hasNetworkData := sc.Networks[opts.Network.Name()]
+	subnetData, subnetDeployed, err := c.app.GetSubnetNetworkData(&sc, opts.Network)
+	if err != nil {
+		return nil, err
+	}
+	if result.SubnetID != ids.Empty {
+		result.CreatedSubnet = false
+		if subnetDeployed && subnetData.SubnetID == result.SubnetID {
+			result.TransferSubnetOwnershipTxID = subnetData.TransferSubnetOwnershipTxID
+		}
+	} else if sc.IsAddedChain() {
+		// added chains are deployed into the subnet they were added to
+		if !subnetDeployed {
+			return nil, fmt.Errorf("subnet %s is not deployed on %s", sc.GetSubnetName(), opts.Network.Name())
+		}
+		result.SubnetID = subnetData.SubnetID
+		result.TransferSubnetOwnershipTxID = subnetData.TransferSubnetOwnershipTxID
+		result.CreatedSubnet = false
+	} else if hasNetworkData && (!opts.SubnetOnly || networkData.Pending) {
+		if networkData.SubnetID != ids.Empty && networkData.BlockchainID == ids.Empty {
+			result.SubnetID = networkData.SubnetID
+			result.TransferSubnetOwnershipTxID = networkData.TransferSubnetOwnershipTxID
+			result.CreatedSubnet = false
+		}
+	}
+	if pending != nil && pending.SubnetID != ids.Empty && pending.SubnetID == result.SubnetID && len(opts.ControlKeys) != 0 {
+		// the owners of the subnet being resumed must be the requested ones
+		owners, threshold, err := txutils.GetOwners(opts.Network, result.SubnetID, result.TransferSubnetOwnershipTxID)
+		if err != nil {
+			return nil, err
+		}
+		if !sameOwners(owners, threshold, opts.ControlKeys, opts.Threshold) {
+			return nil, fmt.Errorf(
+				"subnet %s created by an interrupted deploy is owned by %v with threshold %d, not by the given control keys",
+				result.SubnetID,
+				owners,
+				threshold,
+			)
+		}
+	}
+
+	controlKeys, threshold := opts.ControlKeys, opts.Threshold
+	if result.CreatedSubnet {
+		if len(controlKeys) == 0 {
+			return nil, errors.New("control keys are required to create a subnet")
+		}
+		if threshold == 0 || int(threshold) > len(controlKeys) {
+			return nil, fmt.Errorf("threshold must be between 1 and the number of control keys (%d): %d", len(controlKeys), threshold)
+		}
+	} else if len(controlKeys) == 0 {
+		controlKeys, threshold, err = txutils.GetOwners(opts.Network, result.SubnetID, result.TransferSubnetOwnershipTxID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// add control keys to the keychain whenever possible
+	if err := opts.Keychain.AddAddresses(controlKeys); err != nil {
+		return nil, err
+	}
+	subnetAuthKeys, err := selectSubnetAuthKeys(opts.Keychain, opts.SubnetAuthKeys, controlKeys, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	deployer := newPublicDeployer(c.app, opts.Keychain, opts.Network)
+	if result.CreatedSubnet {
+		result.SubnetID, err = deployer.DeploySubnet(controlKeys, threshold)
+		if result.SubnetID != ids.Empty {
+			if err := c.app.UpdateSidecarDeployStep(
+				&sc,
+				opts.Network,
+				result.SubnetID,
+				ids.Empty,
+				models.CreateSubnetTxStep,
+				result.SubnetID,
+			); err != nil {
+				return nil, err
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		// get the control keys in the same order as the tx
+		controlKeys, _, err = txutils.GetOwners(opts.Network, result.SubnetID, ids.Empty)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var deployErr error
+	if !opts.SubnetOnly {
+		isFullySigned, blockchainID, tx, remainingSubnetAuthKeys, err := deployer.DeployBlockchain(
+			controlKeys,
+			subnetAuthKeys,
+			result.SubnetID,
+			result.TransferSubnetOwnershipTxID,
+			opts.SubnetName,
+			genesis,
+		)
+		if err != nil {
+			deployErr = fmt.Errorf("%w: %w", ErrDeployBlockchain, err)
+			if isFullySigned && tx != nil {
+				// the tx may still be accepted, so it is checked by the next deploy
+				// before creating another blockchain
+				if err := c.app.UpdateSidecarDeployStep(
+					&sc,
+					opts.Network,
+					result.SubnetID,
+					result.TransferSubnetOwnershipTxID,
+					models.CreateChainTxStep,
+					tx.ID(),
+				); err != nil {
+					return nil, err
+				}
+				return result, deployErr
+			}
+		} else {
+			result.BlockchainID = blockchainID
+			result.BlockchainTx = &TxResult{
+				Tx:                      tx,
+				FullySigned:             isFullySigned,
+				SubnetAuthKeys:          subnetAuthKeys,
+				RemainingSubnetAuthKeys: remainingSubnetAuthKeys,
+			}
+			if isFullySigned && opts.Network.ClusterName != "" {
+				if err := c.addSubnetToCluster(opts.Network.ClusterName, opts.SubnetName); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if err := c.app.UpdateSidecarNetworks(
+		&sc,
+		opts.Network,
+		result.SubnetID,
+		result.TransferSubnetOwnershipTxID,
+		result.BlockchainID,
+		"",
+		"",
+	); err != nil {
+		return nil, err
+	}
+	return result, deployErr
+}
+
+// LocalDeployOptions configures [Client.DeployLocal].
+type LocalDeployOptions struct {
+	SubnetName string
+	// AvalancheGoVersion to use if the local network is not running yet.
+	// Ignored if AvalancheGoBinaryPath is set
+	AvalancheGoVersion    string
+	AvalancheGoBinaryPath string
+	// SubnetID deploys the blockchain into this existing local subnet. If
+	// empty, chains added to a subnet are deployed into it
+	SubnetID ids.ID
+	// SkipTeleporter disables the automatic teleporter deploy
+	SkipTeleporter bool
+	// SkipGenesisValidation deploys even if the genesis validation finds
+	// errors
+	SkipGenesisValidation bool
+}
+
+// LocalDeployResult describes the outcome of [Client.DeployLocal].
+type LocalDeployResult struct {
+	SubnetID                   ids.ID
+	BlockchainID               ids.ID
+	TeleporterMessengerAddress string
+	TeleporterRegistryAddress  string
+}
+
+// DeployLocal deploys the subnet into the local network, starting it if
+// needed, and records the result in the sidecar.
+func (c *Client) DeployLocal(opts LocalDeployOptions) (*LocalDeployResult, error) {
+	sc, err := c.app.LoadSidecar(opts.SubnetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	genesis, err := c.app.LoadRawGenesis(opts.SubnetName)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.SkipGenesisValidation {
+		if err := lintGenesis(&sc, genesis); err != nil {
+			return nil, err
+		}
+	}
+	// copy vm binary to the expected location, first downloading it if necessary
+	var vmBin string
+	switch sc.VM {
+	case models.SubnetEvm:
+		_, vmBin, err = binutils.SetupSubnetEVM(c.app, sc.VMVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to install subnet-evm: %w", err)
+		}
+	case models.CustomVM:
+		vmBin = binutils.SetupCustomBin(c.app, opts.SubnetName)
+		if err := vm.VerifyCustomVMBinary(&sc, vmBin); err != nil {
+			return nil, err
+		}
+	default:
+		vmTemplate, err := vm.GetVMTemplateForSidecar(c.app, &sc)
+		if err != nil {
+			return nil, fmt.Errorf("unknown vm %s: %w", sc.VM, err)
+		}
+		vmBin, err = vmTemplate.SetupBinary(c.app, &sc, sc.VMVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+	subnetIDStr := ""
+	if opts.SubnetID != ids.Empty {
+		subnetIDStr = opts.SubnetID.String()
+	} else if sc.IsAddedChain() {
+		subnetData, deployed, err := c.app.GetSubnetNetworkData(&sc, models.NewLocalNetwork())
+		if err != nil {
+			return nil, err
+		}
+		if !deployed {
+			return nil, fmt.Errorf("subnet %s is not deployed on the local network", sc.GetSubnetName())
+		}
+		subnetIDStr = subnetData.SubnetID.String()
+	}
+	deployer := subnet.NewLocalDeployer(c.app, opts.AvalancheGoVersion, opts.AvalancheGoBinaryPath, vmBin)
+	deployInfo, err := deployer.DeployToLocalNetwork(
+		opts.SubnetName,
+		genesis,
+		c.app.GetGenesisPath(opts.SubnetName),
+		opts.SkipTeleporter,
+		subnetIDStr,
+	)
+	if err != nil {
+		if deployer.BackendStartedHere() {
+			if innerErr := binutils.KillgRPCServerProcess(c.app); innerErr != nil {
+				c.app.Log.Warn("tried to kill the gRPC server process but it failed", zap.Error(innerErr))
+			}
+		}
+		return nil, err
+	}
+	if err := c.app.UpdateSidecarNetworks(
+		&sc,
+		models.NewLocalNetwork(),
+		deployInfo.SubnetID,
+		ids.Empty,
+		deployInfo.BlockchainID,
+		deployInfo.TeleporterMessengerAddress,
+		deployInfo.TeleporterRegistryAddress,
+	); err != nil {
+		return nil, err
+	}
+	if sc.VM == models.SubnetEvm {
+		rpcURL := models.NewLocalNetwork().BlockchainEndpoint(deployInfo.BlockchainID.String())
+		if err := vm.VerifyGenesisContracts(rpcURL, genesis); err != nil {
+			return nil, err
+		}
+	}
+	return &LocalDeployResult{
+		SubnetID:                   deployInfo.SubnetID,
+		BlockchainID:               deployInfo.BlockchainID,
+		TeleporterMessengerAddress: deployInfo.TeleporterMessengerAddress,
+		TeleporterRegistryAddress:  deployInfo.TeleporterRegistryAddress,
+	}, nil
+}
+
+// lintGenesis fails if the Subnet-EVM genesis of [sc] has errors, so that
+// invalid genesis are caught before paying for chain creation
+func lintGenesis(sc *models.Sidecar, genesis []byte) error {
+	if sc.VM != models.SubnetEvm {
+		return nil
+	}
+	errs := vm.LintGenesis(genesis, sc.TeleporterReady).Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(errs))
+	for _, issue := range errs {
+		messages = append(messages, issue.Message)
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidGenesis, strings.Join(messages, "; "))
+}
+
+// sameOwners checks if both sets of subnet owners are equivalent
+func sameOwners(controlKeys []string, threshold uint32, otherControlKeys []string, otherThreshold uint32) bool {
+	if threshold != otherThreshold || len(controlKeys) != len(otherControlKeys) {
+		return false
+	}
+	for _, controlKey := range otherControlKeys {
+		if !slices.Contains(controlKeys, controlKey) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) addSubnetToCluster(clusterName string, subnetName string) error {
+	clusterConfig, err := c.app.GetClusterConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	if _, err := utils.GetIndexInSlice(clusterConfig.Subnets, subnetName); err != nil {
+		clusterConfig.Subnets = append(clusterConfig.Subnets, subnetName)
+	}
+	return c.app.SetClusterConfig(clusterName, clusterConfig)
+}
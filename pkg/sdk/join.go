@@ -0,0 +1,147 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package sdk
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/plugins"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// JoinOptions configures [Client.Join].
+type JoinOptions struct {
+	SubnetName string
+	Network    models.Network
+	// AvalancheGoConfigPath is the node config file to update (created if missing)
+	AvalancheGoConfigPath string
+	// PluginDir is where the VM binary is installed
+	PluginDir string
+	// WriteChainConfigs also writes the subnet config, chain config and
+	// network upgrades of the subnet into DataDir
+	WriteChainConfigs bool
+	// DataDir is the avalanchego data dir. Defaults to ~/.avalanchego
+	DataDir string
+}
+
+// Join configures a local avalanchego node to track a deployed subnet: it
+// installs the VM binary and updates the node config. It returns the path
+// of the installed VM binary.
+func (c *Client) Join(opts JoinOptions) (string, error) {
+	if opts.AvalancheGoConfigPath == "" || opts.PluginDir == "" {
+		return "", errors.New("both the avalanchego config path and the plugin dir are required")
+	}
+	sc, networkData, err := c.loadDeployedSidecar(opts.SubnetName, opts.Network)
+	if err != nil {
+		return "", err
+	}
+	avagoConfigPath, err := plugins.SanitizePath(opts.AvalancheGoConfigPath)
+	if err != nil {
+		return "", err
+	}
+	pluginDir, err := plugins.SanitizePath(opts.PluginDir)
+	if err != nil {
+		return "", err
+	}
+	vmPath, err := plugins.CreatePlugin(c.app, sc.Name, pluginDir)
+	if err != nil {
+		return "", err
+	}
+	ux.Logger.PrintToUser("VM binary written to %s", vmPath)
+	if opts.WriteChainConfigs {
+		if err := c.WriteChainConfigFiles(opts.SubnetName, opts.Network, opts.DataDir); err != nil {
+			return "", err
+		}
+	}
+	subnetAvagoConfigFile := ""
+	if c.app.AvagoNodeConfigExists(opts.SubnetName) {
+		subnetAvagoConfigFile = c.app.GetAvagoNodeConfigPath(opts.SubnetName)
+	}
+	if err := plugins.EditConfigFile(
+		c.app,
+		networkData.SubnetID.String(),
+		opts.Network,
+		avagoConfigPath,
+		true,
+		subnetAvagoConfigFile,
+	); err != nil {
+		return "", err
+	}
+	return vmPath, nil
+}
+
+// WriteChainConfigFiles writes the subnet config, chain config and network
+// upgrades of a deployed subnet into the avalanchego data dir (defaults to
+// ~/.avalanchego), removing stale ones.
+func (c *Client) WriteChainConfigFiles(subnetName string, network models.Network, dataDir string) error {
+	if dataDir == "" {
+		dataDir = utils.UserHomePath(".avalanchego")
+	}
+	sc, networkData, err := c.loadDeployedSidecar(subnetName, network)
+	if err != nil {
+		return err
+	}
+	subnetIDStr := networkData.SubnetID.String()
+	blockchainID := networkData.BlockchainID
+
+	configsPath := filepath.Join(dataDir, "configs")
+
+	subnetConfigsPath := filepath.Join(configsPath, "subnets")
+	subnetConfigPath := filepath.Join(subnetConfigsPath, subnetIDStr+".json")
+	// the subnet config of an added chain is the one of its subnet
+	switch {
+	case sc.IsAddedChain():
+	case c.app.AvagoSubnetConfigExists(subnetName):
+		if err := os.MkdirAll(subnetConfigsPath, constants.DefaultPerms755); err != nil {
+			return err
+		}
+		subnetConfig, err := c.app.LoadRawAvagoSubnetConfig(subnetName)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(subnetConfigPath, subnetConfig, constants.DefaultPerms755); err != nil {
+			return err
+		}
+	default:
+		_ = os.RemoveAll(subnetConfigPath)
+	}
+
+	if blockchainID != ids.Empty && c.app.ChainConfigExists(subnetName) || c.app.NetworkUpgradeExists(subnetName) {
+		chainConfigsPath := filepath.Join(configsPath, "chains", blockchainID.String())
+		if err := os.MkdirAll(chainConfigsPath, constants.DefaultPerms755); err != nil {
+			return err
+		}
+		chainConfigPath := filepath.Join(chainConfigsPath, "config.json")
+		if c.app.ChainConfigExists(subnetName) {
+			chainConfig, err := c.app.LoadRawChainConfig(subnetName)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(chainConfigPath, chainConfig, constants.DefaultPerms755); err != nil {
+				return err
+			}
+		} else {
+			_ = os.RemoveAll(chainConfigPath)
+		}
+		networkUpgradesPath := filepath.Join(chainConfigsPath, "upgrade.json")
+		if c.app.NetworkUpgradeExists(subnetName) {
+			networkUpgrades, err := c.app.LoadRawNetworkUpgrades(subnetName)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(networkUpgradesPath, networkUpgrades, constants.DefaultPerms755); err != nil {
+				return err
+			}
+		} else {
+			_ = os.RemoveAll(networkUpgradesPath)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package sdk exposes the subnet lifecycle operations of Avalanche-CLI as a
+// Go API for programs that embed the CLI.
+//
+// Functions in this package never prompt. Every input is given through an
+// options struct and missing or invalid inputs are reported as errors. The
+// cobra commands gather (and prompt for) their inputs and then call into
+// this package, so both share the same code path.
+package sdk
+
+import (
+	"errors"
+	"unicode"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+var (
+	ErrIllegalNameCharacter = errors.New("illegal name character: only letters, no special characters allowed")
+	ErrNoSubnetID           = errors.New("failed to find the subnet ID for this subnet, has it been deployed/created on this network?")
+	ErrNoBlockchainID       = errors.New("failed to find the blockchain ID for this subnet, has it been deployed on this network?")
+	ErrMissingKeychain      = errors.New("a keychain is required to issue transactions")
+)
+
+// Client runs lifecycle operations against the state of an [application.Avalanche].
+type Client struct {
+	app *application.Avalanche
+}
+
+// New returns a client operating on the given application state. app must
+// have been set up (see [application.Avalanche.Setup]).
+func New(app *application.Avalanche) *Client {
+	return &Client{app: app}
+}
+
+// TxResult describes a P-Chain transaction built on behalf of a subnet.
+// If the subnet requires more signatures than the keychain could provide,
+// FullySigned is false, the tx was not issued and RemainingSubnetAuthKeys
+// lists the keys that still need to sign it.
+type TxResult struct {
+	Tx                      *txs.Tx
+	FullySigned             bool
+	SubnetAuthKeys          []string
+	RemainingSubnetAuthKeys []string
+}
+
+// ValidateSubnetName checks that name can be used as a subnet and chain name.
+func ValidateSubnetName(name string) error {
+	if name == "" {
+		return errors.New("subnet name must not be empty")
+	}
+	// this is currently exactly the same code as in avalanchego/vms/platformvm/create_chain_tx.go
+	for _, r := range name {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsNumber(r) || r == ' ') {
+			return ErrIllegalNameCharacter
+		}
+	}
+	return nil
+}
+
+func (c *Client) loadDeployedSidecar(subnetName string, network models.Network) (models.Sidecar, models.NetworkData, error) {
+	sc, err := c.app.LoadSidecar(subnetName)
+	if err != nil {
+		return models.Sidecar{}, models.NetworkData{}, err
+	}
+	networkData, ok := sc.Networks[network.Name()]
+	if !ok || networkData.SubnetID == ids.Empty {
+		return models.Sidecar{}, models.NetworkData{}, ErrNoSubnetID
+	}
+	return sc, networkData, nil
+}
@@ -0,0 +1,480 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/config"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/stretchr/testify/require"
+)
+
+const testGenesis = `{
+	"config": {
+		"chainId": 12345,
+		"homesteadBlock": 0,
+		"eip150Block": 0,
+		"eip155Block": 0,
+		"eip158Block": 0,
+		"byzantiumBlock": 0,
+		"constantinopleBlock": 0,
+		"petersburgBlock": 0,
+		"istanbulBlock": 0,
+		"muirGlacierBlock": 0,
+		"subnetEVMTimestamp": 0,
+		"durangoTimestamp": 0,
+		"feeConfig": {
+			"gasLimit": 8000000,
+			"targetBlockRate": 2,
+			"minBaseFee": 25000000000,
+			"targetGas": 15000000,
+			"baseFeeChangeDenominator": 36,
+			"minBlockGasCost": 0,
+			"maxBlockGasCost": 1000000,
+			"blockGasCostStep": 200000
+		}
+	},
+	"alloc": {},
+	"gasLimit": "0x7A1200",
+	"difficulty": "0x0"
+}`
+
+func newTestClient(t *testing.T) *Client {
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, config.New(), prompts.NewPrompter(), application.NewDownloader())
+	return New(app)
+}
+
+func TestValidateSubnetName(t *testing.T) {
+	require := require.New(t)
+	require.NoError(ValidateSubnetName("mySubnet 1"))
+	require.ErrorIs(ValidateSubnetName("my-subnet"), ErrIllegalNameCharacter)
+	require.ErrorIs(ValidateSubnetName("subnetä"), ErrIllegalNameCharacter)
+	require.Error(ValidateSubnetName(""))
+}
+
+func TestIsSubnetEVMGenesis(t *testing.T) {
+	require := require.New(t)
+	require.True(IsSubnetEVMGenesis([]byte(testGenesis)))
+	require.False(IsSubnetEVMGenesis([]byte(`{"alloc": {}}`)))
+	require.False(IsSubnetEVMGenesis([]byte("not json")))
+}
+
+func TestAddGenesisPrefundedAddress(t *testing.T) {
+	require := require.New(t)
+	genesis, err := addGenesisPrefundedAddress([]byte(testGenesis), "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", "1000")
+	require.NoError(err)
+	var genesisMap map[string]interface{}
+	require.NoError(json.Unmarshal(genesis, &genesisMap))
+	alloc := genesisMap["alloc"].(map[string]interface{})
+	require.Equal(map[string]interface{}{"balance": "1000"}, alloc["8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"])
+
+	_, err = addGenesisPrefundedAddress([]byte(`{}`), "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", "1000")
+	require.Error(err)
+}
+
+func TestCreateSubnetInvalidOptions(t *testing.T) {
+	client := newTestClient(t)
+	tests := []struct {
+		name string
+		opts CreateSubnetOptions
+	}{
+		{
+			name: "invalid name",
+			opts: CreateSubnetOptions{Name: "my-subnet", VM: models.SubnetEvm, VMVersion: "v0.6.0", Genesis: []byte(testGenesis)},
+		},
+		{
+			name: "missing genesis",
+			opts: CreateSubnetOptions{Name: "mySubnet", VM: models.SubnetEvm, VMVersion: "v0.6.0"},
+		},
+		{
+			name: "invalid version",
+			opts: CreateSubnetOptions{Name: "mySubnet", VM: models.SubnetEvm, VMVersion: "latest", Genesis: []byte(testGenesis)},
+		},
+		{
+			name: "custom vm without binary",
+			opts: CreateSubnetOptions{Name: "mySubnet", VM: models.CustomVM, Genesis: []byte(testGenesis)},
+		},
+		{
+			name: "unknown vm",
+			opts: CreateSubnetOptions{Name: "mySubnet", VM: "other", Genesis: []byte(testGenesis)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateSubnet(tt.opts)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestCreateSubnetExisting(t *testing.T) {
+	require := require.New(t)
+	client := newTestClient(t)
+	opts := CreateSubnetOptions{
+		Name:       "mySubnet",
+		VM:         models.SubnetEvm,
+		VMVersion:  "v0.6.0",
+		RPCVersion: 33,
+		Genesis:    []byte(testGenesis),
+		TokenName:  "TEST",
+	}
+	sc, err := client.CreateSubnet(opts)
+	require.NoError(err)
+	require.Equal("mySubnet", sc.Subnet)
+	require.Equal(33, sc.RPCVersion)
+
+	loaded, err := client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+	require.Equal("TEST", loaded.TokenName)
+	require.Equal("v0.6.0", loaded.VMVersion)
+
+	_, err = client.CreateSubnet(opts)
+	require.Error(err)
+	opts.Force = true
+	_, err = client.CreateSubnet(opts)
+	require.NoError(err)
+}
+
+func TestCloneSubnet(t *testing.T) {
+	require := require.New(t)
+	client := newTestClient(t)
+	_, err := client.CreateSubnet(CreateSubnetOptions{
+		Name:       "prod",
+		VM:         models.SubnetEvm,
+		VMVersion:  "v0.6.0",
+		RPCVersion: 33,
+		Genesis:    []byte(testGenesis),
+		TokenName:  "PROD",
+	})
+	require.NoError(err)
+	require.NoError(client.app.WriteChainConfigFile("prod", []byte(`{"log-level": "info"}`)))
+	require.NoError(client.app.WriteNetworkUpgradesFile("prod", []byte(`{"precompileUpgrades": []}`)))
+	require.NoError(client.app.WriteLockUpgradeFile("prod", []byte(`{"precompileUpgrades": []}`)))
+	sc, err := client.app.LoadSidecar("prod")
+	require.NoError(err)
+	require.NoError(client.app.UpdateSidecarNetworks(&sc, models.NewFujiNetwork(), ids.GenerateTestID(), ids.Empty, ids.GenerateTestID(), "", ""))
+
+	_, err = client.CloneSubnet(CloneOptions{Source: "missing", Destination: "staging"})
+	require.ErrorIs(err, ErrCloneSourceNotFound)
+
+	result, err := client.CloneSubnet(CloneOptions{Source: "prod", Destination: "staging", ChainID: 54321, TokenName: "STG"})
+	require.NoError(err)
+	prodVMID, err := sc.GetVMID()
+	require.NoError(err)
+	require.NotEqual(prodVMID, result.VMID)
+
+	cloned, err := client.app.LoadSidecar("staging")
+	require.NoError(err)
+	require.Equal("staging", cloned.Name)
+	require.Equal("staging", cloned.Subnet)
+	require.Equal("STG", cloned.TokenName)
+	require.Equal("54321", cloned.ChainID)
+	require.Empty(cloned.Networks)
+	genesis, err := client.app.LoadEvmGenesis("staging")
+	require.NoError(err)
+	require.Equal(uint64(54321), genesis.Config.ChainID.Uint64())
+	require.True(client.app.ChainConfigExists("staging"))
+	require.True(client.app.NetworkUpgradeExists("staging"))
+	require.NoFileExists(client.app.GetUpgradeBytesFilePath("staging") + constants.UpgradeBytesLockExtension)
+
+	_, err = client.CloneSubnet(CloneOptions{Source: "prod", Destination: "staging"})
+	require.ErrorIs(err, ErrCloneExists)
+	_, err = client.CloneSubnet(CloneOptions{Source: "prod", Destination: "staging", Force: true})
+	require.NoError(err)
+	genesis, err = client.app.LoadEvmGenesis("staging")
+	require.NoError(err)
+	require.Equal(uint64(12345), genesis.Config.ChainID.Uint64())
+}
+
+func TestCloneCustomVMSubnet(t *testing.T) {
+	require := require.New(t)
+	client := newTestClient(t)
+	sc := models.Sidecar{Name: "prod", Subnet: "prod", VM: models.CustomVM, TokenName: "PROD"}
+	prodVMID, err := sc.GetVMID()
+	require.NoError(err)
+	require.NoError(os.MkdirAll(client.app.GetCustomVMDir(), constants.DefaultPerms755))
+	vmPath := client.app.GetCustomVMPath("prod")
+	require.NoError(os.WriteFile(vmPath, []byte("vm binary"), constants.DefaultPerms755))
+	binarySHA256, err := utils.GetSHA256FromDisk(vmPath)
+	require.NoError(err)
+	sc.CustomVMBuild = &models.CustomVMBuild{Commit: "abc123", BinarySHA256: binarySHA256, VMID: prodVMID}
+	require.NoError(client.app.WriteGenesisFile("prod", []byte(testGenesis)))
+	require.NoError(client.app.CreateSidecar(&sc))
+
+	result, err := client.CloneSubnet(CloneOptions{Source: "prod", Destination: "staging"})
+	require.NoError(err)
+	require.FileExists(client.app.GetCustomVMPath("staging"))
+	cloned, err := client.app.LoadSidecar("staging")
+	require.NoError(err)
+	require.Equal(result.VMID, cloned.CustomVMBuild.VMID)
+	require.Equal("abc123", cloned.CustomVMBuild.Commit)
+	require.NoError(vm.VerifyCustomVMBinary(&cloned, client.app.GetCustomVMPath("staging")))
+	// the source build record is left as it was
+	sc, err = client.app.LoadSidecar("prod")
+	require.NoError(err)
+	require.Equal(prodVMID, sc.CustomVMBuild.VMID)
+}
+
+func TestDeployInvalidGenesis(t *testing.T) {
+	require := require.New(t)
+	client := newTestClient(t)
+	// no balance is allocated and fees are not zero, so nobody can issue txs
+	_, err := client.CreateSubnet(CreateSubnetOptions{
+		Name:       "mySubnet",
+		VM:         models.SubnetEvm,
+		VMVersion:  "v0.6.0",
+		RPCVersion: 33,
+		Genesis:    []byte(testGenesis),
+		TokenName:  "TEST",
+	})
+	require.NoError(err)
+
+	_, err = client.DeployLocal(LocalDeployOptions{SubnetName: "mySubnet"})
+	require.ErrorIs(err, ErrInvalidGenesis)
+	_, err = client.Deploy(DeployOptions{
+		SubnetName: "mySubnet",
+		Network:    models.NewDevnetNetwork("http://127.0.0.1:1", 1337),
+		Keychain:   &keychain.Keychain{},
+	})
+	require.ErrorIs(err, ErrInvalidGenesis)
+}
+
+// newTestPChain serves the status of the P-Chain txs in [statuses], and the
+// bytes of the txs in [issued]
+func newTestPChain(t *testing.T, statuses map[ids.ID]string, issued map[ids.ID][]byte) models.Network {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage
+			Method string
+			Params struct {
+				TxID ids.ID `json:"txID"`
+			}
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		if request.Method == "platform.getTx" {
+			txStr, err := formatting.Encode(formatting.Hex, issued[request.Params.TxID])
+			require.NoError(t, err)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"tx":%q,"encoding":"hex"}}`, request.ID, txStr)
+			return
+		}
+		txStatus, ok := statuses[request.Params.TxID]
+		if !ok {
+			txStatus = "Unknown"
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"status":%q}}`, request.ID, txStatus)
+	}))
+	t.Cleanup(server.Close)
+	return models.NewDevnetNetwork(server.URL, 1337)
+}
+
+func TestCheckPendingDeploy(t *testing.T) {
+	require := require.New(t)
+	client := newTestClient(t)
+	require.NoError(client.app.CreateSidecar(&models.Sidecar{Name: "mySubnet", Subnet: "mySubnet"}))
+	subnetID := ids.GenerateTestID()
+	chainTxID := ids.GenerateTestID()
+	statuses := map[ids.ID]string{subnetID: "Committed"}
+	network := newTestPChain(t, statuses, nil)
+
+	pending, err := client.CheckPendingDeploy("mySubnet", network)
+	require.NoError(err)
+	require.Nil(pending)
+
+	// the subnet was created, but its blockchain tx was not accepted
+	sc, err := client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+	require.NoError(client.app.UpdateSidecarDeployStep(&sc, network, subnetID, ids.Empty, models.CreateSubnetTxStep, subnetID))
+	require.NoError(client.app.UpdateSidecarDeployStep(&sc, network, subnetID, ids.Empty, models.CreateChainTxStep, chainTxID))
+	pending, err = client.CheckPendingDeploy("mySubnet", network)
+	require.NoError(err)
+	require.Equal(&PendingDeploy{SubnetID: subnetID}, pending)
+	sc, err = client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+	networkData := sc.Networks[network.Name()]
+	require.True(networkData.Pending)
+	require.Equal(subnetID, networkData.SubnetID)
+	require.Equal(map[string]ids.ID{models.CreateSubnetTxStep: subnetID}, networkData.DeployTxs)
+
+	// the blockchain tx made it after all: the deploy is completed
+	statuses[chainTxID] = "Committed"
+	require.NoError(client.app.UpdateSidecarDeployStep(&sc, network, subnetID, ids.Empty, models.CreateChainTxStep, chainTxID))
+	pending, err = client.CheckPendingDeploy("mySubnet", network)
+	require.NoError(err)
+	require.Equal(&PendingDeploy{SubnetID: subnetID, BlockchainID: chainTxID}, pending)
+	sc, err = client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+	networkData = sc.Networks[network.Name()]
+	require.False(networkData.Pending)
+	require.Nil(networkData.DeployTxs)
+	require.Equal(chainTxID, networkData.BlockchainID)
+	require.Equal(map[string]ids.ID{"mySubnet": chainTxID}, networkData.Chains)
+
+	// a tx still being processed can't be resumed yet
+	statuses[subnetID] = "Processing"
+	require.NoError(client.app.UpdateSidecarDeployStep(&sc, network, subnetID, ids.Empty, models.CreateSubnetTxStep, subnetID))
+	_, err = client.CheckPendingDeploy("mySubnet", network)
+	require.ErrorContains(err, "still being processed")
+
+	// the subnet creation didn't make it: a new deploy starts from scratch
+	delete(statuses, subnetID)
+	pending, err = client.CheckPendingDeploy("mySubnet", network)
+	require.NoError(err)
+	require.Equal(&PendingDeploy{}, pending)
+	sc, err = client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+	require.NotContains(sc.Networks, network.Name())
+}
+
+func TestPreviewPendingDeploy(t *testing.T) {
+	require := require.New(t)
+	client := newTestClient(t)
+	require.NoError(client.app.CreateSidecar(&models.Sidecar{Name: "mySubnet", Subnet: "mySubnet"}))
+	subnetID := ids.GenerateTestID()
+	chainTxID := ids.GenerateTestID()
+	statuses := map[ids.ID]string{subnetID: "Committed", chainTxID: "Committed"}
+	network := newTestPChain(t, statuses, nil)
+	sc, err := client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+	require.NoError(client.app.UpdateSidecarDeployStep(&sc, network, subnetID, ids.Empty, models.CreateSubnetTxStep, subnetID))
+	require.NoError(client.app.UpdateSidecarDeployStep(&sc, network, subnetID, ids.Empty, models.CreateChainTxStep, chainTxID))
+	stored, err := client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+
+	// the blockchain was created, but the deploy is not recorded as completed
+	pending, previewed, err := client.PreviewPendingDeploy("mySubnet", network)
+	require.NoError(err)
+	require.Equal(&PendingDeploy{SubnetID: subnetID, BlockchainID: chainTxID}, pending)
+	require.Equal(stored, *previewed)
+	sc, err = client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+	require.Equal(stored, sc)
+
+	// nothing made it: the previewed sidecar starts from scratch, but the
+	// stored one is left as it was
+	delete(statuses, subnetID)
+	delete(statuses, chainTxID)
+	pending, previewed, err = client.PreviewPendingDeploy("mySubnet", network)
+	require.NoError(err)
+	require.Equal(&PendingDeploy{}, pending)
+	require.NotContains(previewed.Networks, network.Name())
+	sc, err = client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+	require.Equal(stored, sc)
+}
+
+// fakePublicDeployer issues no txs. Its subnet creation fails after issuing
+// the tx of [subnetID], unless [failSubnet] is false
+type fakePublicDeployer struct {
+	subnetID          ids.ID
+	failSubnet        bool
+	deployedSubnets   int
+	blockchainSubnets []ids.ID
+}
+
+func (d *fakePublicDeployer) DeploySubnet([]string, uint32) (ids.ID, error) {
+	d.deployedSubnets++
+	if d.failSubnet {
+		return d.subnetID, errors.New("timeout issuing/verifying tx")
+	}
+	return d.subnetID, nil
+}
+
+func (d *fakePublicDeployer) DeployBlockchain(
+	_ []string,
+	_ []string,
+	subnetID ids.ID,
+	_ ids.ID,
+	_ string,
+	_ []byte,
+) (bool, ids.ID, *txs.Tx, []string, error) {
+	d.blockchainSubnets = append(d.blockchainSubnets, subnetID)
+	return true, ids.GenerateTestID(), &txs.Tx{}, nil, nil
+}
+
+func TestDeployResumesIssuedSubnet(t *testing.T) {
+	require := require.New(t)
+	client := newTestClient(t)
+	_, err := client.CreateSubnet(CreateSubnetOptions{
+		Name:       "mySubnet",
+		VM:         models.SubnetEvm,
+		VMVersion:  "v0.6.0",
+		RPCVersion: 33,
+		Genesis:    []byte(testGenesis),
+		TokenName:  "TEST",
+	})
+	require.NoError(err)
+	pk, err := secp256k1.NewPrivateKey()
+	require.NoError(err)
+
+	// the subnet tx issued by the failed deploy owns the subnet
+	subnetTx := &txs.Tx{Unsigned: &txs.CreateSubnetTx{
+		Owner: &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{pk.Address()}},
+	}}
+	require.NoError(subnetTx.Initialize(txs.Codec))
+	subnetID := subnetTx.ID()
+	statuses := map[ids.ID]string{}
+	network := newTestPChain(t, statuses, map[ids.ID][]byte{subnetID: subnetTx.Bytes()})
+	kc := keychain.NewKeychain(network, secp256k1fx.NewKeychain(pk), nil, nil)
+	controlKeys, err := kc.PChainFormattedStrAddresses()
+	require.NoError(err)
+
+	deployer := &fakePublicDeployer{subnetID: subnetID, failSubnet: true}
+	defer func(prev func(*application.Avalanche, *keychain.Keychain, models.Network) publicDeployer) {
+		newPublicDeployer = prev
+	}(newPublicDeployer)
+	newPublicDeployer = func(*application.Avalanche, *keychain.Keychain, models.Network) publicDeployer {
+		return deployer
+	}
+	opts := DeployOptions{
+		SubnetName:            "mySubnet",
+		Network:               network,
+		Keychain:              kc,
+		ControlKeys:           controlKeys,
+		Threshold:             1,
+		SkipGenesisValidation: true,
+	}
+
+	_, err = client.Deploy(opts)
+	require.ErrorContains(err, "timeout issuing")
+	sc, err := client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+	networkData := sc.Networks[network.Name()]
+	require.True(networkData.Pending)
+	require.Equal(subnetID, networkData.SubnetID)
+	require.Equal(map[string]ids.ID{models.CreateSubnetTxStep: subnetID}, networkData.DeployTxs)
+
+	// the subnet tx was accepted after all: the retry deploys the blockchain
+	// into it instead of creating another subnet
+	statuses[subnetID] = "Committed"
+	deployer.failSubnet = false
+	result, err := client.Deploy(opts)
+	require.NoError(err)
+	require.Equal(1, deployer.deployedSubnets)
+	require.Equal([]ids.ID{subnetID}, deployer.blockchainSubnets)
+	require.False(result.CreatedSubnet)
+	require.Equal(subnetID, result.SubnetID)
+	sc, err = client.app.LoadSidecar("mySubnet")
+	require.NoError(err)
+	require.False(sc.Networks[network.Name()].Pending)
+	require.Equal(result.BlockchainID, sc.Networks[network.Name()].BlockchainID)
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package sdk
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/teleporter"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// TeleporterDeployOptions configures [Client.DeployTeleporter].
+type TeleporterDeployOptions struct {
+	SubnetName string
+	Network    models.Network
+}
+
+// TeleporterDeployResult describes the outcome of [Client.DeployTeleporter].
+type TeleporterDeployResult struct {
+	MessengerAddress string
+	RegistryAddress  string
+	// AlreadyDeployed is set if teleporter was found already deployed on the subnet
+	AlreadyDeployed bool
+}
+
+// DeployTeleporter deploys the teleporter messenger and registry into a
+// deployed teleporter ready subnet, funding its relayer. On local networks
+// and devnets, it is also deployed into the C-Chain.
+func (c *Client) DeployTeleporter(opts TeleporterDeployOptions) (*TeleporterDeployResult, error) {
+	sc, err := c.app.LoadSidecar(opts.SubnetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sidecar: %w", err)
+	}
+	if !sc.TeleporterReady {
+		return nil, errors.New("subnet is not configured for teleporter")
+	}
+	genesis, err := c.app.LoadRawGenesis(opts.SubnetName)
+	if err != nil {
+		return nil, err
+	}
+	if !IsSubnetEVMGenesis(genesis) {
+		return nil, errors.New("only Subnet-EVM based vms can be used for teleporter")
+	}
+	networkInfo := sc.Networks[opts.Network.Name()]
+	if networkInfo.BlockchainID == ids.Empty {
+		return nil, fmt.Errorf("subnet has not been deployed to %s", opts.Network.Name())
+	}
+	// deploy to subnet
+	alreadyDeployed, messengerAddress, registryAddress, err := teleporter.DeployAndFundRelayer(
+		c.app,
+		sc.TeleporterVersion,
+		opts.Network,
+		opts.SubnetName,
+		networkInfo.BlockchainID.String(),
+		sc.TeleporterKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	result := &TeleporterDeployResult{
+		MessengerAddress: messengerAddress,
+		RegistryAddress:  registryAddress,
+		AlreadyDeployed:  alreadyDeployed,
+	}
+	if !alreadyDeployed {
+		// update sidecar
+		networkInfo.TeleporterMessengerAddress = messengerAddress
+		networkInfo.TeleporterRegistryAddress = registryAddress
+		sc.Networks[opts.Network.Name()] = networkInfo
+		if err := c.app.UpdateSidecar(&sc); err != nil {
+			return nil, err
+		}
+	}
+	// deploy to cchain for local
+	if opts.Network.Kind == models.Local || opts.Network.Kind == models.Devnet {
+		alreadyDeployed, messengerAddress, registryAddress, err = teleporter.DeployAndFundRelayer(
+			c.app,
+			sc.TeleporterVersion,
+			opts.Network,
+			"c-chain",
+			"C",
+			"",
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !alreadyDeployed {
+			if opts.Network.Kind == models.Local {
+				if err := subnet.WriteExtraLocalNetworkData(c.app, messengerAddress, registryAddress); err != nil {
+					return nil, err
+				}
+			}
+			if opts.Network.ClusterName != "" {
+				clusterConfig, err := c.app.GetClusterConfig(opts.Network.ClusterName)
+				if err != nil {
+					return nil, err
+				}
+				clusterConfig.ExtraNetworkData = models.ExtraNetworkData{
+					CChainTeleporterMessengerAddress: messengerAddress,
+					CChainTeleporterRegistryAddress:  registryAddress,
+				}
+				if err := c.app.SetClusterConfig(opts.Network.ClusterName, clusterConfig); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return result, nil
+}
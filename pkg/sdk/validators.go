@@ -0,0 +1,210 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package sdk
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/txutils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// ValidatorOptions holds the inputs common to validator set changes of a
+// permissioned subnet.
+type ValidatorOptions struct {
+	SubnetName string
+	Network    models.Network
+	// Keychain pays the fees and signs the txs
+	Keychain *keychain.Keychain
+	NodeID   ids.NodeID
+	// ControlKeys and Threshold of the subnet. Queried from the P-Chain when empty
+	ControlKeys []string
+	Threshold   uint32
+	// SubnetAuthKeys sign the tx. When nil, they are selected automatically
+	// if this can be done without ambiguity
+	SubnetAuthKeys []string
+}
+
+// AddValidatorOptions configures [Client.AddValidator].
+type AddValidatorOptions struct {
+	ValidatorOptions
+	Weight    uint64
+	StartTime time.Time
+	Duration  time.Duration
+	// JustIssueTx issues the tx without waiting for its acceptance
+	JustIssueTx bool
+}
+
+// RemoveValidatorOptions configures [Client.RemoveValidator].
+type RemoveValidatorOptions struct {
+	ValidatorOptions
+}
+
+// AddValidator adds a node to the validator set of a deployed permissioned subnet.
+func (c *Client) AddValidator(opts AddValidatorOptions) (*TxResult, error) {
+	if opts.Weight < constants.MinStakeWeight {
+		return nil, fmt.Errorf("illegal weight, must be greater than or equal to %d: %d", constants.MinStakeWeight, opts.Weight)
+	}
+	if opts.Duration <= 0 {
+		return nil, fmt.Errorf("validation duration must be positive: %s", opts.Duration)
+	}
+	networkData, controlKeys, subnetAuthKeys, err := c.prepareValidatorTx(opts.ValidatorOptions)
+	if err != nil {
+		return nil, err
+	}
+	deployer := subnet.NewPublicDeployer(c.app, opts.Keychain, opts.Network)
+	isFullySigned, tx, remainingSubnetAuthKeys, err := deployer.AddValidator(
+		opts.JustIssueTx,
+		controlKeys,
+		subnetAuthKeys,
+		networkData.SubnetID,
+		networkData.TransferSubnetOwnershipTxID,
+		opts.NodeID,
+		opts.Weight,
+		opts.StartTime,
+		opts.Duration,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if isFullySigned {
+		if err := c.RecordValidatorTx(opts.SubnetName, opts.Network, opts.NodeID, tx.ID()); err != nil {
+			return nil, err
+		}
+	}
+	return &TxResult{
+		Tx:                      tx,
+		FullySigned:             isFullySigned,
+		SubnetAuthKeys:          subnetAuthKeys,
+		RemainingSubnetAuthKeys: remainingSubnetAuthKeys,
+	}, nil
+}
+
+// RecordValidatorTx records the add validator tx [txID] of [nodeID] in the
+// sidecar of the subnet [chainName] is deployed into. An empty [txID]
+// forgets the validator
+func (c *Client) RecordValidatorTx(chainName string, network models.Network, nodeID ids.NodeID, txID ids.ID) error {
+	sc, err := c.app.LoadSidecar(chainName)
+	if err != nil {
+		return err
+	}
+	if sc.IsAddedChain() {
+		sc, err = c.app.LoadSidecar(sc.GetSubnetName())
+		if err != nil {
+			return err
+		}
+	}
+	if _, ok := sc.Networks[network.Name()]; !ok {
+		return nil
+	}
+	if err := c.app.UpdateSidecarValidatorTx(&sc, network, nodeID, txID); err != nil {
+		return fmt.Errorf("validator set was updated, but failed to update sidecar: %w", err)
+	}
+	return nil
+}
+
+// RemoveValidator removes a node from the validator set of a deployed
+// permissioned subnet.
+func (c *Client) RemoveValidator(opts RemoveValidatorOptions) (*TxResult, error) {
+	networkData, controlKeys, subnetAuthKeys, err := c.prepareValidatorTx(opts.ValidatorOptions)
+	if err != nil {
+		return nil, err
+	}
+	// check that this node actually is a validator on the subnet
+	isValidator, err := subnet.IsSubnetValidator(networkData.SubnetID, opts.NodeID, opts.Network)
+	if err != nil {
+		// just warn, don't fail
+		ux.Logger.PrintToUser("failed to check if node is a validator on the subnet: %s", err)
+	} else if !isValidator {
+		return nil, fmt.Errorf("node %s is not a validator on subnet %s", opts.NodeID, networkData.SubnetID)
+	}
+	deployer := subnet.NewPublicDeployer(c.app, opts.Keychain, opts.Network)
+	isFullySigned, tx, remainingSubnetAuthKeys, err := deployer.RemoveValidator(
+		controlKeys,
+		subnetAuthKeys,
+		networkData.SubnetID,
+		networkData.TransferSubnetOwnershipTxID,
+		opts.NodeID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if isFullySigned {
+		if err := c.RecordValidatorTx(opts.SubnetName, opts.Network, opts.NodeID, ids.Empty); err != nil {
+			return nil, err
+		}
+	}
+	return &TxResult{
+		Tx:                      tx,
+		FullySigned:             isFullySigned,
+		SubnetAuthKeys:          subnetAuthKeys,
+		RemainingSubnetAuthKeys: remainingSubnetAuthKeys,
+	}, nil
+}
+
+func (c *Client) prepareValidatorTx(opts ValidatorOptions) (models.NetworkData, []string, []string, error) {
+	if opts.Keychain == nil {
+		return models.NetworkData{}, nil, nil, ErrMissingKeychain
+	}
+	if opts.NodeID == ids.EmptyNodeID {
+		return models.NetworkData{}, nil, nil, fmt.Errorf("a node ID is required")
+	}
+	_, networkData, err := c.loadDeployedSidecar(opts.SubnetName, opts.Network)
+	if err != nil {
+		return models.NetworkData{}, nil, nil, err
+	}
+	controlKeys, threshold := opts.ControlKeys, opts.Threshold
+	if len(controlKeys) == 0 {
+		controlKeys, threshold, err = txutils.GetOwners(opts.Network, networkData.SubnetID, networkData.TransferSubnetOwnershipTxID)
+		if err != nil {
+			return models.NetworkData{}, nil, nil, err
+		}
+	}
+	// add control keys to the keychain whenever possible
+	if err := opts.Keychain.AddAddresses(controlKeys); err != nil {
+		return models.NetworkData{}, nil, nil, err
+	}
+	subnetAuthKeys, err := selectSubnetAuthKeys(opts.Keychain, opts.SubnetAuthKeys, controlKeys, threshold)
+	if err != nil {
+		return models.NetworkData{}, nil, nil, err
+	}
+	return networkData, controlKeys, subnetAuthKeys, nil
+}
+
+// selectSubnetAuthKeys validates the given subnet auth keys or, if none
+// were given, selects them: all control keys if all are required, or the
+// control keys the keychain holds if they match the threshold.
+func selectSubnetAuthKeys(kc *keychain.Keychain, subnetAuthKeys []string, controlKeys []string, threshold uint32) ([]string, error) {
+	kcKeys, err := kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return nil, err
+	}
+	if subnetAuthKeys != nil {
+		if err := prompts.CheckSubnetAuthKeys(kcKeys, subnetAuthKeys, controlKeys, threshold); err != nil {
+			return nil, err
+		}
+		return subnetAuthKeys, nil
+	}
+	if len(controlKeys) == int(threshold) {
+		return controlKeys, nil
+	}
+	selected := []string{}
+	for _, kcKey := range kcKeys {
+		if slices.Contains(controlKeys, kcKey) {
+			selected = append(selected, kcKey)
+		}
+	}
+	if len(selected) != int(threshold) {
+		return nil, fmt.Errorf("subnet auth keys must be given: %d of the %d control keys are required and the keychain holds %d of them",
+			threshold, len(controlKeys), len(selected))
+	}
+	return selected, nil
+}
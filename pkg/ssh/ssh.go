@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
@@ -550,6 +551,21 @@ func RunSSHUploadClustersConfig(host *models.Host, localClustersConfigPath strin
 	)
 }
 
+// RunSSHUploadUpgradeBytes uploads the upgrade file at [upgradeBytesPath] to [remoteUpgradeBytesPath]
+func RunSSHUploadUpgradeBytes(host *models.Host, upgradeBytesPath string, remoteUpgradeBytesPath string) error {
+	if err := host.MkdirAll(
+		filepath.Dir(remoteUpgradeBytesPath),
+		constants.SSHDirOpsTimeout,
+	); err != nil {
+		return err
+	}
+	return host.Upload(
+		upgradeBytesPath,
+		remoteUpgradeBytesPath,
+		constants.SSHFileOpsTimeout,
+	)
+}
+
 // RunSSHUploadStakingFiles uploads staking files to a remote host via SSH.
 func RunSSHUploadStakingFiles(host *models.Host, nodeInstanceDirPath string) error {
 	if err := host.MkdirAll(
@@ -600,6 +616,20 @@ func RunSSHTrackSubnet(host *models.Host, subnetName, importPath, networkFlag st
 	)
 }
 
+// RunSSHGetPluginSHA256 returns the SHA-256 of the VM binary installed for [vmID]
+func RunSSHGetPluginSHA256(host *models.Host, vmID string) (string, error) {
+	pluginPath := fmt.Sprintf(constants.CloudNodeSubnetEvmBinaryPath, vmID)
+	out, err := host.Command("sha256sum "+pluginPath, nil, constants.SSHScriptTimeout)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the sha256 of %s: %w", pluginPath, err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("unexpected sha256sum output for %s: %q", pluginPath, out)
+	}
+	return fields[0], nil
+}
+
 // RunSSHUpdateSubnet runs avalanche subnet join <subnetName> in cloud server using update subnet info
 func RunSSHUpdateSubnet(host *models.Host, subnetName, importPath string) error {
 	return RunOverSSH(
@@ -688,6 +718,13 @@ func RunSSHCheckBootstrapped(host *models.Host) ([]byte, error) {
 	return PostOverSSH(host, "", requestBody)
 }
 
+// RunSSHCheckChainBootstrapped checks if node has bootstrapped chain [chainID]
+func RunSSHCheckChainBootstrapped(host *models.Host, chainID string) ([]byte, error) {
+	// Craft and send the HTTP POST request
+	requestBody := fmt.Sprintf("{\"jsonrpc\":\"2.0\", \"id\":1,\"method\" :\"info.isBootstrapped\", \"params\": {\"chain\":\"%s\"}}", chainID)
+	return PostOverSSH(host, "", requestBody)
+}
+
 // RunSSHCheckHealthy checks if node is healthy
 func RunSSHCheckHealthy(host *models.Host) ([]byte, error) {
 	// Craft and send the HTTP POST request
@@ -709,6 +746,11 @@ func RunSSHSubnetSyncStatus(host *models.Host, blockchainID string) ([]byte, err
 	return PostOverSSH(host, "/ext/bc/P", requestBody)
 }
 
+// RunSSHChainRPC posts a JSON-RPC request to the RPC API of blockchain [blockchainID]
+func RunSSHChainRPC(host *models.Host, blockchainID string, requestBody string) ([]byte, error) {
+	return PostOverSSH(host, fmt.Sprintf("/ext/bc/%s/rpc", blockchainID), requestBody)
+}
+
 // StreamOverSSH runs provided script path over ssh.
 // This script can be template as it will be rendered using scriptInputs vars
 func StreamOverSSH(
@@ -0,0 +1,108 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+)
+
+const (
+	// ExpiryRenewable validators can be re-added once their period ends
+	ExpiryRenewable = "renewable"
+	// ExpiryPrimaryExpiring validators can't be renewed until the primary
+	// network validation of the node is extended
+	ExpiryPrimaryExpiring = "primary validator expiring"
+	// ExpiryNotPrimaryValidator validators are on nodes that no longer
+	// validate the primary network
+	ExpiryNotPrimaryValidator = "not a primary network validator"
+)
+
+// ExpiringValidator is a subnet validator whose period ends soon, or has
+// already ended
+type ExpiringValidator struct {
+	NodeID  ids.NodeID
+	Weight  uint64
+	EndTime time.Time
+	// Ended is set when the node is no longer a validator of the subnet
+	Ended bool
+	// PrimaryEndTime is the end of the primary network validation of the
+	// node. Zero if the node doesn't validate the primary network
+	PrimaryEndTime time.Time
+	Status         string
+}
+
+// IsRenewable tells if the validator can be re-added with a new period
+func (v ExpiringValidator) IsRenewable() bool {
+	return v.Status == ExpiryRenewable
+}
+
+// ExpiringValidators returns the current [subnetValidators] whose period ends
+// by [now] + [window], and the [pastValidators] whose period has ended and
+// that are no longer subnet validators, sorted by end time. As a subnet
+// validation can't outlast the primary network validation of the node, the
+// validators whose primary validation also ends within the window are
+// flagged as not renewable
+func ExpiringValidators(
+	subnetValidators []platformvm.ClientPermissionlessValidator,
+	pastValidators []platformvm.ClientPermissionlessValidator,
+	primaryValidators []platformvm.ClientPermissionlessValidator,
+	now time.Time,
+	window time.Duration,
+) []ExpiringValidator {
+	deadline := now.Add(window)
+	primaryEndTimes := map[ids.NodeID]time.Time{}
+	for _, validator := range primaryValidators {
+		primaryEndTimes[validator.NodeID] = time.Unix(int64(validator.EndTime), 0)
+	}
+	current := map[ids.NodeID]bool{}
+	candidates := []ExpiringValidator{}
+	for _, validator := range subnetValidators {
+		current[validator.NodeID] = true
+		endTime := time.Unix(int64(validator.EndTime), 0)
+		if endTime.After(deadline) {
+			continue
+		}
+		candidates = append(candidates, ExpiringValidator{
+			NodeID:  validator.NodeID,
+			Weight:  validator.Weight,
+			EndTime: endTime,
+		})
+	}
+	for _, validator := range pastValidators {
+		endTime := time.Unix(int64(validator.EndTime), 0)
+		// validators removed before the end of their period are not renewed
+		if current[validator.NodeID] || endTime.After(now) {
+			continue
+		}
+		current[validator.NodeID] = true
+		candidates = append(candidates, ExpiringValidator{
+			NodeID:  validator.NodeID,
+			Weight:  validator.Weight,
+			EndTime: endTime,
+			Ended:   true,
+		})
+	}
+	expiring := []ExpiringValidator{}
+	for _, v := range candidates {
+		primaryEndTime, ok := primaryEndTimes[v.NodeID]
+		switch {
+		case !ok:
+			v.Status = ExpiryNotPrimaryValidator
+		case !primaryEndTime.After(deadline):
+			v.PrimaryEndTime = primaryEndTime
+			v.Status = ExpiryPrimaryExpiring
+		default:
+			v.PrimaryEndTime = primaryEndTime
+			v.Status = ExpiryRenewable
+		}
+		expiring = append(expiring, v)
+	}
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].EndTime.Before(expiring[j].EndTime)
+	})
+	return expiring
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiringValidators(t *testing.T) {
+	require := require.New(t)
+	now := time.Unix(1_700_000_000, 0)
+	day := 24 * time.Hour
+	staker := func(nodeID ids.NodeID, end time.Duration) platformvm.ClientPermissionlessValidator {
+		return platformvm.ClientPermissionlessValidator{ClientStaker: platformvm.ClientStaker{
+			NodeID:  nodeID,
+			Weight:  20,
+			EndTime: uint64(now.Add(end).Unix()),
+		}}
+	}
+	renewable, primaryExpiring, notPrimary, later, ended, removed := ids.GenerateTestNodeID(),
+		ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID(),
+		ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	subnetValidators := []platformvm.ClientPermissionlessValidator{
+		staker(later, 30*day),
+		staker(renewable, 10*day),
+		staker(primaryExpiring, 3*day),
+		staker(notPrimary, -day),
+	}
+	// past add validator txs, of validators no longer listed by the P-Chain
+	pastValidators := []platformvm.ClientPermissionlessValidator{
+		staker(ended, -2*day),
+		// removed before the end of its period
+		staker(removed, 5*day),
+		// renewed since, and still validating
+		staker(later, -60*day),
+	}
+	primaryValidators := []platformvm.ClientPermissionlessValidator{
+		staker(ended, 90*day),
+		staker(removed, 90*day),
+		staker(later, 60*day),
+		staker(renewable, 90*day),
+		// subnet validations added with --default-duration end with the primary one
+		staker(primaryExpiring, 3*day),
+	}
+
+	expiring := ExpiringValidators(subnetValidators, pastValidators, primaryValidators, now, 14*day)
+	require.Len(expiring, 4)
+	require.Equal(ExpiringValidator{
+		NodeID:         ended,
+		Weight:         20,
+		EndTime:        now.Add(-2 * day),
+		Ended:          true,
+		PrimaryEndTime: now.Add(90 * day),
+		Status:         ExpiryRenewable,
+	}, expiring[0])
+	require.Equal(ExpiringValidator{
+		NodeID:  notPrimary,
+		Weight:  20,
+		EndTime: now.Add(-day),
+		Status:  ExpiryNotPrimaryValidator,
+	}, expiring[1])
+	require.Equal(primaryExpiring, expiring[2].NodeID)
+	require.Equal(ExpiryPrimaryExpiring, expiring[2].Status)
+	require.False(expiring[2].IsRenewable())
+	require.Equal(renewable, expiring[3].NodeID)
+	require.False(expiring[3].Ended)
+	require.Equal(now.Add(90*day), expiring[3].PrimaryEndTime)
+	require.True(expiring[3].IsRenewable())
+
+	require.Len(ExpiringValidators(subnetValidators, pastValidators, primaryValidators, now, 30*day), 5)
+	require.Empty(ExpiringValidators(subnetValidators, nil, primaryValidators, now.Add(-2*day), 0))
+}
@@ -66,6 +66,7 @@ type LocalDeployer struct {
 	avagoVersion       string
 	avagoBinaryPath    string
 	vmBin              string
+	networkUpgradePath string
 }
 
 // uses either avagoVersion or avagoBinaryPath
@@ -88,6 +89,12 @@ func NewLocalDeployer(
 	}
 }
 
+// SetNetworkUpgrade makes the deployed blockchain start with the upgrade
+// bytes at [path]
+func (d *LocalDeployer) SetNetworkUpgrade(path string) {
+	d.networkUpgradePath = path
+}
+
 type getGRPCClientFunc func(...binutils.GRPCClientOpOption) (client.Client, error)
 
 type setDefaultSnapshotFunc func(string, bool, string, bool) (bool, error)
@@ -534,6 +541,7 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 				SubnetConfig: subnetConfig,
 			},
 			ChainConfig:        chainConfig,
+			NetworkUpgrade:     d.networkUpgradePath,
 			BlockchainAlias:    chain,
 			PerNodeChainConfig: perNodeChainConfig,
 		},
@@ -0,0 +1,496 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/keychain"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	anrutils "github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	PChain = "P"
+	XChain = "X"
+)
+
+// TxPlanStep is a tx that a command would issue.
+type TxPlanStep struct {
+	Name string
+	// Chain is the alias of the chain the tx is issued to
+	Chain string
+	// Fee is the amount of AVAX burnt by the tx
+	Fee uint64
+	// Spent is the amount of each asset, besides the fee, that the tx takes
+	// from the keychain balance on [Chain], such as stake or exported funds.
+	// Funds produced by previous steps of the plan are not included
+	Spent map[ids.ID]uint64
+	// Signers is set if the tx has to be signed by the subnet control keys
+	Signers *TxPlanSigners
+	// Built is set if the tx could be built from the current chain state.
+	// Otherwise it depends on the outputs of a previous step, and only its
+	// fee is known
+	Built bool
+}
+
+// TxPlanSigners describes who signs a tx authorized by the subnet control keys.
+type TxPlanSigners struct {
+	ControlKeys    []string
+	Threshold      uint32
+	SubnetAuthKeys []string
+	// Remaining are the subnet auth keys missing in the keychain, that have to
+	// sign the tx after it is created
+	Remaining []string
+}
+
+// TxPlan collects the txs a command would issue, building them against the
+// current chain state without signing nor issuing them. It is used to check
+// fees, balances and signers before anything is broadcast.
+type TxPlan struct {
+	deployer   *PublicDeployer
+	preloadTxs []ids.ID
+	Steps      []TxPlanStep
+}
+
+// NewTxPlan creates an empty plan for txs paid by [kc]. [preloadTxs] are the
+// P-Chain txs defining the owners of the subnets the txs are authorized by
+func NewTxPlan(app *application.Avalanche, kc *keychain.Keychain, network models.Network, preloadTxs ...ids.ID) *TxPlan {
+	return &TxPlan{
+		deployer:   NewPublicDeployer(app, kc, network),
+		preloadTxs: preloadTxs,
+	}
+}
+
+func (p *TxPlan) wallet() (primary.Wallet, error) {
+	return p.deployer.loadCacheWallet(p.preloadTxs...)
+}
+
+func (p *TxPlan) signers(controlKeys []string, threshold uint32, subnetAuthKeys []string) (*TxPlanSigners, error) {
+	if threshold == 0 || int(threshold) > len(controlKeys) {
+		return nil, fmt.Errorf("threshold must be between 1 and the number of control keys (%d): %d", len(controlKeys), threshold)
+	}
+	kcKeys, err := p.deployer.kc.PChainFormattedStrAddresses()
+	if err != nil {
+		return nil, err
+	}
+	if err := prompts.CheckSubnetAuthKeys(kcKeys, subnetAuthKeys, controlKeys, threshold); err != nil {
+		return nil, err
+	}
+	signers := &TxPlanSigners{
+		ControlKeys:    controlKeys,
+		Threshold:      threshold,
+		SubnetAuthKeys: subnetAuthKeys,
+	}
+	for _, subnetAuthKey := range subnetAuthKeys {
+		if !slices.Contains(kcKeys, subnetAuthKey) {
+			signers.Remaining = append(signers.Remaining, subnetAuthKey)
+		}
+	}
+	return signers, nil
+}
+
+// CreateSubnet plans the creation of a subnet owned by [controlKeys]
+func (p *TxPlan) CreateSubnet(controlKeys []string, threshold uint32) error {
+	if threshold == 0 || int(threshold) > len(controlKeys) {
+		return fmt.Errorf("threshold must be between 1 and the number of control keys (%d): %d", len(controlKeys), threshold)
+	}
+	wallet, err := p.wallet()
+	if err != nil {
+		return err
+	}
+	addrs, err := address.ParseToIDs(controlKeys)
+	if err != nil {
+		return fmt.Errorf("failure parsing control keys: %w", err)
+	}
+	if _, err := wallet.P().Builder().NewCreateSubnetTx(&secp256k1fx.OutputOwners{
+		Addrs:     addrs,
+		Threshold: threshold,
+	}); err != nil {
+		return fmt.Errorf("error building CreateSubnetTx: %w", err)
+	}
+	p.Steps = append(p.Steps, TxPlanStep{
+		Name:  "CreateSubnetTx",
+		Chain: PChain,
+		Fee:   wallet.P().Builder().Context().CreateSubnetTxFee,
+		Built: true,
+	})
+	return nil
+}
+
+// CreateChain plans the creation of blockchain [chain] into [subnetID], or
+// into the subnet created by a previous step if it is ids.Empty
+func (p *TxPlan) CreateChain(
+	subnetID ids.ID,
+	controlKeys []string,
+	threshold uint32,
+	subnetAuthKeysStrs []string,
+	chain string,
+	genesis []byte,
+) error {
+	signers, err := p.signers(controlKeys, threshold, subnetAuthKeysStrs)
+	if err != nil {
+		return err
+	}
+	wallet, err := p.wallet()
+	if err != nil {
+		return err
+	}
+	step := TxPlanStep{
+		Name:    "CreateChainTx",
+		Chain:   PChain,
+		Fee:     wallet.P().Builder().Context().CreateBlockchainTxFee,
+		Signers: signers,
+	}
+	if subnetID != ids.Empty {
+		vmID, err := anrutils.VMID(chain)
+		if err != nil {
+			return fmt.Errorf("failed to create VM ID from %s: %w", chain, err)
+		}
+		subnetAuthKeys, err := address.ParseToIDs(subnetAuthKeysStrs)
+		if err != nil {
+			return fmt.Errorf("failure parsing subnet auth keys: %w", err)
+		}
+		if _, err := wallet.P().Builder().NewCreateChainTx(
+			subnetID,
+			genesis,
+			vmID,
+			nil,
+			chain,
+			p.deployer.getMultisigTxOptions(subnetAuthKeys)...,
+		); err != nil {
+			return fmt.Errorf("error building CreateChainTx: %w", err)
+		}
+		step.Built = true
+	}
+	p.Steps = append(p.Steps, step)
+	return nil
+}
+
+// AddSubnetValidator plans adding [nodeID] as a validator of permissioned
+// subnet [subnetID]
+func (p *TxPlan) AddSubnetValidator(
+	subnetID ids.ID,
+	controlKeys []string,
+	threshold uint32,
+	subnetAuthKeysStrs []string,
+	nodeID ids.NodeID,
+	weight uint64,
+	startTime time.Time,
+	duration time.Duration,
+) error {
+	signers, err := p.signers(controlKeys, threshold, subnetAuthKeysStrs)
+	if err != nil {
+		return err
+	}
+	wallet, err := p.wallet()
+	if err != nil {
+		return err
+	}
+	subnetAuthKeys, err := address.ParseToIDs(subnetAuthKeysStrs)
+	if err != nil {
+		return fmt.Errorf("failure parsing subnet auth keys: %w", err)
+	}
+	if _, err := wallet.P().Builder().NewAddSubnetValidatorTx(
+		&txs.SubnetValidator{
+			Validator: txs.Validator{
+				NodeID: nodeID,
+				Start:  uint64(startTime.Unix()),
+				End:    uint64(startTime.Add(duration).Unix()),
+				Wght:   weight,
+			},
+			Subnet: subnetID,
+		},
+		p.deployer.getMultisigTxOptions(subnetAuthKeys)...,
+	); err != nil {
+		return fmt.Errorf("error building AddSubnetValidatorTx: %w", err)
+	}
+	p.Steps = append(p.Steps, TxPlanStep{
+		Name:    "AddSubnetValidatorTx",
+		Chain:   PChain,
+		Fee:     wallet.P().Builder().Context().AddSubnetValidatorFee,
+		Signers: signers,
+		Built:   true,
+	})
+	return nil
+}
+
+// CreateAsset plans the creation of the X-Chain asset of an elastic subnet,
+// minting [maxSupply] to [recipientAddr]
+func (p *TxPlan) CreateAsset(
+	tokenName string,
+	tokenSymbol string,
+	denomination byte,
+	maxSupply uint64,
+	recipientAddr ids.ShortID,
+) error {
+	wallet, err := p.wallet()
+	if err != nil {
+		return err
+	}
+	if _, err := wallet.X().Builder().NewCreateAssetTx(
+		tokenName,
+		tokenSymbol,
+		denomination,
+		map[uint32][]verify.State{
+			0: {
+				&secp256k1fx.TransferOutput{
+					Amt: maxSupply,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{recipientAddr},
+					},
+				},
+			},
+		},
+	); err != nil {
+		return fmt.Errorf("error building CreateAssetTx: %w", err)
+	}
+	p.Steps = append(p.Steps, TxPlanStep{
+		Name:  "CreateAssetTx",
+		Chain: XChain,
+		Fee:   wallet.X().Builder().Context().CreateAssetTxFee,
+		Built: true,
+	})
+	return nil
+}
+
+// ExportToPChain plans the export of [amount] of [assetID] from the X-Chain to
+// the P-Chain, or of the asset created by a previous step if it is ids.Empty
+func (p *TxPlan) ExportToPChain(assetID ids.ID, amount uint64, recipientAddr ids.ShortID) error {
+	wallet, err := p.wallet()
+	if err != nil {
+		return err
+	}
+	step := TxPlanStep{
+		Name:  "ExportTx",
+		Chain: XChain,
+		Fee:   wallet.X().Builder().Context().BaseTxFee,
+	}
+	if assetID != ids.Empty {
+		if _, err := wallet.X().Builder().NewExportTx(
+			avagoconstants.PlatformChainID,
+			[]*avax.TransferableOutput{
+				{
+					Asset: avax.Asset{ID: assetID},
+					Out: &secp256k1fx.TransferOutput{
+						Amt: amount,
+						OutputOwners: secp256k1fx.OutputOwners{
+							Threshold: 1,
+							Addrs:     []ids.ShortID{recipientAddr},
+						},
+					},
+				},
+			},
+		); err != nil {
+			return fmt.Errorf("error building ExportTx: %w", err)
+		}
+		step.Spent = map[ids.ID]uint64{assetID: amount}
+		step.Built = true
+	}
+	p.Steps = append(p.Steps, step)
+	return nil
+}
+
+// ImportFromXChain plans the import into the P-Chain of the funds exported
+// from the X-Chain. If [exported] is false, the funds are exported by a
+// previous step
+func (p *TxPlan) ImportFromXChain(exported bool, recipientAddr ids.ShortID) error {
+	wallet, err := p.wallet()
+	if err != nil {
+		return err
+	}
+	step := TxPlanStep{
+		Name:  "ImportTx",
+		Chain: PChain,
+		Fee:   wallet.P().Builder().Context().BaseTxFee,
+	}
+	if exported {
+		if _, err := wallet.P().Builder().NewImportTx(
+			wallet.X().Builder().Context().BlockchainID,
+			&secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{recipientAddr},
+			},
+		); err != nil {
+			return fmt.Errorf("error building ImportTx: %w", err)
+		}
+		step.Built = true
+	}
+	p.Steps = append(p.Steps, step)
+	return nil
+}
+
+// TransformSubnet plans the transformation of [elasticSubnetConfig.SubnetID]
+// into an elastic subnet using [assetID]. If [assetInPChain] is false, the
+// asset is moved into the P-Chain by previous steps
+func (p *TxPlan) TransformSubnet(
+	controlKeys []string,
+	threshold uint32,
+	subnetAuthKeysStrs []string,
+	elasticSubnetConfig models.ElasticSubnetConfig,
+	assetID ids.ID,
+	assetInPChain bool,
+) error {
+	signers, err := p.signers(controlKeys, threshold, subnetAuthKeysStrs)
+	if err != nil {
+		return err
+	}
+	wallet, err := p.wallet()
+	if err != nil {
+		return err
+	}
+	step := TxPlanStep{
+		Name:    "TransformSubnetTx",
+		Chain:   PChain,
+		Fee:     wallet.P().Builder().Context().TransformSubnetTxFee,
+		Signers: signers,
+	}
+	if assetInPChain {
+		subnetAuthKeys, err := address.ParseToIDs(subnetAuthKeysStrs)
+		if err != nil {
+			return fmt.Errorf("failure parsing subnet auth keys: %w", err)
+		}
+		if _, err := wallet.P().Builder().NewTransformSubnetTx(elasticSubnetConfig.SubnetID, assetID,
+			elasticSubnetConfig.InitialSupply, elasticSubnetConfig.MaxSupply, elasticSubnetConfig.MinConsumptionRate,
+			elasticSubnetConfig.MaxConsumptionRate, elasticSubnetConfig.MinValidatorStake, elasticSubnetConfig.MaxValidatorStake,
+			elasticSubnetConfig.MinStakeDuration, elasticSubnetConfig.MaxStakeDuration, elasticSubnetConfig.MinDelegationFee,
+			elasticSubnetConfig.MinDelegatorStake, elasticSubnetConfig.MaxValidatorWeightFactor, elasticSubnetConfig.UptimeRequirement,
+			p.deployer.getMultisigTxOptions(subnetAuthKeys)...,
+		); err != nil {
+			return fmt.Errorf("error building TransformSubnetTx: %w", err)
+		}
+		step.Spent = map[ids.ID]uint64{assetID: elasticSubnetConfig.MaxSupply - elasticSubnetConfig.InitialSupply}
+		step.Built = true
+	}
+	p.Steps = append(p.Steps, step)
+	return nil
+}
+
+// AddPermissionlessValidator plans adding [nodeID] as a validator of elastic
+// subnet [subnetID], staking [stakeAmount] of [assetID]
+func (p *TxPlan) AddPermissionlessValidator(
+	subnetID ids.ID,
+	assetID ids.ID,
+	nodeID ids.NodeID,
+	stakeAmount uint64,
+	startTime uint64,
+	endTime uint64,
+	recipientAddr ids.ShortID,
+	delegationFee uint32,
+) error {
+	wallet, err := p.wallet()
+	if err != nil {
+		return err
+	}
+	owner := &secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{recipientAddr},
+	}
+	if _, err := wallet.P().Builder().NewAddPermissionlessValidatorTx(
+		&txs.SubnetValidator{
+			Validator: txs.Validator{
+				NodeID: nodeID,
+				Start:  startTime,
+				End:    endTime,
+				Wght:   stakeAmount,
+			},
+			Subnet: subnetID,
+		},
+		&signer.Empty{},
+		assetID,
+		owner,
+		owner,
+		delegationFee,
+		p.deployer.getMultisigTxOptions([]ids.ShortID{})...,
+	); err != nil {
+		return fmt.Errorf("error building AddPermissionlessValidatorTx: %w", err)
+	}
+	p.Steps = append(p.Steps, TxPlanStep{
+		Name:  "AddPermissionlessValidatorTx",
+		Chain: PChain,
+		Fee:   wallet.P().Builder().Context().AddSubnetValidatorFee,
+		Spent: map[ids.ID]uint64{assetID: stakeAmount},
+		Built: true,
+	})
+	return nil
+}
+
+// AVAXAssetID returns the ID of the asset fees are paid with
+func (p *TxPlan) AVAXAssetID() (ids.ID, error) {
+	wallet, err := p.wallet()
+	if err != nil {
+		return ids.Empty, err
+	}
+	return wallet.P().Builder().Context().AVAXAssetID, nil
+}
+
+// Balances returns the spendable balance of the keychain on each chain, by asset
+func (p *TxPlan) Balances() (map[string]map[ids.ID]uint64, error) {
+	wallet, err := p.wallet()
+	if err != nil {
+		return nil, err
+	}
+	pBalance, err := wallet.P().Builder().GetBalance()
+	if err != nil {
+		return nil, fmt.Errorf("failure getting the P-Chain balance: %w", err)
+	}
+	xBalance, err := wallet.X().Builder().GetFTBalance()
+	if err != nil {
+		return nil, fmt.Errorf("failure getting the X-Chain balance: %w", err)
+	}
+	return map[string]map[ids.ID]uint64{
+		PChain: pBalance,
+		XChain: xBalance,
+	}, nil
+}
+
+// Required returns the amount of each asset the plan takes from the keychain
+// balance on each chain, adding up fees paid in [avaxAssetID] and spent funds
+func (p *TxPlan) Required(avaxAssetID ids.ID) map[string]map[ids.ID]uint64 {
+	required := map[string]map[ids.ID]uint64{}
+	for _, step := range p.Steps {
+		if required[step.Chain] == nil {
+			required[step.Chain] = map[ids.ID]uint64{}
+		}
+		required[step.Chain][avaxAssetID] += step.Fee
+		for assetID, amount := range step.Spent {
+			required[step.Chain][assetID] += amount
+		}
+	}
+	return required
+}
+
+// CheckFunds verifies that [balances] cover the [required] amounts,
+// describing each shortfall found
+func CheckFunds(required map[string]map[ids.ID]uint64, balances map[string]map[ids.ID]uint64) error {
+	errs := []error{}
+	for _, chain := range []string{PChain, XChain} {
+		for assetID, amount := range required[chain] {
+			if balance := balances[chain][assetID]; balance < amount {
+				errs = append(errs, fmt.Errorf(
+					"insufficient %s-Chain balance of asset %s: %d needed, %d available",
+					chain,
+					assetID,
+					amount,
+					balance,
+				))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnet
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxPlanFunds(t *testing.T) {
+	require := require.New(t)
+	avaxAssetID := ids.GenerateTestID()
+	subnetAssetID := ids.GenerateTestID()
+	plan := &TxPlan{
+		Steps: []TxPlanStep{
+			{Name: "CreateAssetTx", Chain: XChain, Fee: 10, Built: true},
+			// exports the asset created by the previous step
+			{Name: "ExportTx", Chain: XChain, Fee: 1},
+			{Name: "ImportTx", Chain: PChain, Fee: 1},
+			{Name: "AddPermissionlessValidatorTx", Chain: PChain, Fee: 100, Spent: map[ids.ID]uint64{subnetAssetID: 5000}, Built: true},
+		},
+	}
+	required := plan.Required(avaxAssetID)
+	require.Equal(map[string]map[ids.ID]uint64{
+		XChain: {avaxAssetID: 11},
+		PChain: {avaxAssetID: 101, subnetAssetID: 5000},
+	}, required)
+
+	balances := map[string]map[ids.ID]uint64{
+		XChain: {avaxAssetID: 11},
+		PChain: {avaxAssetID: 200, subnetAssetID: 5000},
+	}
+	require.NoError(CheckFunds(required, balances))
+
+	balances[XChain][avaxAssetID] = 10
+	delete(balances[PChain], subnetAssetID)
+	err := CheckFunds(required, balances)
+	require.ErrorContains(err, "insufficient X-Chain balance of asset "+avaxAssetID.String()+": 11 needed, 10 available")
+	require.ErrorContains(err, "insufficient P-Chain balance of asset "+subnetAssetID.String()+": 5000 needed, 0 available")
+}
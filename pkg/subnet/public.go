@@ -411,7 +411,9 @@ func (d *PublicDeployer) DeploySubnet(
 	}
 	subnetID, err := d.createSubnetTx(controlKeys, threshold, wallet)
 	if err != nil {
-		return ids.Empty, err
+		// the subnet ID is also returned if the tx was issued, as it may
+		// still be accepted
+		return subnetID, err
 	}
 	ux.Logger.PrintToUser("Subnet has been created with ID: %s", subnetID.String())
 	time.Sleep(2 * time.Second)
@@ -466,7 +468,8 @@ func (d *PublicDeployer) DeployBlockchain(
 	if isFullySigned {
 		id, err = d.Commit(tx, false)
 		if err != nil {
-			return false, ids.Empty, nil, nil, err
+			// the tx is also returned, as it may still be accepted
+			return isFullySigned, ids.Empty, tx, nil, err
 		}
 	}
 
@@ -893,7 +896,8 @@ func (d *PublicDeployer) createSubnetTx(controlKeys []string, threshold uint32,
 		} else {
 			err = fmt.Errorf("error issuing tx with ID %s: %w", tx.ID(), err)
 		}
-		return ids.Empty, err
+		// the tx may still be accepted, so its ID is returned
+		return tx.ID(), err
 	}
 
 	return tx.ID(), nil
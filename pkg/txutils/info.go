@@ -8,9 +8,11 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
 	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 )
@@ -87,6 +89,38 @@ func IsTransferSubnetOwnershipTx(tx *txs.Tx) bool {
 	return ok
 }
 
+// GetAddSubnetValidatorTx reads the AddSubnetValidatorTx [txID] from the P-Chain
+func GetAddSubnetValidatorTx(network models.Network, txID ids.ID) (*txs.AddSubnetValidatorTx, error) {
+	pClient := platformvm.NewClient(network.Endpoint)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	txBytes, err := pClient.GetTx(ctx, txID)
+	if err != nil {
+		return nil, fmt.Errorf("tx %s query error: %w", txID, err)
+	}
+	var tx txs.Tx
+	if _, err := txs.Codec.Unmarshal(txBytes, &tx); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal tx %s: %w", txID, err)
+	}
+	addSubnetValidatorTx, ok := tx.Unsigned.(*txs.AddSubnetValidatorTx)
+	if !ok {
+		return nil, fmt.Errorf("got unexpected type %T for add validator tx %s", tx.Unsigned, txID)
+	}
+	return addSubnetValidatorTx, nil
+}
+
+// GetTxStatus returns the P-Chain status of tx [txID]
+func GetTxStatus(network models.Network, txID ids.ID) (status.Status, error) {
+	pClient := platformvm.NewClient(network.Endpoint)
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	resp, err := pClient.GetTxStatus(ctx, txID)
+	if err != nil {
+		return status.Unknown, fmt.Errorf("tx %s status query error: %w", txID, err)
+	}
+	return resp.Status, nil
+}
+
 func GetOwners(network models.Network, subnetID ids.ID, transferSubnetOwnershipTxID ids.ID) ([]string, uint32, error) {
 	pClient := platformvm.NewClient(network.Endpoint)
 	ctx := context.Background()
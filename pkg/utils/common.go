@@ -360,3 +360,39 @@ func AddSingleQuotes(s []string) []string {
 		return item
 	})
 }
+
+// ParseDuration is [time.ParseDuration] also accepting days (d) and weeks
+// (w) as units, eg. 14d or 1w12h
+func ParseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	total := time.Duration(0)
+	rest := s
+	for _, unit := range []struct {
+		suffix   string
+		duration time.Duration
+	}{
+		{"w", 7 * 24 * time.Hour},
+		{"d", 24 * time.Hour},
+	} {
+		i := strings.Index(rest, unit.suffix)
+		if i == -1 {
+			continue
+		}
+		n, err := strconv.ParseUint(rest[:i], 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += time.Duration(n) * unit.duration
+		rest = rest[i+1:]
+	}
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += d
+	}
+	return total, nil
+}
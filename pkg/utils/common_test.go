@@ -5,6 +5,7 @@ package utils
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 // TestSpitStringWithQuotes test case
@@ -171,3 +172,27 @@ func TestAddSingleQuotes(t *testing.T) {
 		t.Errorf("AddSingleQuotes(%v) = %v, expected %v", input, output, expected)
 	}
 }
+
+func TestParseDuration(t *testing.T) {
+	for input, expected := range map[string]time.Duration{
+		"5m":      5 * time.Minute,
+		"14d":     14 * 24 * time.Hour,
+		"1w":      7 * 24 * time.Hour,
+		"1w2d":    9 * 24 * time.Hour,
+		"1d12h":   36 * time.Hour,
+		"2w1h30m": 14*24*time.Hour + 90*time.Minute,
+	} {
+		d, err := ParseDuration(input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) failed: %s", input, err)
+		}
+		if d != expected {
+			t.Errorf("ParseDuration(%q) = %s, expected %s", input, d, expected)
+		}
+	}
+	for _, input := range []string{"", "d", "1x", "1d1w", "-1d"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) should fail", input)
+		}
+	}
+}
@@ -66,3 +66,23 @@ func FileCopy(src string, dst string) error {
 	}
 	return os.WriteFile(dst, data, constants.WriteReadReadPerms)
 }
+
+// FindProjectDir walks up from startDir looking for a project dir
+// (see constants.ProjectDirName). It returns the path of the first one found.
+func FindProjectDir(startDir string) (string, bool) {
+	dir, err := filepath.Abs(startDir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		projectDir := filepath.Join(dir, constants.ProjectDirName)
+		if DirectoryExists(projectDir) {
+			return projectDir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
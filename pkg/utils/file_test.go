@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 )
 
 func TestExpandHome(t *testing.T) {
@@ -36,3 +38,22 @@ func TestExpandHome(t *testing.T) {
 		t.Errorf("ExpandHome failed for path starting with ~: expected %s, got %s", expectedTildePath, expandedTildePath)
 	}
 }
+
+func TestFindProjectDir(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := FindProjectDir(nested); found {
+		t.Errorf("FindProjectDir found a project dir where there is none")
+	}
+	projectDir := filepath.Join(root, "a", constants.ProjectDirName)
+	if err := os.Mkdir(projectDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	found, ok := FindProjectDir(nested)
+	if !ok || found != projectDir {
+		t.Errorf("FindProjectDir failed: expected %s, got %s", projectDir, found)
+	}
+}
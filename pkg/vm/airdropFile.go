@@ -0,0 +1,334 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	airdropAddressColumn = "address"
+	airdropAmountColumn  = "amount"
+	airdropUnitColumn    = "unit"
+	airdropCodeColumn    = "code"
+	airdropStorageColumn = "storage"
+)
+
+// units accepted in the unit column of an airdrop file. An empty unit means
+// whole tokens, the same unit used by the interactive airdrop prompt
+var airdropUnits = map[string]*big.Int{
+	"":      oneAvax,
+	"token": oneAvax,
+	"avax":  oneAvax,
+	"ether": oneAvax,
+	"gwei":  big.NewInt(1_000_000_000),
+	"navax": big.NewInt(1_000_000_000),
+	"wei":   big.NewInt(1),
+}
+
+// AirdropEntry is a single row of an airdrop file
+type AirdropEntry struct {
+	Address string            `json:"address"`
+	Amount  json.Number       `json:"amount"`
+	Unit    string            `json:"unit,omitempty"`
+	Code    string            `json:"code,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+// LoadAirdropFile reads a CSV or JSON airdrop file and returns the resulting
+// genesis allocation. Entries for the same address are summed, and the
+// addresses that appeared more than once are returned so they can be reported.
+//
+// CSV files need a header row naming the columns: address and amount are
+// required, unit, code and storage are optional. The storage column holds a
+// JSON object mapping slots to values. JSON files hold an array of entries.
+func LoadAirdropFile(path string) (core.GenesisAlloc, []common.Address, error) {
+	var (
+		entries []AirdropEntry
+		err     error
+	)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		entries, err = readAirdropCSV(path)
+	case ".json":
+		entries, err = readAirdropJSON(path)
+	default:
+		return nil, nil, fmt.Errorf("unsupported airdrop file extension %q, expected .csv or .json", ext)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read airdrop file %s: %w", path, err)
+	}
+	if len(entries) == 0 {
+		return nil, nil, fmt.Errorf("airdrop file %s has no entries", path)
+	}
+	alloc, duplicates, err := buildAirdropAllocation(entries)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid airdrop file %s: %w", path, err)
+	}
+	return alloc, duplicates, nil
+}
+
+// AllocationTotalSupply returns the sum of all the balances in [alloc]
+func AllocationTotalSupply(alloc core.GenesisAlloc) *big.Int {
+	total := big.NewInt(0)
+	for _, account := range alloc {
+		if account.Balance != nil {
+			total.Add(total, account.Balance)
+		}
+	}
+	return total
+}
+
+// FormatTokenAmount renders an amount in wei as whole tokens, without
+// trailing zeros
+func FormatTokenAmount(amount *big.Int) string {
+	s := new(big.Rat).SetFrac(amount, oneAvax).FloatString(18)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func printAllocationSummary(alloc core.GenesisAlloc, tokenName string) {
+	if tokenName == "" {
+		tokenName = "tokens"
+	}
+	total := AllocationTotalSupply(alloc)
+	ux.Logger.PrintToUser("Genesis allocates %s %s (%s wei) to %d accounts", FormatTokenAmount(total), tokenName, total, len(alloc))
+}
+
+func getEVMAllocationFromFile(airdropFile string) (core.GenesisAlloc, error) {
+	alloc, duplicates, err := LoadAirdropFile(airdropFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range duplicates {
+		ux.Logger.PrintToUser("Address %s appears more than once in the airdrop file, amounts have been summed", address.Hex())
+	}
+	return alloc, nil
+}
+
+func readAirdropJSON(path string) ([]AirdropEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []AirdropEntry
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func readAirdropCSV(path string) ([]AirdropEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case airdropAddressColumn, airdropAmountColumn, airdropUnitColumn, airdropCodeColumn, airdropStorageColumn:
+		default:
+			return nil, fmt.Errorf("unknown column %q in header", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("column %q appears more than once in header", name)
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{airdropAddressColumn, airdropAmountColumn} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %q column in header", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	entries := []AirdropEntry{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		entry := AirdropEntry{
+			Address: field(record, airdropAddressColumn),
+			Amount:  json.Number(field(record, airdropAmountColumn)),
+			Unit:    field(record, airdropUnitColumn),
+			Code:    field(record, airdropCodeColumn),
+		}
+		if storage := field(record, airdropStorageColumn); storage != "" {
+			if err := json.Unmarshal([]byte(storage), &entry.Storage); err != nil {
+				return nil, fmt.Errorf("line %d: storage must be a JSON object of slot to value: %w", line, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func buildAirdropAllocation(entries []AirdropEntry) (core.GenesisAlloc, []common.Address, error) {
+	alloc := core.GenesisAlloc{}
+	seen := map[common.Address]int{}
+	duplicates := []common.Address{}
+	for i, entry := range entries {
+		account, err := entry.toGenesisAccount()
+		if err != nil {
+			return nil, nil, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		address := common.HexToAddress(entry.Address)
+		seen[address]++
+		if seen[address] == 1 {
+			alloc[address] = account
+			continue
+		}
+		if seen[address] == 2 {
+			duplicates = append(duplicates, address)
+		}
+		merged, err := mergeGenesisAccounts(alloc[address], account)
+		if err != nil {
+			return nil, nil, fmt.Errorf("entry %d: address %s: %w", i+1, address.Hex(), err)
+		}
+		alloc[address] = merged
+	}
+	return alloc, duplicates, nil
+}
+
+func (e AirdropEntry) toGenesisAccount() (core.GenesisAccount, error) {
+	if !common.IsHexAddress(e.Address) {
+		return core.GenesisAccount{}, fmt.Errorf("invalid address %q", e.Address)
+	}
+	balance, err := parseAirdropAmount(e.Amount.String(), e.Unit)
+	if err != nil {
+		return core.GenesisAccount{}, err
+	}
+	account := core.GenesisAccount{Balance: balance}
+	if e.Code != "" {
+		code, err := hexutil.Decode(e.Code)
+		if err != nil {
+			return core.GenesisAccount{}, fmt.Errorf("invalid code: %w", err)
+		}
+		account.Code = code
+	}
+	if len(e.Storage) > 0 {
+		account.Storage = map[common.Hash]common.Hash{}
+		for slot, value := range e.Storage {
+			slotHash, err := parseStorageHash(slot)
+			if err != nil {
+				return core.GenesisAccount{}, fmt.Errorf("invalid storage slot %q: %w", slot, err)
+			}
+			valueHash, err := parseStorageHash(value)
+			if err != nil {
+				return core.GenesisAccount{}, fmt.Errorf("invalid storage value %q: %w", value, err)
+			}
+			account.Storage[slotHash] = valueHash
+		}
+	}
+	return account, nil
+}
+
+// parseAirdropAmount converts a decimal [amount] expressed in [unit] to wei
+func parseAirdropAmount(amount string, unit string) (*big.Int, error) {
+	multiplier, ok := airdropUnits[strings.ToLower(unit)]
+	if !ok {
+		return nil, fmt.Errorf("unknown unit %q, expected one of token, avax, ether, gwei, navax or wei", unit)
+	}
+	if amount == "" {
+		return nil, errors.New("missing amount")
+	}
+	value, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	if value.Sign() < 0 {
+		return nil, fmt.Errorf("amount %q must not be negative", amount)
+	}
+	value.Mul(value, new(big.Rat).SetInt(multiplier))
+	if !value.IsInt() {
+		return nil, fmt.Errorf("amount %q %s is not a whole number of wei", amount, unit)
+	}
+	return new(big.Int).Set(value.Num()), nil
+}
+
+// parseStorageHash accepts 0x prefixed hex of up to 32 bytes, allowing short
+// forms such as 0x0 for slot numbers
+func parseStorageHash(s string) (common.Hash, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return common.Hash{}, errors.New("missing 0x prefix")
+	}
+	digits := s[2:]
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	b, err := hexutil.Decode("0x" + digits)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(b) > common.HashLength {
+		return common.Hash{}, fmt.Errorf("longer than %d bytes", common.HashLength)
+	}
+	return common.BytesToHash(b), nil
+}
+
+func mergeGenesisAccounts(a, b core.GenesisAccount) (core.GenesisAccount, error) {
+	merged := core.GenesisAccount{
+		Balance: new(big.Int).Add(a.Balance, b.Balance),
+		Code:    a.Code,
+		Storage: map[common.Hash]common.Hash{},
+	}
+	if len(b.Code) > 0 {
+		if len(a.Code) > 0 && hexutil.Encode(a.Code) != hexutil.Encode(b.Code) {
+			return core.GenesisAccount{}, errors.New("conflicting code")
+		}
+		merged.Code = b.Code
+	}
+	for slot, value := range a.Storage {
+		merged.Storage[slot] = value
+	}
+	for slot, value := range b.Storage {
+		if prev, ok := merged.Storage[slot]; ok && prev != value {
+			return core.GenesisAccount{}, fmt.Errorf("conflicting value for storage slot %s", slot.Hex())
+		}
+		merged.Storage[slot] = value
+	}
+	if len(merged.Storage) == 0 {
+		merged.Storage = nil
+	}
+	return merged, nil
+}
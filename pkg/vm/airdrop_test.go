@@ -5,6 +5,8 @@ package vm
 
 import (
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ava-labs/avalanche-cli/internal/mocks"
@@ -69,3 +71,75 @@ func TestMultipleAirdropsSameAddress(t *testing.T) {
 
 	require.Equal(alloc[testAirdropAddress].Balance, expectedAmount)
 }
+
+func writeAirdropFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAirdropFileCSV(t *testing.T) {
+	require := setupTest(t)
+	contractAddress := common.HexToAddress("0x0300000000000000000000000000000000000000")
+	path := writeAirdropFile(t, "airdrop.csv", `# game testnet players
+address,amount,unit,code,storage
+0x098B69E43b1720Bd12378225519d74e5F3aD0eA5,1.5,,,
+0x098b69e43b1720bd12378225519d74e5f3ad0ea5,500000000,gwei,,
+0x0300000000000000000000000000000000000000,0,wei,0x6080,"{""0x0"": ""0x01""}"
+`)
+	alloc, duplicates, err := LoadAirdropFile(path)
+	require.NoError(err)
+	require.Len(alloc, 2)
+	require.Equal([]common.Address{testAirdropAddress}, duplicates)
+	expected, _ := new(big.Int).SetString("2000000000000000000", 10)
+	require.Equal(expected, alloc[testAirdropAddress].Balance)
+	require.Equal([]byte{0x60, 0x80}, alloc[contractAddress].Code)
+	require.Equal(common.BigToHash(big.NewInt(1)), alloc[contractAddress].Storage[common.Hash{}])
+	require.Equal(expected, AllocationTotalSupply(alloc))
+	require.Equal("2", FormatTokenAmount(AllocationTotalSupply(alloc)))
+}
+
+func TestLoadAirdropFileJSON(t *testing.T) {
+	require := setupTest(t)
+	path := writeAirdropFile(t, "airdrop.json", `[
+	{"address": "0x098B69E43b1720Bd12378225519d74e5F3aD0eA5", "amount": 10},
+	{"address": "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC", "amount": "0.25", "unit": "token"}
+]`)
+	alloc, duplicates, err := LoadAirdropFile(path)
+	require.NoError(err)
+	require.Empty(duplicates)
+	require.Equal("10.25", FormatTokenAmount(AllocationTotalSupply(alloc)))
+}
+
+func TestLoadAirdropFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		err     string
+	}{
+		{"unknown extension", "airdrop.txt", "", "unsupported airdrop file extension"},
+		{"missing column", "airdrop.csv", "address\n0x098B69E43b1720Bd12378225519d74e5F3aD0eA5\n", `missing "amount" column`},
+		{"unknown column", "airdrop.csv", "address,amount,balance\n", `unknown column "balance"`},
+		{"empty", "airdrop.csv", "address,amount\n", "has no entries"},
+		{"invalid address", "airdrop.csv", "address,amount\n0x1234,1\n", "invalid address"},
+		{"unknown unit", "airdrop.csv", "address,amount,unit\n0x098B69E43b1720Bd12378225519d74e5F3aD0eA5,1,eth\n", "unknown unit"},
+		{"negative amount", "airdrop.csv", "address,amount\n0x098B69E43b1720Bd12378225519d74e5F3aD0eA5,-1\n", "must not be negative"},
+		{"fractional wei", "airdrop.csv", "address,amount,unit\n0x098B69E43b1720Bd12378225519d74e5F3aD0eA5,1.5,wei\n", "not a whole number of wei"},
+		{
+			"conflicting code", "airdrop.json",
+			`[{"address": "0x098B69E43b1720Bd12378225519d74e5F3aD0eA5", "amount": 1, "code": "0x01"},
+			{"address": "0x098B69E43b1720Bd12378225519d74e5F3aD0eA5", "amount": 1, "code": "0x02"}]`,
+			"conflicting code",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := setupTest(t)
+			_, _, err := LoadAirdropFile(writeAirdropFile(t, tt.file, tt.content))
+			require.ErrorContains(err, tt.err)
+		})
+	}
+}
@@ -145,6 +145,12 @@ func GetRPCProtocolVersion(app *application.Avalanche, vmType models.VMType, vmV
 		return 0, errors.New("unknown VM type")
 	}
 
+	return getRPCProtocolVersionFromURL(app, url, vmVersion)
+}
+
+// getRPCProtocolVersionFromURL looks up [vmVersion] in the compatibility file
+// at [url]
+func getRPCProtocolVersionFromURL(app *application.Avalanche, url string, vmVersion string) (int, error) {
 	compatibilityBytes, err := app.Downloader.Download(url)
 	if err != nil {
 		return 0, err
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
@@ -17,6 +19,8 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 )
 
+var ErrCustomVMChecksumMismatch = errors.New("custom VM binary checksum mismatch")
+
 func CreateCustomSubnetConfig(
 	app *application.Avalanche,
 	subnetName string,
@@ -25,6 +29,7 @@ func CreateCustomSubnetConfig(
 	customVMRepoURL string,
 	customVMBranch string,
 	customVMBuildScript string,
+	customVMBuildImage string,
 	vmPath string,
 ) ([]byte, *models.Sidecar, error) {
 	ux.Logger.PrintToUser("creating custom VM subnet %s", subnetName)
@@ -42,7 +47,7 @@ func CreateCustomSubnetConfig(
 		TokenName: "",
 	}
 
-	if customVMRepoURL != "" || customVMBranch != "" || customVMBuildScript != "" {
+	if customVMRepoURL != "" || customVMBranch != "" || customVMBuildScript != "" || customVMBuildImage != "" {
 		useRepo = true
 	}
 	if vmPath == "" && !useRepo {
@@ -66,6 +71,9 @@ func CreateCustomSubnetConfig(
 		if err := SetCustomVMSourceCodeFields(app, sc, customVMRepoURL, customVMBranch, customVMBuildScript); err != nil {
 			return nil, &models.Sidecar{}, err
 		}
+		if customVMBuildImage != "" {
+			sc.CustomVMBuild = &models.CustomVMBuild{BuildImage: customVMBuildImage}
+		}
 		if err := BuildCustomVM(app, sc); err != nil {
 			return nil, &models.Sidecar{}, err
 		}
@@ -157,6 +165,21 @@ func checkGitIsInstalled() error {
 	return nil
 }
 
+func checkDockerIsInstalled() error {
+	if err := exec.Command("docker", "version").Run(); err != nil {
+		ux.Logger.PrintToUser("Docker is not available. It is a necessary dependency for CLI to build a custom VM in a container.")
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("Please follow install instructions at https://docs.docker.com/get-docker/ and try again")
+		ux.Logger.PrintToUser("")
+		return fmt.Errorf("docker is not available: %w", err)
+	}
+	return nil
+}
+
+// BuildCustomVM builds the VM binary of [sc] from its source code repository,
+// and records the build in sc.CustomVMBuild. If a previous build is recorded,
+// its commit is built instead of the branch head, and the new binary must have
+// the recorded checksum
 func BuildCustomVM(
 	app *application.Avalanche,
 	sc *models.Sidecar,
@@ -164,6 +187,15 @@ func BuildCustomVM(
 	if err := checkGitIsInstalled(); err != nil {
 		return err
 	}
+	pinned := sc.CustomVMBuild
+	if pinned == nil {
+		pinned = &models.CustomVMBuild{}
+	}
+	if pinned.BuildImage != "" {
+		if err := checkDockerIsInstalled(); err != nil {
+			return err
+		}
+	}
 
 	// create repo dir
 	reposDir := app.GetReposDir()
@@ -173,7 +205,11 @@ func BuildCustomVM(
 		return err
 	}
 
-	// get branch from repo
+	// get branch, or pinned commit, from repo
+	ref := sc.CustomVMBranch
+	if pinned.Commit != "" {
+		ref = pinned.Commit
+	}
 	cmd := exec.Command("git", "init", "-q")
 	cmd.Dir = repoDir
 	utils.SetupRealtimeCLIOutput(cmd, true, true)
@@ -186,25 +222,48 @@ func BuildCustomVM(
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("could not add origin %s on git: %w", sc.CustomVMRepoURL, err)
 	}
-	cmd = exec.Command("git", "fetch", "--depth", "1", "origin", sc.CustomVMBranch, "-q")
+	cmd = exec.Command("git", "fetch", "--depth", "1", "origin", ref, "-q")
 	cmd.Dir = repoDir
 	utils.SetupRealtimeCLIOutput(cmd, true, true)
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("could not fetch git branch/commit %s of repository %s: %w", sc.CustomVMBranch, sc.CustomVMRepoURL, err)
+		return fmt.Errorf("could not fetch git branch/commit %s of repository %s: %w", ref, sc.CustomVMRepoURL, err)
 	}
-	cmd = exec.Command("git", "checkout", sc.CustomVMBranch)
+	cmd = exec.Command("git", "checkout", ref)
 	cmd.Dir = repoDir
 	utils.SetupRealtimeCLIOutput(cmd, true, true)
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("could not checkout git branch %s of repository %s: %w", sc.CustomVMBranch, sc.CustomVMRepoURL, err)
+		return fmt.Errorf("could not checkout git branch %s of repository %s: %w", ref, sc.CustomVMRepoURL, err)
+	}
+	cmd = exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("could not resolve the commit of git branch %s of repository %s: %w", ref, sc.CustomVMRepoURL, err)
+	}
+	commit := strings.TrimSpace(string(out))
+	if pinned.Commit != "" && !strings.HasPrefix(commit, pinned.Commit) {
+		return fmt.Errorf("checked out commit %s instead of the pinned commit %s", commit, pinned.Commit)
 	}
 
 	vmPath := app.GetCustomVMPath(sc.Name)
 	_ = os.RemoveAll(vmPath)
 
 	// build
-	cmd = exec.Command(sc.CustomVMBuildScript, vmPath)
-	cmd.Dir = repoDir
+	if pinned.BuildImage != "" {
+		cmd = exec.Command(
+			"docker", "run", "--rm",
+			"--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()),
+			"-e", "HOME=/tmp",
+			"-v", repoDir+":/src",
+			"-v", filepath.Dir(vmPath)+":/out",
+			"-w", "/src",
+			pinned.BuildImage,
+			sc.CustomVMBuildScript, path.Join("/out", filepath.Base(vmPath)),
+		)
+	} else {
+		cmd = exec.Command(sc.CustomVMBuildScript, vmPath)
+		cmd.Dir = repoDir
+	}
 	utils.SetupRealtimeCLIOutput(cmd, true, true)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("error building custom vm binary using script %s on repo %s: %w", sc.CustomVMBuildScript, sc.CustomVMRepoURL, err)
@@ -215,5 +274,61 @@ func BuildCustomVM(
 	if !utils.IsExecutable(vmPath) {
 		return fmt.Errorf("custom VM binary %s not executable. Expected build script to create an executable file", vmPath)
 	}
+
+	binarySHA256, err := utils.GetSHA256FromDisk(vmPath)
+	if err != nil {
+		return err
+	}
+	if pinned.BinarySHA256 != "" && binarySHA256 != pinned.BinarySHA256 {
+		hint := "set a build image to build it in a container"
+		if pinned.BuildImage != "" {
+			hint = fmt.Sprintf("check that build image %s is pinned by digest", pinned.BuildImage)
+		}
+		return fmt.Errorf(
+			"%w: commit %s built into a binary with sha256 %s, expected %s. The build is not reproducible on this host, %s",
+			ErrCustomVMChecksumMismatch, commit, binarySHA256, pinned.BinarySHA256, hint,
+		)
+	}
+	vmID, err := sc.GetVMID()
+	if err != nil {
+		return err
+	}
+	if pinned.VMID != "" && vmID != pinned.VMID {
+		return fmt.Errorf("VMID mismatch for %s: the binary was built for %s but would be installed as %s", sc.Name, pinned.VMID, vmID)
+	}
+	sc.CustomVMBuild = &models.CustomVMBuild{
+		Commit:       commit,
+		BinarySHA256: binarySHA256,
+		VMID:         vmID,
+		BuildImage:   pinned.BuildImage,
+	}
+	ux.Logger.PrintToUser("Built custom VM from commit %s (sha256 %s)", commit, binarySHA256)
+	return nil
+}
+
+// VerifyCustomVMBinary checks that the VM binary at [vmPath] is the one
+// recorded on the last source build of [sc], and that it is still installed
+// under the same VMID. Sidecars without a build record are not checked
+func VerifyCustomVMBinary(sc *models.Sidecar, vmPath string) error {
+	if sc.CustomVMBuild == nil || sc.CustomVMBuild.BinarySHA256 == "" {
+		return nil
+	}
+	vmID, err := sc.GetVMID()
+	if err != nil {
+		return err
+	}
+	if sc.CustomVMBuild.VMID != "" && vmID != sc.CustomVMBuild.VMID {
+		return fmt.Errorf("VMID mismatch for %s: the binary was built for %s but would be installed as %s", sc.Name, sc.CustomVMBuild.VMID, vmID)
+	}
+	binarySHA256, err := utils.GetSHA256FromDisk(vmPath)
+	if err != nil {
+		return err
+	}
+	if binarySHA256 != sc.CustomVMBuild.BinarySHA256 {
+		return fmt.Errorf(
+			"%w: %s has sha256 %s but commit %s built into %s. Rebuild it or update the subnet VM",
+			ErrCustomVMChecksumMismatch, vmPath, binarySHA256, sc.CustomVMBuild.Commit, sc.CustomVMBuild.BinarySHA256,
+		)
+	}
 	return nil
 }
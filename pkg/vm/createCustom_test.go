@@ -0,0 +1,100 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+const testBuildScript = `#!/usr/bin/env bash
+printf '#!/bin/sh\necho custom vm\n' > "$1"
+chmod +x "$1"
+`
+
+func newTestVMRepo(t *testing.T) string {
+	repoDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "build.sh"), []byte(testBuildScript), constants.DefaultPerms755))
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "build.sh"},
+		{"-c", "user.name=test", "-c", "user.email=test@test", "commit", "-q", "-m", "build script"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return repoDir
+}
+
+func TestBuildCustomVMPinsBuild(t *testing.T) {
+	require := setupTest(t)
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	app := application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, nil, nil)
+	require.NoError(os.MkdirAll(app.GetCustomVMDir(), constants.DefaultPerms755))
+
+	sc := &models.Sidecar{
+		Name:                "testvm",
+		VM:                  models.CustomVM,
+		CustomVMRepoURL:     newTestVMRepo(t),
+		CustomVMBranch:      "main",
+		CustomVMBuildScript: "./build.sh",
+	}
+	require.NoError(BuildCustomVM(app, sc))
+	require.NotNil(sc.CustomVMBuild)
+	require.Len(sc.CustomVMBuild.Commit, 40)
+	require.Len(sc.CustomVMBuild.BinarySHA256, 64)
+	vmID, err := sc.GetVMID()
+	require.NoError(err)
+	require.Equal(vmID, sc.CustomVMBuild.VMID)
+	vmPath := app.GetCustomVMPath(sc.Name)
+	require.NoError(VerifyCustomVMBinary(sc, vmPath))
+
+	// rebuilding the pinned commit gives the same binary
+	build := *sc.CustomVMBuild
+	require.NoError(BuildCustomVM(app, sc))
+	require.Equal(build, *sc.CustomVMBuild)
+
+	sc.CustomVMBuild.BinarySHA256 = "00"
+	require.ErrorIs(BuildCustomVM(app, sc), ErrCustomVMChecksumMismatch)
+}
+
+func TestVerifyCustomVMBinary(t *testing.T) {
+	require := setupTest(t)
+	vmPath := filepath.Join(t.TempDir(), "vm")
+	require.NoError(os.WriteFile(vmPath, []byte("custom vm"), constants.DefaultPerms755))
+	sc := &models.Sidecar{Name: "testvm"}
+	// nothing to check without a build record
+	require.NoError(VerifyCustomVMBinary(sc, vmPath))
+
+	vmID, err := sc.GetVMID()
+	require.NoError(err)
+	binarySHA256, err := utils.GetSHA256FromDisk(vmPath)
+	require.NoError(err)
+	sc.CustomVMBuild = &models.CustomVMBuild{
+		Commit:       "abc",
+		BinarySHA256: binarySHA256,
+		VMID:         vmID,
+	}
+	require.NoError(VerifyCustomVMBinary(sc, vmPath))
+
+	require.NoError(os.WriteFile(vmPath, []byte("other vm"), constants.DefaultPerms755))
+	require.ErrorIs(VerifyCustomVMBinary(sc, vmPath), ErrCustomVMChecksumMismatch)
+
+	sc.Name = "renamed"
+	require.ErrorContains(VerifyCustomVMBinary(sc, vmPath), "VMID mismatch")
+}
@@ -38,6 +38,10 @@ func CreateEvmSubnetConfig(
 	subnetEVMTokenName string,
 	useSubnetEVMDefaults bool,
 	teleporterReady bool,
+	airdropFile string,
+	genesisContracts []GenesisContract,
+	evmTemplate *EvmGenesisTemplate,
+	precompilesFile string,
 ) ([]byte, *models.Sidecar, error) {
 	var (
 		genesisBytes []byte
@@ -67,6 +71,14 @@ func CreateEvmSubnetConfig(
 		}
 	}
 
+	var precompiles params.Precompiles
+	if precompilesFile != "" {
+		precompiles, err = LoadPrecompilesFile(precompilesFile, subnetEVMVersion)
+		if err != nil {
+			return nil, &models.Sidecar{}, err
+		}
+	}
+
 	if genesisPath == "" {
 		genesisBytes, sc, err = createEvmGenesis(
 			app,
@@ -77,6 +89,10 @@ func CreateEvmSubnetConfig(
 			subnetEVMTokenName,
 			useSubnetEVMDefaults,
 			teleporterReady,
+			airdropFile,
+			genesisContracts,
+			evmTemplate,
+			precompiles,
 		)
 		if err != nil {
 			return nil, &models.Sidecar{}, err
@@ -110,11 +126,18 @@ func createEvmGenesis(
 	subnetEVMTokenName string,
 	useSubnetEVMDefaults bool,
 	teleporterReady bool,
+	airdropFile string,
+	genesisContracts []GenesisContract,
+	evmTemplate *EvmGenesisTemplate,
+	precompiles params.Precompiles,
 ) ([]byte, *models.Sidecar, error) {
 	ux.Logger.PrintToUser("creating genesis for subnet %s", subnetName)
 
 	genesis := core.Genesis{}
-	conf := params.SubnetEVMDefaultChainConfig
+	// work on a copy, so that the defaults are not modified across calls
+	defaultConf := *params.SubnetEVMDefaultChainConfig
+	conf := &defaultConf
+	conf.GenesisPrecompiles = params.Precompiles{}
 
 	// set non nil durango start block height 0
 	// TODO: check if needed to set on subnet deploy to a specific network
@@ -141,29 +164,60 @@ func createEvmGenesis(
 		err        error
 	)
 
-	subnetEvmState, err := statemachine.NewStateMachine(
-		[]string{descriptorsState, feeState, airdropState, precompilesState},
-	)
-	if err != nil {
-		return nil, nil, err
-	}
-	for subnetEvmState.Running() {
-		switch subnetEvmState.CurrentState() {
-		case descriptorsState:
-			chainID, tokenName, direction, err = getDescriptors(app, subnetEVMChainID, subnetEVMTokenName)
-		case feeState:
-			*conf, direction, err = GetFeeConfig(*conf, app, useSubnetEVMDefaults)
-		case airdropState:
-			allocation, direction, err = getEVMAllocation(app, useSubnetEVMDefaults)
-		case precompilesState:
-			*conf, direction, err = getPrecompiles(*conf, app, useSubnetEVMDefaults, teleporterReady)
-		default:
-			err = errors.New("invalid creation stage")
+	if evmTemplate != nil {
+		// templates provide every setting, so nothing is prompted
+		if err := ValidatePrecompilesForVersion(evmTemplate.Precompiles, subnetEVMVersion); err != nil {
+			return nil, nil, fmt.Errorf("template %s: %w", evmTemplate.Ref, err)
 		}
+		chainID, tokenName, allocation, err = applyGenesisTemplate(conf, evmTemplate, subnetEVMChainID, subnetEVMTokenName, teleporterReady, airdropFile)
 		if err != nil {
 			return nil, nil, err
 		}
-		subnetEvmState.NextState(direction)
+		setGenesisPrecompiles(conf, precompiles, teleporterReady)
+	} else {
+		states := []string{descriptorsState, feeState}
+		if airdropFile != "" {
+			// the allocation comes from the file, so the airdrop step is skipped
+			allocation, err = getEVMAllocationFromFile(airdropFile)
+			if err != nil {
+				return nil, nil, err
+			}
+		} else {
+			states = append(states, airdropState)
+		}
+		if precompiles != nil {
+			// same for the precompiles
+			setGenesisPrecompiles(conf, precompiles, teleporterReady)
+		} else {
+			states = append(states, precompilesState)
+		}
+
+		subnetEvmState, err := statemachine.NewStateMachine(states)
+		if err != nil {
+			return nil, nil, err
+		}
+		for subnetEvmState.Running() {
+			switch subnetEvmState.CurrentState() {
+			case descriptorsState:
+				chainID, tokenName, direction, err = getDescriptors(app, subnetEVMChainID, subnetEVMTokenName)
+			case feeState:
+				*conf, direction, err = GetFeeConfig(*conf, app, useSubnetEVMDefaults)
+			case airdropState:
+				allocation, direction, err = getEVMAllocation(app, useSubnetEVMDefaults)
+			case precompilesState:
+				*conf, direction, err = getPrecompiles(*conf, app, useSubnetEVMDefaults, teleporterReady)
+			default:
+				err = errors.New("invalid creation stage")
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			subnetEvmState.NextState(direction)
+		}
+	}
+
+	if err := AddGenesisContracts(allocation, genesisContracts); err != nil {
+		return nil, nil, err
 	}
 
 	if conf != nil && conf.GenesisPrecompiles[txallowlist.ConfigKey] != nil {
@@ -181,6 +235,8 @@ func createEvmGenesis(
 
 	conf.ChainID = chainID
 
+	printAllocationSummary(allocation, tokenName)
+
 	genesis.Alloc = allocation
 	genesis.Config = conf
 	genesis.Difficulty = Difficulty
@@ -209,10 +265,66 @@ func createEvmGenesis(
 		Subnet:     subnetName,
 		TokenName:  tokenName,
 	}
+	if evmTemplate != nil {
+		sc.GenesisTemplate = evmTemplate.Ref
+	}
 
 	return prettyJSON.Bytes(), sc, nil
 }
 
+// applyGenesisTemplate sets the fee config and precompiles of [evmTemplate] into
+// [conf], and returns the chain ID, token name and allocation to use. Flag
+// values take precedence over the template ones, and the allocations of an
+// airdrop file are added to the template allocation
+func applyGenesisTemplate(
+	conf *params.ChainConfig,
+	evmTemplate *EvmGenesisTemplate,
+	subnetEVMChainID uint64,
+	subnetEVMTokenName string,
+	teleporterReady bool,
+	airdropFile string,
+) (*big.Int, string, core.GenesisAlloc, error) {
+	ux.Logger.PrintToUser("using genesis template %s", evmTemplate.Ref)
+	chainID := subnetEVMChainID
+	if chainID == 0 {
+		chainID = evmTemplate.ChainID
+	}
+	if chainID == 0 {
+		return nil, "", nil, fmt.Errorf("template %s does not set a chain ID, provide one with --evm-chain-id", evmTemplate.Ref)
+	}
+	tokenName := subnetEVMTokenName
+	if tokenName == "" {
+		tokenName = evmTemplate.TokenName
+	}
+	if tokenName == "" {
+		return nil, "", nil, fmt.Errorf("template %s does not set a token name, provide one with --evm-token", evmTemplate.Ref)
+	}
+	conf.FeeConfig = evmTemplate.FeeConfig
+	conf.AllowFeeRecipients = evmTemplate.AllowFeeRecipients
+	conf.GenesisPrecompiles = params.Precompiles{}
+	setGenesisPrecompiles(conf, evmTemplate.Precompiles, teleporterReady)
+	allocation := core.GenesisAlloc{}
+	for address, account := range evmTemplate.Alloc {
+		allocation[address] = account
+	}
+	if airdropFile != "" {
+		fileAllocation, err := getEVMAllocationFromFile(airdropFile)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		for address, account := range fileAllocation {
+			if templateAccount, ok := allocation[address]; ok {
+				account, err = mergeGenesisAccounts(templateAccount, account)
+				if err != nil {
+					return nil, "", nil, fmt.Errorf("address %s: %w", address.Hex(), err)
+				}
+			}
+			allocation[address] = account
+		}
+	}
+	return new(big.Int).SetUint64(chainID), tokenName, allocation, nil
+}
+
 func ensureAdminsHaveBalance(admins []common.Address, alloc core.GenesisAlloc) error {
 	if len(admins) < 1 {
 		return nil
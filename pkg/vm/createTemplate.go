@@ -0,0 +1,135 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+// CreateTemplateSubnetConfig creates the genesis and sidecar of a subnet
+// running the VM of template [t], installing or building its binary. The
+// genesis is read from [genesisPath] if given, and created from the template
+// otherwise. Genesis params missing from [values] are asked, unless
+// [useDefaults] is set and the param has a default
+func CreateTemplateSubnetConfig(
+	app *application.Avalanche,
+	subnetName string,
+	t VMTemplate,
+	version string,
+	genesisPath string,
+	values map[string]string,
+	useDefaults bool,
+) ([]byte, *models.Sidecar, error) {
+	ux.Logger.PrintToUser("creating %s subnet %s", t.Name(), subnetName)
+
+	var (
+		genesisBytes []byte
+		err          error
+	)
+	if genesisPath != "" {
+		genesisBytes, err = os.ReadFile(genesisPath)
+	} else {
+		values, err = captureTemplateParams(app, t, values, useDefaults)
+		if err != nil {
+			return nil, nil, err
+		}
+		genesisBytes, err = t.CreateGenesis(values)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sc := &models.Sidecar{
+		Name:      subnetName,
+		Subnet:    subnetName,
+		TokenName: "",
+	}
+	if err := SetupVMTemplateSidecar(app, sc, t, version); err != nil {
+		return nil, nil, err
+	}
+	return genesisBytes, sc, nil
+}
+
+// SetupVMTemplateSidecar sets the VM fields of [sc] for [version] of the VM of
+// template [t], the default one when empty, installing or building its binary
+func SetupVMTemplateSidecar(app *application.Avalanche, sc *models.Sidecar, t VMTemplate, version string) error {
+	var err error
+	if version == "" {
+		version, err = DefaultVMTemplateVersion(app, t)
+		if err != nil {
+			return err
+		}
+	}
+	sc.VM = models.VMType(t.Name())
+	sc.VMVersion = version
+	if mt, ok := t.(*ManifestVMTemplate); ok {
+		manifest := mt.Manifest
+		sc.VMTemplate = &manifest
+		if build := manifest.Binary.Build; build != nil {
+			sc.CustomVMRepoURL = build.Repo
+			sc.CustomVMBuildScript = build.Script
+		}
+	}
+	if _, err := t.SetupBinary(app, sc, version); err != nil {
+		return err
+	}
+	if sc.CustomVMRepoURL != "" {
+		sc.CustomVMBranch = version
+	}
+	sc.RPCVersion, err = t.RPCVersion(app, sc, version)
+	if err != nil {
+		return fmt.Errorf("unable to get RPC version: %w", err)
+	}
+	return nil
+}
+
+func captureTemplateParams(
+	app *application.Avalanche,
+	t VMTemplate,
+	values map[string]string,
+	useDefaults bool,
+) (map[string]string, error) {
+	captured := map[string]string{}
+	for k, v := range values {
+		captured[k] = v
+	}
+	for _, param := range t.GenesisParams() {
+		if _, ok := captured[param.Name]; ok {
+			continue
+		}
+		if param.Default != nil && useDefaults {
+			continue
+		}
+		promptStr := param.Name
+		if param.Description != "" {
+			promptStr = param.Description
+		}
+		if param.Default != nil {
+			promptStr = fmt.Sprintf("%s (default %s)", promptStr, *param.Default)
+		}
+		p := param
+		value, err := app.Prompt.CaptureValidatedString(promptStr, func(s string) error {
+			if s == "" {
+				if p.Default != nil {
+					return nil
+				}
+				return errors.New("a value is required")
+			}
+			return validateTemplateParam(p, s)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			captured[param.Name] = value
+		}
+	}
+	return captured, nil
+}
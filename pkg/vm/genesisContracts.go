@@ -0,0 +1,270 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/evm"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/subnet-evm/constants"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"gopkg.in/yaml.v3"
+)
+
+// GenesisContractSpec describes a contract to be placed in the genesis
+// allocation, as written in a genesis contracts file. Exactly one of Bytecode
+// (runtime bytecode) or Artifact (a Foundry or Hardhat artifact JSON, relative
+// to the file) must be set
+type GenesisContractSpec struct {
+	Name     string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Address  string            `json:"address" yaml:"address"`
+	Bytecode string            `json:"bytecode,omitempty" yaml:"bytecode,omitempty"`
+	Artifact string            `json:"artifact,omitempty" yaml:"artifact,omitempty"`
+	Storage  map[string]string `json:"storage,omitempty" yaml:"storage,omitempty"`
+	Balance  string            `json:"balance,omitempty" yaml:"balance,omitempty"`
+}
+
+// GenesisContract is a contract ready to be injected into a genesis allocation
+type GenesisContract struct {
+	Name    string
+	Address common.Address
+	Code    []byte
+	Storage map[common.Hash]common.Hash
+	Balance *big.Int
+}
+
+// LoadGenesisContractsFile reads a JSON or YAML list of genesis contract specs,
+// resolving artifact paths relative to the file
+func LoadGenesisContractsFile(path string) ([]GenesisContract, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis contracts file %s: %w", path, err)
+	}
+	var specs []GenesisContractSpec
+	if err := yaml.Unmarshal(fileBytes, &specs); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis contracts file %s: %w", path, err)
+	}
+	contracts := make([]GenesisContract, 0, len(specs))
+	for i, spec := range specs {
+		contract, err := spec.Resolve(filepath.Dir(path))
+		if err != nil {
+			return nil, fmt.Errorf("genesis contracts file %s, entry %d: %w", path, i+1, err)
+		}
+		contracts = append(contracts, contract)
+	}
+	return contracts, nil
+}
+
+// ParseGenesisContractFlag parses a <address>=<artifact or runtime bytecode>
+// pair as given on the command line
+func ParseGenesisContractFlag(value string) (GenesisContract, error) {
+	address, code, ok := strings.Cut(value, "=")
+	if !ok {
+		return GenesisContract{}, fmt.Errorf("invalid genesis contract %q, expected <address>=<artifact file or bytecode>", value)
+	}
+	spec := GenesisContractSpec{Address: address}
+	if strings.HasPrefix(code, "0x") {
+		spec.Bytecode = code
+	} else {
+		spec.Artifact = code
+	}
+	return spec.Resolve("")
+}
+
+// Resolve validates the spec and loads its bytecode, reading the artifact
+// relative to [baseDir] if needed
+func (s GenesisContractSpec) Resolve(baseDir string) (GenesisContract, error) {
+	if !common.IsHexAddress(s.Address) {
+		return GenesisContract{}, fmt.Errorf("invalid address %q", s.Address)
+	}
+	contract := GenesisContract{
+		Name:    s.Name,
+		Address: common.HexToAddress(s.Address),
+		Balance: big.NewInt(0),
+	}
+	if modules.ReservedAddress(contract.Address) || contract.Address == constants.BlackholeAddr {
+		return GenesisContract{}, fmt.Errorf("address %s is reserved by subnet-evm", contract.Address.Hex())
+	}
+	var err error
+	switch {
+	case s.Bytecode != "" && s.Artifact != "":
+		return GenesisContract{}, errors.New("bytecode and artifact are mutually exclusive")
+	case s.Bytecode != "":
+		contract.Code, err = hexutil.Decode(s.Bytecode)
+		if err != nil {
+			return GenesisContract{}, fmt.Errorf("invalid bytecode: %w", err)
+		}
+	case s.Artifact != "":
+		artifactPath := s.Artifact
+		if !filepath.IsAbs(artifactPath) && baseDir != "" {
+			artifactPath = filepath.Join(baseDir, artifactPath)
+		}
+		contract.Code, err = ReadContractArtifact(artifactPath)
+		if err != nil {
+			return GenesisContract{}, err
+		}
+		if contract.Name == "" {
+			contract.Name = strings.TrimSuffix(filepath.Base(artifactPath), filepath.Ext(artifactPath))
+		}
+	default:
+		return GenesisContract{}, errors.New("either bytecode or artifact must be given")
+	}
+	if len(contract.Code) == 0 {
+		return GenesisContract{}, errors.New("runtime bytecode is empty")
+	}
+	if len(s.Storage) > 0 {
+		contract.Storage = map[common.Hash]common.Hash{}
+		for slot, value := range s.Storage {
+			slotHash, err := parseStorageHash(slot)
+			if err != nil {
+				return GenesisContract{}, fmt.Errorf("invalid storage slot %q: %w", slot, err)
+			}
+			valueHash, err := parseStorageHash(value)
+			if err != nil {
+				return GenesisContract{}, fmt.Errorf("invalid storage value %q: %w", value, err)
+			}
+			contract.Storage[slotHash] = valueHash
+		}
+	}
+	if s.Balance != "" {
+		balance, ok := new(big.Int).SetString(s.Balance, 0)
+		if !ok || balance.Sign() < 0 {
+			return GenesisContract{}, fmt.Errorf("invalid balance %q, expected a non negative amount in wei", s.Balance)
+		}
+		contract.Balance = balance
+	}
+	if contract.Name == "" {
+		contract.Name = contract.Address.Hex()
+	}
+	return contract, nil
+}
+
+// ReadContractArtifact returns the runtime bytecode of a compiled contract
+// artifact. Both the Foundry (deployedBytecode.object) and the Hardhat
+// (deployedBytecode) layouts are supported
+func ReadContractArtifact(path string) ([]byte, error) {
+	artifactBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract artifact %s: %w", path, err)
+	}
+	var artifact struct {
+		DeployedBytecode json.RawMessage `json:"deployedBytecode"`
+	}
+	if err := json.Unmarshal(artifactBytes, &artifact); err != nil {
+		return nil, fmt.Errorf("failed to parse contract artifact %s: %w", path, err)
+	}
+	if len(artifact.DeployedBytecode) == 0 {
+		return nil, fmt.Errorf("contract artifact %s has no deployedBytecode", path)
+	}
+	var bytecode string
+	if err := json.Unmarshal(artifact.DeployedBytecode, &bytecode); err != nil {
+		var foundryBytecode struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(artifact.DeployedBytecode, &foundryBytecode); err != nil {
+			return nil, fmt.Errorf("unexpected deployedBytecode format in contract artifact %s", path)
+		}
+		bytecode = foundryBytecode.Object
+	}
+	if strings.Contains(bytecode, "__") {
+		return nil, fmt.Errorf("contract artifact %s has unlinked library references", path)
+	}
+	if !strings.HasPrefix(bytecode, "0x") {
+		bytecode = "0x" + bytecode
+	}
+	code, err := hexutil.Decode(bytecode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployedBytecode in contract artifact %s: %w", path, err)
+	}
+	return code, nil
+}
+
+// AddGenesisContracts injects [contracts] into [alloc]. Balances already
+// allocated to a contract address are kept and added to the contract balance
+func AddGenesisContracts(alloc core.GenesisAlloc, contracts []GenesisContract) error {
+	for _, contract := range contracts {
+		account, ok := alloc[contract.Address]
+		if ok && len(account.Code) > 0 {
+			return fmt.Errorf("contract %s: address %s already has code in the genesis", contract.Name, contract.Address.Hex())
+		}
+		balance := new(big.Int).Set(contract.Balance)
+		if account.Balance != nil {
+			balance.Add(balance, account.Balance)
+		}
+		storage := account.Storage
+		if len(contract.Storage) > 0 {
+			storage = contract.Storage
+		}
+		alloc[contract.Address] = core.GenesisAccount{
+			Code:    contract.Code,
+			Storage: storage,
+			Balance: balance,
+			Nonce:   account.Nonce,
+		}
+		ux.Logger.PrintToUser("Pre-deploying contract %s at %s (%d bytes)", contract.Name, contract.Address.Hex(), len(contract.Code))
+	}
+	return nil
+}
+
+// GenesisContractAddresses returns the sorted addresses holding code in the
+// allocation of a Subnet-EVM genesis
+func GenesisContractAddresses(genesisBytes []byte) ([]common.Address, error) {
+	var genesis core.Genesis
+	if err := json.Unmarshal(genesisBytes, &genesis); err != nil {
+		return nil, err
+	}
+	addresses := []common.Address{}
+	for address, account := range genesis.Alloc {
+		if len(account.Code) > 0 {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Hex() < addresses[j].Hex()
+	})
+	return addresses, nil
+}
+
+// VerifyGenesisContracts checks that every contract of the genesis allocation
+// is deployed on the chain served at [rpcURL]
+func VerifyGenesisContracts(rpcURL string, genesisBytes []byte) error {
+	addresses, err := GenesisContractAddresses(genesisBytes)
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		return nil
+	}
+	client, err := evm.GetClient(rpcURL)
+	if err != nil {
+		return fmt.Errorf("failure connecting to %s: %w", rpcURL, err)
+	}
+	defer client.Close()
+	missing := []string{}
+	for _, address := range addresses {
+		deployed, err := evm.ContractAlreadyDeployed(client, address.Hex())
+		if err != nil {
+			return fmt.Errorf("failure making a request to %s: %w", rpcURL, err)
+		}
+		if !deployed {
+			missing = append(missing, address.Hex())
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("genesis contracts not found on chain: %s", strings.Join(missing, ", "))
+	}
+	ux.Logger.PrintToUser("Verified %d genesis contracts on chain", len(addresses))
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	foundryArtifact = `{"abi": [], "bytecode": {"object": "0x6080600a"}, "deployedBytecode": {"object": "0x6080", "linkReferences": {}}}`
+	hardhatArtifact = `{"contractName": "Multicall3", "abi": [], "bytecode": "0x6080600a", "deployedBytecode": "0x6001"}`
+)
+
+var (
+	multicallAddress = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+	tokenAddress     = common.HexToAddress("0x1000000000000000000000000000000000000001")
+)
+
+func TestReadContractArtifact(t *testing.T) {
+	require := setupTest(t)
+	dir := t.TempDir()
+	foundryPath := filepath.Join(dir, "Token.json")
+	require.NoError(os.WriteFile(foundryPath, []byte(foundryArtifact), 0o600))
+	code, err := ReadContractArtifact(foundryPath)
+	require.NoError(err)
+	require.Equal([]byte{0x60, 0x80}, code)
+
+	hardhatPath := filepath.Join(dir, "Multicall3.json")
+	require.NoError(os.WriteFile(hardhatPath, []byte(hardhatArtifact), 0o600))
+	code, err = ReadContractArtifact(hardhatPath)
+	require.NoError(err)
+	require.Equal([]byte{0x60, 0x01}, code)
+
+	unlinkedPath := filepath.Join(dir, "Unlinked.json")
+	require.NoError(os.WriteFile(unlinkedPath, []byte(`{"deployedBytecode": "0x73__$abc$__"}`), 0o600))
+	_, err = ReadContractArtifact(unlinkedPath)
+	require.ErrorContains(err, "unlinked library")
+}
+
+func TestLoadGenesisContractsFile(t *testing.T) {
+	require := setupTest(t)
+	dir := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(dir, "out"), 0o700))
+	require.NoError(os.WriteFile(filepath.Join(dir, "out", "Multicall3.json"), []byte(hardhatArtifact), 0o600))
+	manifest := `- address: 0xcA11bde05977b3631167028862bE2a173976CA11
+  artifact: out/Multicall3.json
+- name: GasToken
+  address: "0x1000000000000000000000000000000000000001"
+  bytecode: "0x6080"
+  storage:
+    "0x2": "0x64"
+  balance: "1000"
+`
+	manifestPath := filepath.Join(dir, "contracts.yaml")
+	require.NoError(os.WriteFile(manifestPath, []byte(manifest), 0o600))
+	contracts, err := LoadGenesisContractsFile(manifestPath)
+	require.NoError(err)
+	require.Len(contracts, 2)
+	require.Equal("Multicall3", contracts[0].Name)
+	require.Equal(multicallAddress, contracts[0].Address)
+	require.Equal([]byte{0x60, 0x01}, contracts[0].Code)
+	require.Equal("GasToken", contracts[1].Name)
+	require.Equal(common.BigToHash(big.NewInt(100)), contracts[1].Storage[common.BigToHash(big.NewInt(2))])
+	require.Equal(big.NewInt(1000), contracts[1].Balance)
+}
+
+func TestParseGenesisContractFlag(t *testing.T) {
+	require := setupTest(t)
+	contract, err := ParseGenesisContractFlag(tokenAddress.Hex() + "=0x6080")
+	require.NoError(err)
+	require.Equal(tokenAddress, contract.Address)
+	require.Equal([]byte{0x60, 0x80}, contract.Code)
+
+	_, err = ParseGenesisContractFlag(tokenAddress.Hex())
+	require.ErrorContains(err, "expected <address>=")
+	_, err = ParseGenesisContractFlag("0x0200000000000000000000000000000000000001=0x6080")
+	require.ErrorContains(err, "reserved")
+	_, err = ParseGenesisContractFlag(tokenAddress.Hex() + "=0x")
+	require.ErrorContains(err, "empty")
+}
+
+func TestAddGenesisContracts(t *testing.T) {
+	require := setupTest(t)
+	alloc := core.GenesisAlloc{
+		tokenAddress: {Balance: big.NewInt(5)},
+	}
+	contracts := []GenesisContract{
+		{Name: "Token", Address: tokenAddress, Code: []byte{0x60, 0x80}, Balance: big.NewInt(1)},
+		{Name: "Multicall3", Address: multicallAddress, Code: []byte{0x60, 0x01}, Balance: big.NewInt(0)},
+	}
+	require.NoError(AddGenesisContracts(alloc, contracts))
+	require.Equal(big.NewInt(6), alloc[tokenAddress].Balance)
+	require.Equal([]byte{0x60, 0x80}, alloc[tokenAddress].Code)
+	require.ErrorContains(AddGenesisContracts(alloc, contracts[:1]), "already has code")
+
+	genesisBytes, err := json.Marshal(core.Genesis{Alloc: alloc, Difficulty: Difficulty})
+	require.NoError(err)
+	addresses, err := GenesisContractAddresses(genesisBytes)
+	require.NoError(err)
+	require.Equal([]common.Address{tokenAddress, multicallAddress}, addresses)
+}
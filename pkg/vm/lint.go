@@ -0,0 +1,264 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/allowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type LintSeverity int
+
+const (
+	LintInfo LintSeverity = iota
+	LintWarning
+	LintError
+)
+
+func (s LintSeverity) String() string {
+	switch s {
+	case LintInfo:
+		return "info"
+	case LintWarning:
+		return "warning"
+	case LintError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// lint rule names
+const (
+	RuleGenesisFormat = "genesis-format"
+	RuleChainID       = "chain-id"
+	RuleFeeConfig     = "fee-config"
+	RuleGasLimit      = "gas-limit"
+	RuleAdminBalance  = "admin-balance"
+	RuleAllocation    = "allocation"
+	RuleWarpConfig    = "warp-config"
+	RuleChainConfig   = "chain-config"
+)
+
+const (
+	intrinsicTxGas  = 21_000
+	minSaneGasLimit = 1_000_000
+	maxSaneGasLimit = 100_000_000
+	// subnet-evm targets gas usage over a rolling window of this many seconds
+	targetGasWindowSec = 10
+)
+
+// well known EVM networks a subnet chain ID should not collide with, as
+// that enables replaying their transactions on the subnet and confuses wallets
+var wellKnownChainIDs = map[uint64]string{
+	1:        "Ethereum Mainnet",
+	5:        "Ethereum Goerli",
+	10:       "Optimism",
+	56:       "BNB Smart Chain",
+	137:      "Polygon",
+	250:      "Fantom",
+	8453:     "Base",
+	17000:    "Ethereum Holesky",
+	42161:    "Arbitrum One",
+	43112:    "Avalanche Local C-Chain",
+	43113:    "Avalanche Fuji C-Chain",
+	43114:    "Avalanche C-Chain",
+	11155111: "Ethereum Sepolia",
+}
+
+type LintIssue struct {
+	Rule     string
+	Severity LintSeverity
+	Message  string
+}
+
+type LintResult []LintIssue
+
+func (r LintResult) HasErrors() bool {
+	for _, issue := range r {
+		if issue.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
+
+func (r LintResult) Errors() LintResult {
+	errs := LintResult{}
+	for _, issue := range r {
+		if issue.Severity == LintError {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+func (r *LintResult) add(rule string, severity LintSeverity, format string, args ...interface{}) {
+	*r = append(*r, LintIssue{Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// LintGenesis checks a Subnet-EVM genesis for problems that would otherwise
+// only surface at chain creation or once the chain is running. Set
+// teleporterReady if the subnet is expected to support teleporter.
+func LintGenesis(genesisBytes []byte, teleporterReady bool) LintResult {
+	result := LintResult{}
+	var genesis core.Genesis
+	if err := json.Unmarshal(genesisBytes, &genesis); err != nil {
+		result.add(RuleGenesisFormat, LintError, "genesis is not a valid Subnet-EVM genesis: %s", err)
+		return result
+	}
+	if genesis.Config == nil {
+		result.add(RuleGenesisFormat, LintError, "genesis has no config")
+		return result
+	}
+	lintChainID(&result, genesis.Config)
+	feeConfigOK := lintFeeConfig(&result, genesis.Config)
+	gasLimitOK := feeConfigOK && lintGasLimit(&result, &genesis)
+	lintAllowListAdmins(&result, &genesis)
+	lintAllocation(&result, &genesis)
+	if teleporterReady {
+		if _, ok := genesis.Config.GenesisPrecompiles[warp.ConfigKey]; !ok {
+			result.add(RuleWarpConfig, LintError, "subnet is teleporter ready but the genesis does not enable the warp precompile (%s)", warp.ConfigKey)
+		}
+	}
+	if gasLimitOK {
+		// remaining consistency checks done by subnet-evm itself (precompile and network upgrades)
+		genesis.Config.AvalancheContext = params.AvalancheContext{
+			SnowCtx: &snow.Context{},
+		}
+		if err := genesis.Verify(); err != nil {
+			result.add(RuleChainConfig, LintError, "%s", err)
+		}
+	}
+	return result
+}
+
+func lintChainID(result *LintResult, config *params.ChainConfig) {
+	if config.ChainID == nil || config.ChainID.Sign() <= 0 {
+		result.add(RuleChainID, LintError, "chain ID must be a positive integer")
+		return
+	}
+	if !config.ChainID.IsUint64() {
+		return
+	}
+	if network, ok := wellKnownChainIDs[config.ChainID.Uint64()]; ok {
+		result.add(RuleChainID, LintError, "chain ID %s collides with %s", config.ChainID, network)
+	}
+}
+
+func lintFeeConfig(result *LintResult, config *params.ChainConfig) bool {
+	feeConfig := config.FeeConfig
+	if err := feeConfig.Verify(); err != nil {
+		result.add(RuleFeeConfig, LintError, "%s", err)
+		return false
+	}
+	// gas that can be consumed in the target window at the configured block rate
+	maxWindowGas := new(big.Int).Mul(feeConfig.GasLimit, big.NewInt(targetGasWindowSec))
+	maxWindowGas.Div(maxWindowGas, new(big.Int).SetUint64(feeConfig.TargetBlockRate))
+	if feeConfig.TargetGas.Cmp(maxWindowGas) > 0 {
+		result.add(RuleFeeConfig, LintWarning,
+			"targetGas (%s) is above the gas that can be consumed in %ds with gasLimit %s and targetBlockRate %d (%s), so the base fee will never increase",
+			feeConfig.TargetGas, targetGasWindowSec, feeConfig.GasLimit, feeConfig.TargetBlockRate, maxWindowGas)
+	}
+	if feeConfig.MinBaseFee.Sign() == 0 {
+		result.add(RuleFeeConfig, LintWarning, "minBaseFee is 0: transactions can be free, which leaves the chain open to spam")
+	}
+	if feeConfig.MaxBlockGasCost.Sign() > 0 && feeConfig.BlockGasCostStep.Sign() == 0 {
+		result.add(RuleFeeConfig, LintInfo, "blockGasCostStep is 0, so the block gas cost never changes from its initial value")
+	}
+	return true
+}
+
+func lintGasLimit(result *LintResult, genesis *core.Genesis) bool {
+	gasLimit := genesis.Config.FeeConfig.GasLimit
+	if !gasLimit.IsUint64() || gasLimit.Uint64() != genesis.GasLimit {
+		result.add(RuleGasLimit, LintError, "gas limit in fee config (%s) does not match gas limit in header (%d)", gasLimit, genesis.GasLimit)
+		return false
+	}
+	switch {
+	case genesis.GasLimit < intrinsicTxGas:
+		result.add(RuleGasLimit, LintError, "gas limit %d is below the %d gas of a plain transfer", genesis.GasLimit, intrinsicTxGas)
+	case genesis.GasLimit < minSaneGasLimit:
+		result.add(RuleGasLimit, LintWarning, "gas limit %d is below %d, which may not fit contract deployments", genesis.GasLimit, minSaneGasLimit)
+	case genesis.GasLimit > maxSaneGasLimit:
+		result.add(RuleGasLimit, LintWarning, "gas limit %d is above %d, which may produce blocks validators can't process in time", genesis.GasLimit, maxSaneGasLimit)
+	}
+	return true
+}
+
+func lintAllowListAdmins(result *LintResult, genesis *core.Genesis) {
+	for _, configKey := range []string{
+		txallowlist.ConfigKey,
+		deployerallowlist.ConfigKey,
+		nativeminter.ConfigKey,
+		feemanager.ConfigKey,
+		rewardmanager.ConfigKey,
+	} {
+		allowList := GetAllowListConfig(genesis.Config.GenesisPrecompiles[configKey])
+		if allowList == nil {
+			continue
+		}
+		if configKey == txallowlist.ConfigKey {
+			allowed := append(append(append([]common.Address{}, allowList.AdminAddresses...), allowList.ManagerAddresses...), allowList.EnabledAddresses...)
+			if err := ensureAdminsHaveBalance(allowed, genesis.Alloc); err != nil {
+				result.add(RuleAdminBalance, LintError, "%s", err)
+				continue
+			}
+		}
+		for _, admin := range allowList.AdminAddresses {
+			if !hasBalance(genesis.Alloc, admin) {
+				result.add(RuleAdminBalance, LintWarning, "%s admin %s has no balance allocated, so it can't pay for admin transactions", configKey, admin)
+			}
+		}
+	}
+}
+
+func lintAllocation(result *LintResult, genesis *core.Genesis) {
+	if _, ok := genesis.Config.GenesisPrecompiles[nativeminter.ConfigKey]; ok {
+		return
+	}
+	for _, account := range genesis.Alloc {
+		if account.Balance != nil && account.Balance.Sign() > 0 {
+			return
+		}
+	}
+	if genesis.Config.FeeConfig.MinBaseFee != nil && genesis.Config.FeeConfig.MinBaseFee.Sign() == 0 {
+		return
+	}
+	result.add(RuleAllocation, LintError, "no address has a balance allocated and there is no native minter, so nobody can pay transaction fees")
+}
+
+// GetAllowListConfig returns the allow list of the precompile [config], if it
+// has one
+func GetAllowListConfig(config interface{}) *allowlist.AllowListConfig {
+	switch c := config.(type) {
+	case *txallowlist.Config:
+		return &c.AllowListConfig
+	case *deployerallowlist.Config:
+		return &c.AllowListConfig
+	case *nativeminter.Config:
+		return &c.AllowListConfig
+	case *feemanager.Config:
+		return &c.AllowListConfig
+	case *rewardmanager.Config:
+		return &c.AllowListConfig
+	}
+	return nil
+}
+
+func hasBalance(alloc core.GenesisAlloc, addr common.Address) bool {
+	account, ok := alloc[addr]
+	return ok && account.Balance != nil && account.Balance.Sign() > 0
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package vm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const lintTestGenesis = `{
+	"config": {
+		"chainId": 99999,
+		"homesteadBlock": 0,
+		"eip150Block": 0,
+		"eip155Block": 0,
+		"eip158Block": 0,
+		"byzantiumBlock": 0,
+		"constantinopleBlock": 0,
+		"petersburgBlock": 0,
+		"istanbulBlock": 0,
+		"muirGlacierBlock": 0,
+		"subnetEVMTimestamp": 0,
+		"durangoTimestamp": 0,
+		"feeConfig": {
+			"gasLimit": 8000000,
+			"targetBlockRate": 2,
+			"minBaseFee": 25000000000,
+			"targetGas": 15000000,
+			"baseFeeChangeDenominator": 36,
+			"minBlockGasCost": 0,
+			"maxBlockGasCost": 1000000,
+			"blockGasCostStep": 200000
+		},
+		"warpConfig": {
+			"blockTimestamp": 0
+		}
+	},
+	"alloc": {
+		"8db97c7cece249c2b98bdc0226cc4c2a57bf52fc": {
+			"balance": "0xd3c21bcecceda1000000"
+		}
+	},
+	"gasLimit": "0x7a1200",
+	"difficulty": "0x0"
+}`
+
+// lintTestGenesisWith returns the test genesis after applying modify to its
+// JSON map representation
+func lintTestGenesisWith(t *testing.T, modify func(genesis map[string]interface{}, config map[string]interface{}, feeConfig map[string]interface{})) []byte {
+	var genesis map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lintTestGenesis), &genesis))
+	config := genesis["config"].(map[string]interface{})
+	modify(genesis, config, config["feeConfig"].(map[string]interface{}))
+	genesisBytes, err := json.Marshal(genesis)
+	require.NoError(t, err)
+	return genesisBytes
+}
+
+func rulesWithSeverity(result LintResult, severity LintSeverity) []string {
+	rules := []string{}
+	for _, issue := range result {
+		if issue.Severity == severity {
+			rules = append(rules, issue.Rule)
+		}
+	}
+	return rules
+}
+
+func TestLintGenesis(t *testing.T) {
+	unfundedAdmin := "0x0000000000000000000000000000000000000bad"
+	tests := []struct {
+		name            string
+		modify          func(genesis, config, feeConfig map[string]interface{})
+		teleporterReady bool
+		errors          []string
+		warnings        []string
+	}{
+		{
+			name:            "valid genesis",
+			modify:          func(_, _, _ map[string]interface{}) {},
+			teleporterReady: true,
+			errors:          []string{},
+			warnings:        []string{},
+		},
+		{
+			name:   "not a genesis",
+			modify: func(genesis, _, _ map[string]interface{}) { delete(genesis, "config") },
+			errors: []string{RuleGenesisFormat},
+		},
+		{
+			name:     "chain id collision",
+			modify:   func(_, config, _ map[string]interface{}) { config["chainId"] = 43114 },
+			errors:   []string{RuleChainID},
+			warnings: []string{},
+		},
+		{
+			name:     "incoherent fee config",
+			modify:   func(_, _, feeConfig map[string]interface{}) { feeConfig["minBlockGasCost"] = 2000000 },
+			errors:   []string{RuleFeeConfig},
+			warnings: []string{},
+		},
+		{
+			name:     "target gas never reached",
+			modify:   func(_, _, feeConfig map[string]interface{}) { feeConfig["targetGas"] = 50000000 },
+			errors:   []string{},
+			warnings: []string{RuleFeeConfig},
+		},
+		{
+			name:     "gas limit mismatch",
+			modify:   func(genesis, _, _ map[string]interface{}) { genesis["gasLimit"] = "0x7a1201" },
+			errors:   []string{RuleGasLimit},
+			warnings: []string{},
+		},
+		{
+			name: "gas limit too high",
+			modify: func(genesis, _, feeConfig map[string]interface{}) {
+				genesis["gasLimit"] = "0x7735940"
+				feeConfig["gasLimit"] = 125000000
+				feeConfig["targetGas"] = 15000000
+			},
+			errors:   []string{},
+			warnings: []string{RuleGasLimit},
+		},
+		{
+			name: "tx allow list without funded addresses",
+			modify: func(_, config, _ map[string]interface{}) {
+				config["txAllowListConfig"] = map[string]interface{}{
+					"blockTimestamp": 0,
+					"adminAddresses": []string{unfundedAdmin},
+				}
+			},
+			errors:   []string{RuleAdminBalance},
+			warnings: []string{},
+		},
+		{
+			name: "unfunded minter admin",
+			modify: func(_, config, _ map[string]interface{}) {
+				config["contractNativeMinterConfig"] = map[string]interface{}{
+					"blockTimestamp": 0,
+					"adminAddresses": []string{unfundedAdmin},
+				}
+			},
+			errors:   []string{},
+			warnings: []string{RuleAdminBalance},
+		},
+		{
+			name:     "nobody can pay fees",
+			modify:   func(genesis, _, _ map[string]interface{}) { genesis["alloc"] = map[string]interface{}{} },
+			errors:   []string{RuleAllocation},
+			warnings: []string{},
+		},
+		{
+			name:            "teleporter without warp",
+			modify:          func(_, config, _ map[string]interface{}) { delete(config, "warpConfig") },
+			teleporterReady: true,
+			errors:          []string{RuleWarpConfig},
+			warnings:        []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			result := LintGenesis(lintTestGenesisWith(t, tt.modify), tt.teleporterReady)
+			require.Equal(tt.errors, rulesWithSeverity(result, LintError), result)
+			require.Equal(len(tt.errors) > 0, result.HasErrors())
+			if tt.warnings != nil {
+				require.Equal(tt.warnings, rulesWithSeverity(result, LintWarning), result)
+			}
+		})
+	}
+}
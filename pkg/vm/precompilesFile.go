@@ -0,0 +1,121 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ava-labs/subnet-evm/precompile/modules"
+	"golang.org/x/exp/slices"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// managerRoleMinVersion is the first subnet-evm release supporting the
+// manager role on allow list based precompiles (Durango)
+const managerRoleMinVersion = "v0.6.0"
+
+// precompileMinVersions holds the first subnet-evm release shipping each
+// precompile. Precompiles not listed are available on every supported
+// release
+var precompileMinVersions = map[string]string{
+	feemanager.ConfigKey:    "v0.2.8",
+	rewardmanager.ConfigKey: "v0.4.4",
+	warp.ConfigKey:          "v0.5.7",
+}
+
+// SupportedPrecompileKeys returns the sorted config keys of the precompiles
+// known to this build
+func SupportedPrecompileKeys() []string {
+	keys := []string{}
+	for _, module := range modules.RegisteredModules() {
+		keys = append(keys, module.ConfigKey)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LoadPrecompilesFile reads a JSON or YAML file mapping precompile config keys
+// (as in the config section of a subnet-evm genesis, eg. txAllowListConfig)
+// to their configs, and validates it against [subnetEVMVersion]
+func LoadPrecompilesFile(path string, subnetEVMVersion string) (params.Precompiles, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read precompiles file %s: %w", path, err)
+	}
+	precompiles, err := ParsePrecompiles(fileBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid precompiles file %s: %w", path, err)
+	}
+	if err := ValidatePrecompilesForVersion(precompiles, subnetEVMVersion); err != nil {
+		return nil, fmt.Errorf("invalid precompiles file %s: %w", path, err)
+	}
+	return precompiles, nil
+}
+
+// ParsePrecompiles decodes JSON or YAML precompile configs, failing on
+// unknown config keys instead of ignoring them
+func ParsePrecompiles(data []byte) (params.Precompiles, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	supported := SupportedPrecompileKeys()
+	for key := range doc {
+		if !slices.Contains(supported, key) {
+			return nil, fmt.Errorf("unknown precompile %q, supported ones are %s", key, strings.Join(supported, ", "))
+		}
+	}
+	// the precompile configs only know how to decode themselves from JSON
+	jsonBytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	precompiles := params.Precompiles{}
+	if err := json.Unmarshal(jsonBytes, &precompiles); err != nil {
+		return nil, err
+	}
+	return precompiles, nil
+}
+
+// ValidatePrecompilesForVersion checks that every precompile, and the
+// manager role of the allow lists, is supported by [subnetEVMVersion].
+// Versions that are not semantic versions are not checked
+func ValidatePrecompilesForVersion(precompiles params.Precompiles, subnetEVMVersion string) error {
+	if !semver.IsValid(subnetEVMVersion) {
+		return nil
+	}
+	for key, config := range precompiles {
+		if minVersion, ok := precompileMinVersions[key]; ok && semver.Compare(subnetEVMVersion, minVersion) < 0 {
+			return fmt.Errorf("%s requires subnet-evm %s or later, but %s is selected", key, minVersion, subnetEVMVersion)
+		}
+		if allowList := GetAllowListConfig(config); allowList != nil &&
+			len(allowList.ManagerAddresses) > 0 &&
+			semver.Compare(subnetEVMVersion, managerRoleMinVersion) < 0 {
+			return fmt.Errorf("%s: manager addresses require subnet-evm %s or later, but %s is selected", key, managerRoleMinVersion, subnetEVMVersion)
+		}
+	}
+	return nil
+}
+
+// setGenesisPrecompiles sets [precompiles] into [conf], overriding any
+// precompile already configured, and adds the default warp config when
+// [teleporterReady] and none was given
+func setGenesisPrecompiles(conf *params.ChainConfig, precompiles params.Precompiles, teleporterReady bool) {
+	for key, config := range precompiles {
+		conf.GenesisPrecompiles[key] = config
+	}
+	if _, ok := conf.GenesisPrecompiles[warp.ConfigKey]; !ok && teleporterReady {
+		warpConfig := configureWarp()
+		conf.GenesisPrecompiles[warp.ConfigKey] = &warpConfig
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/feemanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/warp"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testPrecompilesYAML = `txAllowListConfig:
+  blockTimestamp: 0
+  adminAddresses: ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"]
+  enabledAddresses: ["0x098B69E43b1720Bd12378225519d74e5F3aD0eA5"]
+contractDeployerAllowListConfig:
+  blockTimestamp: 0
+  adminAddresses: ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"]
+  managerAddresses: ["0x098B69E43b1720Bd12378225519d74e5F3aD0eA5"]
+contractNativeMinterConfig:
+  blockTimestamp: 0
+  adminAddresses: ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"]
+  initialMint:
+    "0x098B69E43b1720Bd12378225519d74e5F3aD0eA5": "0x64"
+feeManagerConfig:
+  blockTimestamp: 0
+  adminAddresses: ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"]
+rewardManagerConfig:
+  blockTimestamp: 0
+  adminAddresses: ["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"]
+  initialRewardConfig:
+    allowFeeRecipients: true
+`
+
+func TestParsePrecompiles(t *testing.T) {
+	require := setupTest(t)
+	precompiles, err := ParsePrecompiles([]byte(testPrecompilesYAML))
+	require.NoError(err)
+	require.Len(precompiles, 5)
+	txConfig, ok := precompiles[txallowlist.ConfigKey].(*txallowlist.Config)
+	require.True(ok)
+	require.Equal([]common.Address{PrefundedEwoqAddress}, txConfig.AdminAddresses)
+	require.Equal([]common.Address{testAirdropAddress}, txConfig.EnabledAddresses)
+	deployerConfig, ok := precompiles[deployerallowlist.ConfigKey].(*deployerallowlist.Config)
+	require.True(ok)
+	require.Equal([]common.Address{testAirdropAddress}, deployerConfig.ManagerAddresses)
+	minterConfig, ok := precompiles[nativeminter.ConfigKey].(*nativeminter.Config)
+	require.True(ok)
+	require.Equal(int64(100), (*big.Int)(minterConfig.InitialMint[testAirdropAddress]).Int64())
+	rewardConfig, ok := precompiles[rewardmanager.ConfigKey].(*rewardmanager.Config)
+	require.True(ok)
+	require.True(rewardConfig.InitialRewardConfig.AllowFeeRecipients)
+
+	// JSON works too
+	precompiles, err = ParsePrecompiles([]byte(`{"warpConfig": {"blockTimestamp": 0, "quorumNumerator": 80}}`))
+	require.NoError(err)
+	require.Contains(precompiles, warp.ConfigKey)
+
+	_, err = ParsePrecompiles([]byte(`{"txAllowListConfigs": {}}`))
+	require.ErrorContains(err, `unknown precompile "txAllowListConfigs"`)
+}
+
+func TestValidatePrecompilesForVersion(t *testing.T) {
+	require := setupTest(t)
+	precompiles, err := ParsePrecompiles([]byte(testPrecompilesYAML))
+	require.NoError(err)
+	require.NoError(ValidatePrecompilesForVersion(precompiles, "v0.6.3"))
+	require.NoError(ValidatePrecompilesForVersion(precompiles, "custom"))
+	require.ErrorContains(ValidatePrecompilesForVersion(precompiles, "v0.5.10"), "manager addresses require subnet-evm v0.6.0")
+	delete(precompiles, deployerallowlist.ConfigKey)
+	require.NoError(ValidatePrecompilesForVersion(precompiles, "v0.5.10"))
+	require.ErrorContains(ValidatePrecompilesForVersion(precompiles, "v0.4.0"), "rewardManagerConfig requires subnet-evm v0.4.4")
+}
+
+func TestCreateEvmGenesisWithPrecompilesFile(t *testing.T) {
+	require := setupTest(t)
+	path := filepath.Join(t.TempDir(), "precompiles.yaml")
+	require.NoError(os.WriteFile(path, []byte(testPrecompilesYAML), 0o600))
+	precompiles, err := LoadPrecompilesFile(path, "v0.6.3")
+	require.NoError(err)
+
+	// defaults avoid the fee and airdrop prompts, and the precompiles prompt is
+	// skipped because of the file
+	genesisBytes, _, err := createEvmGenesis(application.New(), "test", "v0.6.3", 35, 12345, testToken, true, true, "", nil, nil, precompiles)
+	require.NoError(err)
+	var genesis core.Genesis
+	require.NoError(json.Unmarshal(genesisBytes, &genesis))
+	for _, key := range []string{
+		txallowlist.ConfigKey,
+		deployerallowlist.ConfigKey,
+		nativeminter.ConfigKey,
+		feemanager.ConfigKey,
+		rewardmanager.ConfigKey,
+		warp.ConfigKey,
+	} {
+		require.Contains(genesis.Config.GenesisPrecompiles, key)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/subnet-evm/commontype"
+	"github.com/ava-labs/subnet-evm/core"
+	"github.com/ava-labs/subnet-evm/params"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	BuiltinTemplateSource = "builtin"
+
+	templateParamAddress = "address"
+	templateParamUint    = "uint"
+	templateParamString  = "string"
+)
+
+//go:embed templates/*.yaml
+var builtinTemplates embed.FS
+
+var ErrTemplateNotFound = errors.New("genesis template not found")
+
+// GenesisTemplateParam is a value a template can be customized with
+type GenesisTemplateParam = models.TemplateParam
+
+// GenesisTemplate is a named and versioned Subnet-EVM genesis preset. Its
+// Genesis field is a Go text/template, rendered with the param values, that
+// yields a YAML document with the optional keys chainId, tokenName,
+// feeConfig, allowFeeRecipients, precompiles and alloc
+type GenesisTemplate struct {
+	Name        string                 `yaml:"name"`
+	Version     string                 `yaml:"version"`
+	Description string                 `yaml:"description"`
+	Params      []GenesisTemplateParam `yaml:"params,omitempty"`
+	Genesis     string                 `yaml:"genesis"`
+	// Source is either builtin or the file the template was loaded from
+	Source string `yaml:"-"`
+}
+
+// EvmGenesisTemplate is a rendered template, ready to be used by
+// CreateEvmSubnetConfig
+type EvmGenesisTemplate struct {
+	// Ref identifies the template as name@version
+	Ref                string               `json:"-"`
+	ChainID            uint64               `json:"chainId,omitempty"`
+	TokenName          string               `json:"tokenName,omitempty"`
+	FeeConfig          commontype.FeeConfig `json:"feeConfig"`
+	AllowFeeRecipients bool                 `json:"allowFeeRecipients,omitempty"`
+	Precompiles        params.Precompiles   `json:"precompiles,omitempty"`
+	Alloc              core.GenesisAlloc    `json:"alloc,omitempty"`
+}
+
+// Ref returns name@version
+func (t *GenesisTemplate) Ref() string {
+	return t.Name + "@" + t.Version
+}
+
+// Render fills the template params with [values], falling back to the param
+// defaults, and parses the resulting genesis settings
+func (t *GenesisTemplate) Render(values map[string]string) (*EvmGenesisTemplate, error) {
+	data, err := resolveTemplateParams(t.Ref(), t.Params, values)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(t.Genesis)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", t.Ref(), err)
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", t.Ref(), err)
+	}
+	// the genesis types only know how to decode themselves from JSON
+	var doc interface{}
+	if err := yaml.Unmarshal(rendered.Bytes(), &doc); err != nil {
+		return nil, fmt.Errorf("template %s does not render to valid YAML: %w", t.Ref(), err)
+	}
+	jsonBytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", t.Ref(), err)
+	}
+	evmTemplate := EvmGenesisTemplate{}
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&evmTemplate); err != nil {
+		return nil, fmt.Errorf("template %s has invalid genesis settings: %w", t.Ref(), err)
+	}
+	if evmTemplate.FeeConfig.GasLimit == nil {
+		evmTemplate.FeeConfig = StarterFeeConfig
+	}
+	if err := evmTemplate.FeeConfig.Verify(); err != nil {
+		return nil, fmt.Errorf("template %s has an invalid fee config: %w", t.Ref(), err)
+	}
+	if evmTemplate.Precompiles == nil {
+		evmTemplate.Precompiles = params.Precompiles{}
+	}
+	if evmTemplate.Alloc == nil {
+		evmTemplate.Alloc = core.GenesisAlloc{}
+	}
+	evmTemplate.Ref = t.Ref()
+	return &evmTemplate, nil
+}
+
+// resolveTemplateParams checks [values] against [params] and fills in the
+// defaults of the params without a value
+func resolveTemplateParams(ref string, params []GenesisTemplateParam, values map[string]string) (map[string]string, error) {
+	data := map[string]string{}
+	known := map[string]bool{}
+	for _, param := range params {
+		known[param.Name] = true
+		value, ok := values[param.Name]
+		if !ok {
+			if param.Default == nil {
+				return nil, fmt.Errorf("template %s requires a value for param %q (%s)", ref, param.Name, param.Description)
+			}
+			value = *param.Default
+		}
+		if err := validateTemplateParam(param, value); err != nil {
+			return nil, fmt.Errorf("template %s: %w", ref, err)
+		}
+		data[param.Name] = value
+	}
+	for name := range values {
+		if !known[name] {
+			return nil, fmt.Errorf("template %s has no param %q", ref, name)
+		}
+	}
+	return data, nil
+}
+
+func validateTemplateParam(param GenesisTemplateParam, value string) error {
+	switch param.Type {
+	case "", templateParamString:
+	case templateParamAddress:
+		if !common.IsHexAddress(value) {
+			return fmt.Errorf("param %q must be an address, got %q", param.Name, value)
+		}
+	case templateParamUint:
+		if n, ok := new(big.Int).SetString(value, 10); !ok || n.Sign() < 0 {
+			return fmt.Errorf("param %q must be a non negative integer, got %q", param.Name, value)
+		}
+	default:
+		return fmt.Errorf("param %q has unknown type %q", param.Name, param.Type)
+	}
+	return nil
+}
+
+func parseGenesisTemplate(templateBytes []byte, source string) (*GenesisTemplate, error) {
+	t := &GenesisTemplate{}
+	if err := yaml.Unmarshal(templateBytes, t); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis template %s: %w", source, err)
+	}
+	if t.Name == "" || t.Version == "" || t.Genesis == "" {
+		return nil, fmt.Errorf("genesis template %s needs a name, a version and a genesis", source)
+	}
+	if strings.Contains(t.Name, "@") {
+		return nil, fmt.Errorf("genesis template %s: name %q cannot contain @", source, t.Name)
+	}
+	if !semver.IsValid(canonicalTemplateVersion(t.Version)) {
+		return nil, fmt.Errorf("genesis template %s: version %q is not a semantic version", source, t.Version)
+	}
+	t.Source = source
+	return t, nil
+}
+
+func canonicalTemplateVersion(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
+// LoadGenesisTemplates returns the builtin templates followed by the ones found
+// in the *.yaml files of [dirs]. Missing dirs are ignored
+func LoadGenesisTemplates(dirs []string) ([]*GenesisTemplate, error) {
+	templates := []*GenesisTemplate{}
+	builtinFiles, err := fs.Glob(builtinTemplates, "templates/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range builtinFiles {
+		templateBytes, err := builtinTemplates.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseGenesisTemplate(templateBytes, BuiltinTemplateSource)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		ymlFiles, err := filepath.Glob(filepath.Join(dir, "*.yml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range append(files, ymlFiles...) {
+			templateBytes, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			t, err := parseGenesisTemplate(templateBytes, file)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, t)
+		}
+	}
+	return templates, nil
+}
+
+// FindGenesisTemplate looks up [ref], given either as name or name@version.
+// Without a version the highest one is selected. When a name and version is
+// defined more than once, the template loaded last wins, so user templates
+// can shadow builtin ones
+func FindGenesisTemplate(templates []*GenesisTemplate, ref string) (*GenesisTemplate, error) {
+	name, version, pinned := strings.Cut(ref, "@")
+	var found *GenesisTemplate
+	for _, t := range templates {
+		if t.Name != name {
+			continue
+		}
+		if pinned {
+			if semver.Compare(canonicalTemplateVersion(t.Version), canonicalTemplateVersion(version)) == 0 {
+				found = t
+			}
+			continue
+		}
+		if found == nil || semver.Compare(canonicalTemplateVersion(t.Version), canonicalTemplateVersion(found.Version)) >= 0 {
+			found = t
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, ref)
+	}
+	return found, nil
+}
+
+// SortGenesisTemplates orders templates by name and then by descending version
+func SortGenesisTemplates(templates []*GenesisTemplate) {
+	sort.SliceStable(templates, func(i, j int) bool {
+		if templates[i].Name != templates[j].Name {
+			return templates[i].Name < templates[j].Name
+		}
+		return semver.Compare(canonicalTemplateVersion(templates[i].Version), canonicalTemplateVersion(templates[j].Version)) > 0
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const userTemplate = `name: gaming
+version: 1.1.0
+description: shadows the builtin gaming template
+params:
+  - name: chainId
+    type: uint
+    default: "777"
+genesis: |
+  chainId: {{ .chainId }}
+  tokenName: MINE
+`
+
+func TestBuiltinGenesisTemplates(t *testing.T) {
+	require := setupTest(t)
+	templates, err := LoadGenesisTemplates(nil)
+	require.NoError(err)
+	SortGenesisTemplates(templates)
+	names := []string{}
+	for _, tmpl := range templates {
+		names = append(names, tmpl.Name)
+		require.Equal(BuiltinTemplateSource, tmpl.Source)
+	}
+	require.Equal([]string{"enterprise", "gaming", "high-throughput"}, names)
+
+	admin := "0x098B69E43b1720Bd12378225519d74e5F3aD0eA5"
+	for _, tmpl := range templates {
+		values := map[string]string{}
+		if tmpl.Name == "enterprise" {
+			values["admin"] = admin
+		}
+		evmTemplate, err := tmpl.Render(values)
+		require.NoError(err, tmpl.Name)
+		genesisBytes, _, err := createEvmGenesis(application.New(), "test", "v0.6.3", 35, 12345, "", false, true, "", nil, evmTemplate, nil)
+		require.NoError(err, tmpl.Name)
+		result := LintGenesis(genesisBytes, true)
+		require.False(result.HasErrors(), "%s: %v", tmpl.Name, result)
+	}
+}
+
+func TestRenderGenesisTemplate(t *testing.T) {
+	require := setupTest(t)
+	templates, err := LoadGenesisTemplates(nil)
+	require.NoError(err)
+	enterprise, err := FindGenesisTemplate(templates, "enterprise")
+	require.NoError(err)
+
+	_, err = enterprise.Render(nil)
+	require.ErrorContains(err, `requires a value for param "admin"`)
+	_, err = enterprise.Render(map[string]string{"admin": "0x1234"})
+	require.ErrorContains(err, "must be an address")
+	_, err = enterprise.Render(map[string]string{"admin": testAirdropAddress.Hex(), "unknown": "1"})
+	require.ErrorContains(err, `has no param "unknown"`)
+
+	evmTemplate, err := enterprise.Render(map[string]string{"admin": testAirdropAddress.Hex(), "tokenName": "ACME"})
+	require.NoError(err)
+	require.Equal("enterprise@1.0.0", evmTemplate.Ref)
+	require.Equal("ACME", evmTemplate.TokenName)
+	txAllowList, ok := evmTemplate.Precompiles[txallowlist.ConfigKey].(*txallowlist.Config)
+	require.True(ok)
+	require.Equal([]common.Address{testAirdropAddress}, txAllowList.AdminAddresses)
+	require.NotNil(evmTemplate.Alloc[testAirdropAddress].Balance)
+}
+
+func TestUserGenesisTemplates(t *testing.T) {
+	require := setupTest(t)
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "mine.yaml"), []byte(userTemplate), 0o600))
+	templates, err := LoadGenesisTemplates([]string{dir, filepath.Join(dir, "missing")})
+	require.NoError(err)
+
+	latest, err := FindGenesisTemplate(templates, "gaming")
+	require.NoError(err)
+	require.Equal("1.1.0", latest.Version)
+	require.Equal(filepath.Join(dir, "mine.yaml"), latest.Source)
+	pinned, err := FindGenesisTemplate(templates, "gaming@1.0.0")
+	require.NoError(err)
+	require.Equal(BuiltinTemplateSource, pinned.Source)
+	_, err = FindGenesisTemplate(templates, "gaming@2.0.0")
+	require.ErrorIs(err, ErrTemplateNotFound)
+
+	evmTemplate, err := latest.Render(nil)
+	require.NoError(err)
+	require.Equal(uint64(777), evmTemplate.ChainID)
+	require.Equal(StarterFeeConfig, evmTemplate.FeeConfig)
+
+	require.NoError(os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("name: broken\nversion: latest\ngenesis: x\n"), 0o600))
+	_, err = LoadGenesisTemplates([]string{dir})
+	require.ErrorContains(err, "not a semantic version")
+}
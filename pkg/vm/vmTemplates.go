@@ -0,0 +1,315 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrVMTemplateNotFound = errors.New("VM template not found")
+
+	vmTemplateNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+	// builtinVMTypes can't be used as VM template names
+	builtinVMTypes = []string{models.SubnetEvm, models.BlobVM, models.TimestampVM, models.CustomVM}
+
+	// registeredVMTemplates holds the templates compiled into the CLI
+	registeredVMTemplates = map[string]VMTemplate{}
+)
+
+// VMTemplate gives a VM first class create, deploy and upgrade support.
+// Subnets created from a template use its name as VM type
+type VMTemplate interface {
+	Name() string
+	Description() string
+	// GenesisParams describes the values the genesis is created from. They
+	// are asked to the user when not given
+	GenesisParams() []GenesisTemplateParam
+	// CreateGenesis builds the genesis from the param values
+	CreateGenesis(values map[string]string) ([]byte, error)
+	// DefaultChainConfig returns the chain config of new subnets, or nil
+	DefaultChainConfig() ([]byte, error)
+	// Versions returns the installable versions, newest first, or nil when
+	// the VM is built from source, in which case versions are git refs
+	Versions(app *application.Avalanche) ([]string, error)
+	// SetupBinary installs or builds [version] of the VM for the subnet of
+	// [sc], and returns the binary path
+	SetupBinary(app *application.Avalanche, sc *models.Sidecar, version string) (string, error)
+	// RPCVersion returns the RPC protocol version of [version] of the VM
+	RPCVersion(app *application.Avalanche, sc *models.Sidecar, version string) (int, error)
+}
+
+// RegisterVMTemplate makes [t] available to every command. Meant to be
+// called from init functions
+func RegisterVMTemplate(t VMTemplate) error {
+	if err := validateVMTemplateName(t.Name()); err != nil {
+		return err
+	}
+	if _, ok := registeredVMTemplates[t.Name()]; ok {
+		return fmt.Errorf("VM template %s is already registered", t.Name())
+	}
+	registeredVMTemplates[t.Name()] = t
+	return nil
+}
+
+func validateVMTemplateName(name string) error {
+	if !vmTemplateNameRegex.MatchString(name) {
+		return fmt.Errorf("VM template name %q must start with a letter or digit and contain only letters, digits, - and _", name)
+	}
+	if slices.Contains(builtinVMTypes, name) {
+		return fmt.Errorf("VM template name %q is reserved for a builtin VM", name)
+	}
+	return nil
+}
+
+// ManifestVMTemplate is a VM template described by a manifest file
+type ManifestVMTemplate struct {
+	Manifest models.VMTemplateManifest
+	// Source is the manifest file, or the sidecar it was loaded from
+	Source string
+}
+
+var _ VMTemplate = (*ManifestVMTemplate)(nil)
+
+// NewManifestVMTemplate validates [manifest]
+func NewManifestVMTemplate(manifest models.VMTemplateManifest, source string) (*ManifestVMTemplate, error) {
+	if err := validateVMTemplateName(manifest.Name); err != nil {
+		return nil, fmt.Errorf("invalid VM template %s: %w", source, err)
+	}
+	binary := manifest.Binary
+	switch {
+	case binary.GitHub != nil && binary.Build != nil:
+		return nil, fmt.Errorf("invalid VM template %s: binary must have either a github or a build section, not both", source)
+	case binary.GitHub != nil:
+		if binary.GitHub.Org == "" || binary.GitHub.Repo == "" || binary.GitHub.Asset == "" || binary.GitHub.Binary == "" {
+			return nil, fmt.Errorf("invalid VM template %s: binary.github needs an org, a repo, an asset and a binary", source)
+		}
+		if !strings.HasSuffix(binary.GitHub.Asset, ".tar.gz") {
+			return nil, fmt.Errorf("invalid VM template %s: binary.github.asset must be a tar.gz archive", source)
+		}
+		if _, err := template.New("asset").Parse(binary.GitHub.Asset); err != nil {
+			return nil, fmt.Errorf("invalid VM template %s: binary.github.asset: %w", source, err)
+		}
+	case binary.Build != nil:
+		if binary.Build.Repo == "" || binary.Build.Script == "" {
+			return nil, fmt.Errorf("invalid VM template %s: binary.build needs a repo and a script", source)
+		}
+	default:
+		return nil, fmt.Errorf("invalid VM template %s: binary must have a github or a build section", source)
+	}
+	if manifest.Genesis.Template == "" {
+		return nil, fmt.Errorf("invalid VM template %s: genesis.template is required", source)
+	}
+	if _, err := template.New(manifest.Name).Parse(manifest.Genesis.Template); err != nil {
+		return nil, fmt.Errorf("invalid VM template %s: genesis.template: %w", source, err)
+	}
+	for _, param := range manifest.Genesis.Params {
+		if param.Default == nil {
+			continue
+		}
+		if err := validateTemplateParam(param, *param.Default); err != nil {
+			return nil, fmt.Errorf("invalid VM template %s: default value: %w", source, err)
+		}
+	}
+	return &ManifestVMTemplate{Manifest: manifest, Source: source}, nil
+}
+
+func (t *ManifestVMTemplate) Name() string {
+	return t.Manifest.Name
+}
+
+func (t *ManifestVMTemplate) Description() string {
+	return t.Manifest.Description
+}
+
+func (t *ManifestVMTemplate) GenesisParams() []GenesisTemplateParam {
+	return t.Manifest.Genesis.Params
+}
+
+func (t *ManifestVMTemplate) CreateGenesis(values map[string]string) ([]byte, error) {
+	data, err := resolveTemplateParams(t.Name(), t.Manifest.Genesis.Params, values)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(t.Name()).Option("missingkey=error").Parse(t.Manifest.Genesis.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VM template %s: %w", t.Name(), err)
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return nil, fmt.Errorf("failed to render the genesis of VM template %s: %w", t.Name(), err)
+	}
+	return bytes.TrimSpace(rendered.Bytes()), nil
+}
+
+func (t *ManifestVMTemplate) DefaultChainConfig() ([]byte, error) {
+	if len(t.Manifest.ChainConfig) == 0 {
+		return nil, nil
+	}
+	return json.MarshalIndent(t.Manifest.ChainConfig, "", "    ")
+}
+
+func (t *ManifestVMTemplate) Versions(app *application.Avalanche) ([]string, error) {
+	if t.Manifest.Binary.GitHub == nil {
+		return nil, nil
+	}
+	return app.Downloader.GetAllReleasesForRepo(t.Manifest.Binary.GitHub.Org, t.Manifest.Binary.GitHub.Repo)
+}
+
+func (t *ManifestVMTemplate) SetupBinary(app *application.Avalanche, sc *models.Sidecar, version string) (string, error) {
+	if release := t.Manifest.Binary.GitHub; release != nil {
+		_, vmBin, err := binutils.SetupGithubReleaseVM(app, t.Name(), release.Org, release.Repo, release.Asset, release.Binary, version)
+		if err != nil {
+			return "", fmt.Errorf("failed to install %s %s: %w", t.Name(), version, err)
+		}
+		return vmBin, nil
+	}
+	vmBin := app.GetCustomVMPath(sc.Name)
+	if utils.FileExists(vmBin) && sc.CustomVMBranch == version {
+		return vmBin, VerifyCustomVMBinary(sc, vmBin)
+	}
+	build := *sc
+	build.CustomVMRepoURL = t.Manifest.Binary.Build.Repo
+	build.CustomVMBuildScript = t.Manifest.Binary.Build.Script
+	build.CustomVMBranch = version
+	// a new version drops the pin of the previous build
+	if sc.CustomVMBuild == nil || sc.CustomVMBranch != version {
+		build.CustomVMBuild = &models.CustomVMBuild{BuildImage: t.Manifest.Binary.Build.Image}
+	}
+	if err := BuildCustomVM(app, &build); err != nil {
+		return "", err
+	}
+	sc.CustomVMBuild = build.CustomVMBuild
+	return vmBin, nil
+}
+
+func (t *ManifestVMTemplate) RPCVersion(app *application.Avalanche, sc *models.Sidecar, version string) (int, error) {
+	if t.Manifest.CompatibilityURL != "" {
+		return getRPCProtocolVersionFromURL(app, t.Manifest.CompatibilityURL, version)
+	}
+	vmBin, err := t.SetupBinary(app, sc, version)
+	if err != nil {
+		return 0, err
+	}
+	return GetVMBinaryProtocolVersion(vmBin)
+}
+
+// DefaultVMTemplateVersion returns the version new subnets use when none is
+// given: the latest release, or the default branch for VMs built from source
+func DefaultVMTemplateVersion(app *application.Avalanche, t VMTemplate) (string, error) {
+	versions, err := t.Versions(app)
+	if err != nil {
+		return "", err
+	}
+	if len(versions) > 0 {
+		return versions[0], nil
+	}
+	if mt, ok := t.(*ManifestVMTemplate); ok && mt.Manifest.Binary.Build != nil && mt.Manifest.Binary.Build.Branch != "" {
+		return mt.Manifest.Binary.Build.Branch, nil
+	}
+	return "", fmt.Errorf("no version available for VM template %s", t.Name())
+}
+
+// LoadVMTemplateManifests loads the *.yaml and *.yml manifests of [dirs].
+// Missing dirs are ignored
+func LoadVMTemplateManifests(dirs []string) ([]*ManifestVMTemplate, error) {
+	templates := []*ManifestVMTemplate{}
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		ymlFiles, err := filepath.Glob(filepath.Join(dir, "*.yml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range append(files, ymlFiles...) {
+			manifestBytes, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			var manifest models.VMTemplateManifest
+			if err := yaml.Unmarshal(manifestBytes, &manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse VM template %s: %w", file, err)
+			}
+			t, err := NewManifestVMTemplate(manifest, file)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, t)
+		}
+	}
+	return templates, nil
+}
+
+// ListVMTemplates returns the registered VM templates and the ones of the
+// manifests in the VM templates dir and the dirs registered in the config,
+// sorted by name. A manifest can't shadow another template
+func ListVMTemplates(app *application.Avalanche) ([]VMTemplate, error) {
+	dirs := []string{app.GetVMTemplatesDir()}
+	dirs = append(dirs, app.Conf.GetConfigStringSliceValue(constants.ConfigVMTemplateDirsKey)...)
+	manifests, err := LoadVMTemplateManifests(dirs)
+	if err != nil {
+		return nil, err
+	}
+	templates := []VMTemplate{}
+	sources := map[string]string{}
+	for name, t := range registeredVMTemplates {
+		templates = append(templates, t)
+		sources[name] = BuiltinTemplateSource
+	}
+	for _, t := range manifests {
+		if source, ok := sources[t.Name()]; ok {
+			return nil, fmt.Errorf("VM template %s of %s is already defined by %s", t.Name(), t.Source, source)
+		}
+		templates = append(templates, t)
+		sources[t.Name()] = t.Source
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name() < templates[j].Name()
+	})
+	return templates, nil
+}
+
+// GetVMTemplate looks up the VM template [name]
+func GetVMTemplate(app *application.Avalanche, name string) (VMTemplate, error) {
+	templates, err := ListVMTemplates(app)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range templates {
+		if t.Name() == name {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrVMTemplateNotFound, name)
+}
+
+// GetVMTemplateForSidecar returns the VM template of a subnet. The manifest
+// stored in the sidecar is used when present, so that subnets keep working on
+// hosts that don't have the manifest, as cluster nodes
+func GetVMTemplateForSidecar(app *application.Avalanche, sc *models.Sidecar) (VMTemplate, error) {
+	if sc.VMTemplate != nil {
+		return NewManifestVMTemplate(*sc.VMTemplate, app.GetSidecarPath(sc.Name))
+	}
+	return GetVMTemplate(app, string(sc.VM))
+}
@@ -0,0 +1,175 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+)
+
+const testVMManifest = `name: morpheusvm
+description: HyperSDK token VM
+binary:
+  github:
+    org: ava-labs
+    repo: hypersdk
+    asset: "morpheusvm_{{.VersionNumber}}_{{.OS}}_{{.Arch}}.tar.gz"
+    binary: morpheusvm
+chainConfig:
+  mempoolSize: 10000000
+genesis:
+  params:
+    - name: owner
+      description: address receiving the initial supply
+    - name: supply
+      type: uint
+      default: "1000000"
+  template: |
+    {"customAllocation":[{"address":"{{ .owner }}","balance":{{ .supply }}}]}
+`
+
+type testVMTemplate struct {
+	versions []string
+	built    []string
+}
+
+func (*testVMTemplate) Name() string                                    { return "testvm" }
+func (*testVMTemplate) Description() string                             { return "" }
+func (*testVMTemplate) GenesisParams() []GenesisTemplateParam           { return nil }
+func (*testVMTemplate) CreateGenesis(map[string]string) ([]byte, error) { return []byte("{}"), nil }
+func (*testVMTemplate) DefaultChainConfig() ([]byte, error)             { return nil, nil }
+
+func (t *testVMTemplate) Versions(*application.Avalanche) ([]string, error) {
+	return t.versions, nil
+}
+
+func (t *testVMTemplate) SetupBinary(_ *application.Avalanche, _ *models.Sidecar, version string) (string, error) {
+	t.built = append(t.built, version)
+	return "/bin/testvm", nil
+}
+
+func (*testVMTemplate) RPCVersion(*application.Avalanche, *models.Sidecar, string) (int, error) {
+	return 35, nil
+}
+
+func TestLoadVMTemplateManifests(t *testing.T) {
+	require := setupTest(t)
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "morpheusvm.yaml"), []byte(testVMManifest), 0o600))
+
+	templates, err := LoadVMTemplateManifests([]string{dir, filepath.Join(dir, "missing")})
+	require.NoError(err)
+	require.Len(templates, 1)
+	vmTemplate := templates[0]
+	require.Equal("morpheusvm", vmTemplate.Name())
+	require.Equal(filepath.Join(dir, "morpheusvm.yaml"), vmTemplate.Source)
+
+	genesis, err := vmTemplate.CreateGenesis(map[string]string{"owner": "morpheus1abc"})
+	require.NoError(err)
+	require.Equal(`{"customAllocation":[{"address":"morpheus1abc","balance":1000000}]}`, string(genesis))
+
+	_, err = vmTemplate.CreateGenesis(nil)
+	require.ErrorContains(err, `requires a value for param "owner"`)
+	_, err = vmTemplate.CreateGenesis(map[string]string{"owner": "a", "supply": "-1"})
+	require.ErrorContains(err, "non negative integer")
+
+	chainConfig, err := vmTemplate.DefaultChainConfig()
+	require.NoError(err)
+	require.JSONEq(`{"mempoolSize":10000000}`, string(chainConfig))
+}
+
+func TestNewManifestVMTemplateErrors(t *testing.T) {
+	github := &models.VMTemplateGitHubRelease{Org: "o", Repo: "r", Asset: "vm.tar.gz", Binary: "vm"}
+	build := &models.VMTemplateBuild{Repo: "https://github.com/o/r", Script: "scripts/build.sh"}
+	genesis := models.VMTemplateGenesis{Template: "{}"}
+	tests := []struct {
+		name     string
+		manifest models.VMTemplateManifest
+		err      string
+	}{
+		{
+			name:     "builtin name",
+			manifest: models.VMTemplateManifest{Name: models.CustomVM, Binary: models.VMTemplateBinary{GitHub: github}, Genesis: genesis},
+			err:      "reserved for a builtin VM",
+		},
+		{
+			name:     "invalid name",
+			manifest: models.VMTemplateManifest{Name: "my vm", Binary: models.VMTemplateBinary{GitHub: github}, Genesis: genesis},
+			err:      "must start with a letter or digit",
+		},
+		{
+			name:     "no binary",
+			manifest: models.VMTemplateManifest{Name: "vm", Genesis: genesis},
+			err:      "binary must have a github or a build section",
+		},
+		{
+			name:     "two binaries",
+			manifest: models.VMTemplateManifest{Name: "vm", Binary: models.VMTemplateBinary{GitHub: github, Build: build}, Genesis: genesis},
+			err:      "not both",
+		},
+		{
+			name: "zip asset",
+			manifest: models.VMTemplateManifest{Name: "vm", Binary: models.VMTemplateBinary{GitHub: &models.VMTemplateGitHubRelease{
+				Org: "o", Repo: "r", Asset: "vm.zip", Binary: "vm",
+			}}, Genesis: genesis},
+			err: "must be a tar.gz archive",
+		},
+		{
+			name:     "no build script",
+			manifest: models.VMTemplateManifest{Name: "vm", Binary: models.VMTemplateBinary{Build: &models.VMTemplateBuild{Repo: "r"}}, Genesis: genesis},
+			err:      "needs a repo and a script",
+		},
+		{
+			name:     "no genesis",
+			manifest: models.VMTemplateManifest{Name: "vm", Binary: models.VMTemplateBinary{Build: build}},
+			err:      "genesis.template is required",
+		},
+		{
+			name: "invalid default",
+			manifest: models.VMTemplateManifest{Name: "vm", Binary: models.VMTemplateBinary{Build: build}, Genesis: models.VMTemplateGenesis{
+				Template: "{}",
+				Params:   []models.TemplateParam{{Name: "admin", Type: "address", Default: stringPtr("nope")}},
+			}},
+			err: "must be an address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := setupTest(t)
+			_, err := NewManifestVMTemplate(tt.manifest, "test")
+			require.ErrorContains(err, tt.err)
+		})
+	}
+}
+
+func TestSetupVMTemplateSidecar(t *testing.T) {
+	require := setupTest(t)
+	vmTemplate := &testVMTemplate{versions: []string{"v0.2.0", "v0.1.0"}}
+	sc := &models.Sidecar{Name: "test"}
+	require.NoError(SetupVMTemplateSidecar(nil, sc, vmTemplate, ""))
+	require.Equal(models.VMType("testvm"), sc.VM)
+	require.Equal("v0.2.0", sc.VMVersion)
+	require.Equal(35, sc.RPCVersion)
+	require.Nil(sc.VMTemplate)
+	require.Equal([]string{"v0.2.0"}, vmTemplate.built)
+
+	require.NoError(SetupVMTemplateSidecar(nil, sc, vmTemplate, "v0.1.0"))
+	require.Equal("v0.1.0", sc.VMVersion)
+}
+
+func TestRegisterVMTemplate(t *testing.T) {
+	require := setupTest(t)
+	vmTemplate := &testVMTemplate{}
+	require.NoError(RegisterVMTemplate(vmTemplate))
+	defer delete(registeredVMTemplates, vmTemplate.Name())
+	require.ErrorContains(RegisterVMTemplate(vmTemplate), "already registered")
+}
+
+func stringPtr(s string) *string {
+	return &s
+}